@@ -0,0 +1,454 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	encjson "encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+// Attachment field constants for type-safe queries
+const (
+	AttachmentFieldID           = "id"
+	AttachmentFieldClassName    = "class_name"
+	AttachmentFieldCode         = "code"
+	AttachmentFieldName         = "name"
+	AttachmentFieldParentID     = "parent_id" // task, comment or document
+	AttachmentFieldURL          = "url"
+	AttachmentFieldSize         = "size"
+	AttachmentFieldMimeType     = "mimetype"
+	AttachmentFieldCmfOwnerID   = "cmf_owner_id"
+	AttachmentFieldCmfCreatedAt = "cmf_created_at"
+
+	// attachmentWriteParent is the CmfAttachment.create/update kwarg carrying
+	// the parent object ID; OAS kwargs are {name, parent}.
+	attachmentWriteParent = "parent"
+
+	// attachmentUploadKwargs and attachmentUploadFile are the multipart
+	// fields of an upload: the JSON-RPC kwargs and the file content.
+	attachmentUploadKwargs = "kwargs"
+	attachmentUploadFile   = "file"
+
+	// maxStreamErrorBody caps the error body read from a failed stream.
+	maxStreamErrorBody = 64 << 10
+)
+
+var (
+	// DefaultAttachmentFields - standard projection for attachment queries
+	DefaultAttachmentFields = []string{
+		AttachmentFieldID,
+		AttachmentFieldClassName,
+		AttachmentFieldCode,
+		AttachmentFieldName,
+		AttachmentFieldParentID,
+		AttachmentFieldURL,
+		AttachmentFieldSize,
+		AttachmentFieldMimeType,
+		AttachmentFieldCmfOwnerID,
+		AttachmentFieldCmfCreatedAt,
+	}
+)
+
+// WithStreamHTTPClient sets the HTTP client of file uploads and downloads,
+// which bypass the JSON-RPC pipeline (middlewares, retries, cache) to stream
+// their bodies. Defaults to a client sharing the transport of the JSON-RPC
+// client, without its timeout: bound streams with the ctx.
+func WithStreamHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.streamClient = hc
+	}
+}
+
+// Attachment retrieves a single attachment by ID
+// Example:
+//
+//	att, meta, err := client.Attachment(ctx, "CmfAttachment:uuid", nil)
+func (c *Client) Attachment(
+	ctx context.Context,
+	attachmentID string,
+	fields []string,
+) (*models.Attachment, *models.Meta, error) {
+	qb := NewQueryBuilder().
+		Select(fields...).
+		From(EntityAttachment).
+		Where(sq.Eq{AttachmentFieldID: attachmentID}).
+		Limit(1)
+
+	return c.AttachmentQuery(ctx, qb)
+}
+
+// AttachmentQuery executes query using REAL Squirrel API
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  Select("id", "name", "url").
+//	  From(evateamclient.EntityAttachment).
+//	  Where(sq.Eq{"code": "ATT-000001"})
+//	att, meta, err := client.AttachmentQuery(ctx, qb)
+func (c *Client) AttachmentQuery(ctx context.Context, qb *QueryBuilder) (*models.Attachment, *models.Meta, error) {
+	kwargs, err := qb.ToKwargs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, hasFields := kwargs["fields"]; !hasFields {
+		kwargs["fields"] = DefaultAttachmentFields
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfAttachment.get",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp models.AttachmentResponse
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, nil, err
+	}
+
+	return &resp.Result, &resp.Meta, nil
+}
+
+// AttachmentsList retrieves list using REAL Squirrel
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityAttachment).
+//	  Where(sq.Eq{"parent_id": "CmfTask:uuid"}).
+//	  OrderBy("-cmf_created_at")
+//	atts, meta, err := client.AttachmentsList(ctx, qb)
+func (c *Client) AttachmentsList(
+	ctx context.Context,
+	qb *QueryBuilder,
+) ([]models.Attachment, *models.Meta, error) {
+	kwargs, err := qb.From(EntityAttachment).ToKwargs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, hasFields := kwargs["fields"]; !hasFields {
+		kwargs["fields"] = DefaultAttachmentFields
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfAttachment.list",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp models.AttachmentListResponse
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, nil, err
+	}
+
+	return resp.Result, &resp.Meta, nil
+}
+
+// AttachmentCount counts using REAL Squirrel
+// Example:
+//
+//	count, err := client.AttachmentCount(ctx, evateamclient.NewQueryBuilder().
+//	  Where(sq.Eq{"parent_id": "CmfTask:uuid"}))
+func (c *Client) AttachmentCount(ctx context.Context, qb *QueryBuilder) (int, error) {
+	return Count(ctx, c, qb.From(EntityAttachment))
+}
+
+// Attachments retrieves ALL attachments of a task, comment or document, the
+// oldest first.
+// Example:
+//
+//	atts, meta, err := client.Attachments(ctx, "CmfTask:uuid")
+func (c *Client) Attachments(ctx context.Context, parentID string) ([]models.Attachment, *models.Meta, error) {
+	qb := NewQueryBuilder().
+		From(EntityAttachment).
+		Where(sq.Eq{AttachmentFieldParentID: parentID}).
+		OrderBy(AttachmentFieldCmfCreatedAt)
+
+	return c.AttachmentsList(ctx, qb)
+}
+
+// AttachmentCreate uploads the content of r as a file named name attached
+// to parentID (a task, comment or document ID).
+//
+// The request is streamed as multipart/form-data to CmfAttachment.create:
+// the JSON-RPC kwargs {name, parent} in the "kwargs" field and the content
+// in the "file" field, so memory use does not depend on the file size. r is
+// read once, hence the upload is not retried.
+// Example:
+//
+//	f, err := os.Open("crash.log")
+//	...
+//	defer f.Close()
+//	att, err := client.AttachmentCreate(ctx, "CmfTask:uuid", "crash.log", f)
+func (c *Client) AttachmentCreate(
+	ctx context.Context,
+	parentID string,
+	name string,
+	r io.Reader,
+) (*models.Attachment, error) {
+	if parentID == "" {
+		return nil, errors.New("parentID is required")
+	}
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	const method = "CmfAttachment.create"
+	defer c.cacheInvalidate(ctx, method)
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeAttachmentForm(mw, parentID, name, r))
+	}()
+	defer pr.Close()
+
+	resp, err := c.stream(ctx, http.MethodPost, c.requestURL(&RPCRequest{Method: method}), method,
+		mw.FormDataContentType(), pr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp struct {
+		JSONRPC string             `json:"jsonrpc"`
+		Result  encjson.RawMessage `json:"result"`
+		Error   *RPCError          `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, errors.WithMessage(err, "unmarshal response body")
+	}
+	if rpcResp.Error != nil {
+		return nil, errors.WithStack(newRPCError(method, "", resp.StatusCode, rpcResp.Error, nil))
+	}
+
+	return parseWriteResult(ctx, rpcResp.Result, method, c.attachmentByID, attachmentHasEmptyID)
+}
+
+// writeAttachmentForm writes the multipart upload form and closes it.
+func writeAttachmentForm(mw *multipart.Writer, parentID, name string, r io.Reader) error {
+	kwargs, err := json.Marshal(map[string]any{
+		AttachmentFieldName:   name,
+		attachmentWriteParent: parentID,
+	})
+	if err != nil {
+		return err
+	}
+	if err := mw.WriteField(attachmentUploadKwargs, string(kwargs)); err != nil {
+		return err
+	}
+	part, err := mw.CreateFormFile(attachmentUploadFile, name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return errors.WithMessage(err, "read attachment content")
+	}
+
+	return mw.Close()
+}
+
+// AttachmentUpdateParams holds the attachment fields to change; empty
+// fields are left as is.
+type AttachmentUpdateParams struct {
+	// Name renames the file.
+	Name string
+	// ParentID moves the attachment to another task, comment or document.
+	ParentID string
+}
+
+// AttachmentUpdate renames or moves an attachment
+// Example:
+//
+//	att, err := client.AttachmentUpdate(ctx, "CmfAttachment:uuid", evateamclient.AttachmentUpdateParams{
+//	  Name: "crash-2026-10-16.log",
+//	})
+func (c *Client) AttachmentUpdate(
+	ctx context.Context,
+	attachmentID string,
+	params AttachmentUpdateParams,
+) (*models.Attachment, error) {
+	if attachmentID == "" {
+		return nil, errors.New("attachmentID is required")
+	}
+
+	kwargs := map[string]any{}
+	if params.Name != "" {
+		kwargs[AttachmentFieldName] = params.Name
+	}
+	if params.ParentID != "" {
+		kwargs[attachmentWriteParent] = params.ParentID
+	}
+	if len(kwargs) == 0 {
+		return nil, errors.New("nothing to update")
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfAttachment.update",
+		CallID:  newCallID(),
+		Args:    []any{attachmentID},
+		Kwargs:  kwargs,
+	}
+
+	var resp struct {
+		JSONRPC string             `json:"jsonrpc"`
+		Result  encjson.RawMessage `json:"result"`
+	}
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+
+	return parseWriteResult(ctx, resp.Result, "CmfAttachment.update", c.attachmentByID, attachmentHasEmptyID)
+}
+
+// AttachmentDelete deletes an attachment by ID
+// Example:
+//
+//	err := client.AttachmentDelete(ctx, "CmfAttachment:uuid")
+func (c *Client) AttachmentDelete(ctx context.Context, attachmentID string) error {
+	if attachmentID == "" {
+		return errors.New("attachmentID is required")
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfAttachment.delete",
+		CallID:  newCallID(),
+		Args:    []any{attachmentID},
+	}
+
+	var resp struct {
+		JSONRPC string `json:"jsonrpc"`
+		Result  any    `json:"result"`
+	}
+
+	return c.doRequest(ctx, reqBody, &resp)
+}
+
+// AttachmentDownload streams the content of an attachment to w, fetched
+// from its url, and returns the number of bytes written. Memory use does
+// not depend on the file size.
+// Example:
+//
+//	f, err := os.Create("crash.log")
+//	...
+//	defer f.Close()
+//	n, err := client.AttachmentDownload(ctx, "CmfAttachment:uuid", f)
+func (c *Client) AttachmentDownload(ctx context.Context, attachmentID string, w io.Writer) (int64, error) {
+	att, _, err := c.Attachment(ctx, attachmentID, []string{AttachmentFieldID, AttachmentFieldURL})
+	if err != nil {
+		return 0, err
+	}
+	if att.ID == "" {
+		return 0, errors.Wrapf(ErrNotFound, "attachment %s", attachmentID)
+	}
+	if att.URL == "" {
+		return 0, errors.Errorf("attachment %s has no url", attachmentID)
+	}
+
+	return c.download(ctx, att.URL, "CmfAttachment.download", w)
+}
+
+// download streams the body of a GET of rawURL, absolute or relative to
+// the server, to w.
+func (c *Client) download(ctx context.Context, rawURL, method string, w io.Writer) (int64, error) {
+	ref, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, errors.WithMessagef(err, "%s: parse url", method)
+	}
+
+	resp, err := c.stream(ctx, http.MethodGet, c.baseURL.ResolveReference(ref).String(), method, "", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, errors.WithMessagef(err, "%s: copy body", method)
+	}
+
+	return n, nil
+}
+
+// stream sends an HTTP request whose body or response is streamed instead
+// of buffered, bypassing the JSON-RPC pipeline. The API token is sent only
+// to the scheme and host of the base URL. A non-2xx response is returned as
+// *APIError; otherwise the caller closes the response body.
+func (c *Client) stream(
+	ctx context.Context,
+	httpMethod string,
+	rawURL string,
+	method string,
+	contentType string,
+	body io.Reader,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, httpMethod, rawURL, body)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "%s: new request", method)
+	}
+	// Attachment URLs may point to a CDN or object storage: the token is
+	// only for the EVA server itself.
+	if sameOrigin(req.URL, c.baseURL) {
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "%s", method)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxStreamErrorBody))
+		return nil, errors.WithStack(newHTTPError(method, "", resp.StatusCode, msg))
+	}
+
+	return resp, nil
+}
+
+// sameOrigin reports whether u has the scheme and host of base.
+func sameOrigin(u, base *url.URL) bool {
+	return strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host)
+}
+
+// attachmentByID fetches an attachment by ID or code, for the follow-up
+// `.get` when CmfAttachment.create/update returns a bare string.
+func (c *Client) attachmentByID(ctx context.Context, idOrCode string) (*models.Attachment, error) {
+	field := AttachmentFieldCode
+	if strings.Contains(idOrCode, ":") {
+		field = AttachmentFieldID
+	}
+
+	qb := NewQueryBuilder().
+		From(EntityAttachment).
+		Where(sq.Eq{field: idOrCode}).
+		Limit(1)
+
+	att, _, err := c.AttachmentQuery(ctx, qb)
+	return att, err
+}
+
+func attachmentHasEmptyID(att *models.Attachment) bool {
+	return att == nil || att.ID == ""
+}
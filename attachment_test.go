@@ -0,0 +1,192 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAttachmentServer serves CmfAttachment.create uploads into files,
+// CmfAttachment.get of CmfAttachment:1 and the download of its url.
+func newAttachmentServer(t *testing.T, files map[string]string) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.URL.Query().Get("m") == "CmfAttachment.create":
+			assert.Contains(t, r.Header.Get("Content-Type"), "multipart/form-data")
+			f, header, err := r.FormFile("file")
+			if err != nil {
+				// An upload aborted by the client arrives truncated.
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			assert.Equal(t, `{"name":"crash.log","parent":"CmfTask:1"}`, r.FormValue("kwargs"))
+			content, _ := io.ReadAll(f)
+			files[header.Filename] = string(content)
+			_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": {"id": "CmfAttachment:1", "name": "crash.log"}}`))
+		case r.URL.Query().Get("m") == "CmfAttachment.get":
+			_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": {"id": "CmfAttachment:1", "url": "/media/1/crash.log"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/media/1/crash.log":
+			_, _ = w.Write([]byte(files["crash.log"]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&Config{BaseURL: srv.URL, APIToken: "test-token"})
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestClient_AttachmentCreate_StreamsMultipart(t *testing.T) {
+	files := map[string]string{}
+	client := newAttachmentServer(t, files)
+	content := strings.Repeat("panic: boom\n", 100_000)
+
+	// io.MultiReader hides the size of the content: it must be streamed.
+	att, err := client.AttachmentCreate(testCtx, "CmfTask:1", "crash.log", io.MultiReader(strings.NewReader(content)))
+
+	require.NoError(t, err)
+	assert.Equal(t, "CmfAttachment:1", att.ID)
+	assert.Equal(t, content, files["crash.log"])
+}
+
+func TestClient_AttachmentCreate_Validation(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+
+	_, err := client.AttachmentCreate(testCtx, "", "a.txt", strings.NewReader("a"))
+	assert.ErrorContains(t, err, "parentID is required")
+
+	_, err = client.AttachmentCreate(testCtx, "CmfTask:1", "", strings.NewReader("a"))
+	assert.ErrorContains(t, err, "name is required")
+
+	assert.Zero(t, mockHTTP.calls)
+}
+
+func TestClient_AttachmentCreate_ReaderError(t *testing.T) {
+	client := newAttachmentServer(t, map[string]string{})
+
+	_, err := client.AttachmentCreate(testCtx, "CmfTask:1", "crash.log",
+		io.MultiReader(strings.NewReader("partial"), errReader{}))
+
+	require.Error(t, err)
+}
+
+func TestClient_AttachmentDownload(t *testing.T) {
+	client := newAttachmentServer(t, map[string]string{"crash.log": "panic: boom"})
+
+	var buf bytes.Buffer
+	n, err := client.AttachmentDownload(testCtx, "CmfAttachment:1", &buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+	assert.Equal(t, "panic: boom", buf.String())
+}
+
+func TestClient_AttachmentDownload_OtherHostGetsNoToken(t *testing.T) {
+	var auth []string
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("panic: boom"))
+	}))
+	defer cdn.Close()
+	eva := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": {"id": "CmfAttachment:1", "url": "` + cdn.URL + `/1/crash.log"}}`))
+	}))
+	defer eva.Close()
+	client, err := NewClient(&Config{BaseURL: eva.URL, APIToken: "test-token"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = client.AttachmentDownload(testCtx, "CmfAttachment:1", &buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, "panic: boom", buf.String())
+	assert.Equal(t, []string{""}, auth)
+}
+
+func TestClient_AttachmentDownload_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("m") == "CmfAttachment.get" {
+			_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": {"id": "CmfAttachment:1", "url": "/media/gone"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+	client, err := NewClient(&Config{BaseURL: srv.URL, APIToken: "test-token"})
+	require.NoError(t, err)
+
+	_, err = client.AttachmentDownload(testCtx, "CmfAttachment:1", io.Discard)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestClient_AttachmentDownload_NotFound(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": null}`)
+
+	_, err := client.AttachmentDownload(testCtx, "CmfAttachment:404", io.Discard)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_Attachments(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": [
+		{"id": "CmfAttachment:1", "name": "a.png", "size": 2048, "mimetype": "image/png"}
+	]}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfAttachment.list")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"filter":["parent_id","==","CmfTask:1"]`) &&
+			assert.Contains(t, string(body), `"order_by":["cmf_created_at"]`)
+	}
+
+	atts, _, err := client.Attachments(testCtx, "CmfTask:1")
+
+	require.NoError(t, err)
+	require.Len(t, atts, 1)
+	assert.Equal(t, int64(2048), atts[0].Size)
+	assert.Equal(t, "image/png", atts[0].MimeType)
+}
+
+func TestClient_AttachmentDelete(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": true}`)
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"method":"CmfAttachment.delete"`) &&
+			assert.Contains(t, string(body), `"args":["CmfAttachment:1"]`)
+	}
+
+	require.NoError(t, client.AttachmentDelete(testCtx, "CmfAttachment:1"))
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
@@ -0,0 +1,216 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+// Audit field constants for type-safe queries.
+//
+// The OAS (doc/oas_evateam_v1_9_22.json) documents no CmfAudit fields but
+// code and cmf_created_at. id, class_name, parent_id and cmf_owner_id are
+// the fields every CMF model has. The audit-specific names below are
+// unverified: they are not in the OAS and have not been checked against a
+// live server, so a query filtering on them may match nothing.
+const (
+	// Core fields
+	AuditFieldID        = "id"
+	AuditFieldClassName = "class_name"
+	AuditFieldCode      = "code"
+
+	// Relations
+	AuditFieldParentID = "parent_id" // changed object
+
+	// System
+	AuditFieldCmfOwnerID   = "cmf_owner_id" // person who made the change
+	AuditFieldCmfCreatedAt = "cmf_created_at"
+
+	// Unverified, see above
+	AuditFieldAction    = "action"     // create, update, delete
+	AuditFieldProjectID = "project_id" // project context
+	AuditFieldFieldName = "field_name" // changed field
+	AuditFieldOldValue  = "old_value"  // value before the change
+	AuditFieldNewValue  = "new_value"  // value after the change
+)
+
+var (
+	// DefaultAuditFields - standard projection for audit queries: all basic
+	// fields, since the OAS does not list them and a projection naming an
+	// unknown field would fail.
+	DefaultAuditFields = []string{"*"} // AllBasicFields
+)
+
+// Audit retrieves a single audit record by ID
+// Example:
+//
+//	record, meta, err := client.Audit(ctx, "CmfAudit:uuid", nil)
+func (c *Client) Audit(
+	ctx context.Context,
+	auditID string,
+	fields []string,
+) (*models.Audit, *models.Meta, error) {
+	qb := NewQueryBuilder().
+		Select(fields...).
+		From(EntityAudit).
+		Where(sq.Eq{AuditFieldID: auditID}).
+		Limit(1)
+
+	return c.AuditQuery(ctx, qb)
+}
+
+// AuditQuery executes query using QueryBuilder
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  Select("id", "field_name", "old_value", "new_value").
+//	  From(evateamclient.EntityAudit).
+//	  Where(sq.Eq{"code": "AUD-000001"})
+//	record, meta, err := client.AuditQuery(ctx, qb)
+func (c *Client) AuditQuery(ctx context.Context, qb *QueryBuilder) (*models.Audit, *models.Meta, error) {
+	kwargs, err := qb.ToKwargs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, hasFields := kwargs["fields"]; !hasFields {
+		kwargs["fields"] = DefaultAuditFields
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfAudit.get",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp models.AuditResponse
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get audit record")
+	}
+
+	return &resp.Result, &resp.Meta, nil
+}
+
+// AuditList retrieves list using QueryBuilder
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityAudit).
+//	  Where(sq.Eq{"parent_id": "CmfTask:uuid"}).
+//	  OrderBy("-cmf_created_at").
+//	  Limit(100)
+//	records, meta, err := client.AuditList(ctx, qb)
+func (c *Client) AuditList(ctx context.Context, qb *QueryBuilder) ([]models.Audit, *models.Meta, error) {
+	kwargs, err := qb.From(EntityAudit).ToKwargs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, hasFields := kwargs["fields"]; !hasFields {
+		kwargs["fields"] = DefaultAuditFields
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfAudit.list",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp models.AuditListResponse
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get audit list")
+	}
+
+	return resp.Result, &resp.Meta, nil
+}
+
+// AuditCount counts audit records using QueryBuilder
+// Example:
+//
+//	count, err := client.AuditCount(ctx, evateamclient.NewQueryBuilder().
+//	  Where(sq.Eq{"cmf_owner_id": "CmfPerson:uuid"}))
+func (c *Client) AuditCount(ctx context.Context, qb *QueryBuilder) (int, error) {
+	count, err := Count(ctx, c, qb.From(EntityAudit))
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get audit count")
+	}
+
+	return count, nil
+}
+
+// ObjectAuditQuery returns the query of the change history of an object
+// (a task, document, project, ...), the oldest change first.
+func ObjectAuditQuery(objectID string) *QueryBuilder {
+	return NewQueryBuilder().
+		From(EntityAudit).
+		Where(sq.Eq{AuditFieldParentID: objectID}).
+		OrderBy(AuditFieldCmfCreatedAt, AuditFieldID)
+}
+
+// PersonAuditQuery returns the query of the changes made by a person in
+// [from, to), the oldest change first. A zero from or to leaves that end
+// open.
+func PersonAuditQuery(personID string, from, to time.Time) *QueryBuilder {
+	qb := NewQueryBuilder().
+		From(EntityAudit).
+		Where(sq.Eq{AuditFieldCmfOwnerID: personID}).
+		OrderBy(AuditFieldCmfCreatedAt, AuditFieldID)
+	if !from.IsZero() {
+		qb.Where(sq.GtOrEq{AuditFieldCmfCreatedAt: from.UTC()})
+	}
+	if !to.IsZero() {
+		qb.Where(sq.Lt{AuditFieldCmfCreatedAt: to.UTC()})
+	}
+
+	return qb
+}
+
+// ObjectAuditHistory iterates over the change history of an object, the
+// oldest change first.
+// Example:
+//
+//	for record, err := range client.ObjectAuditHistory(ctx, "CmfTask:uuid") {
+//	  if err != nil {
+//	    return err
+//	  }
+//	  fmt.Println(record.CmfCreatedAt, record.CmfOwnerID, record.FieldName, record.OldValue, record.NewValue)
+//	}
+func (c *Client) ObjectAuditHistory(
+	ctx context.Context,
+	objectID string,
+	opts ...IterOption,
+) iter.Seq2[models.Audit, error] {
+	return c.IterAudit(ctx, ObjectAuditQuery(objectID), opts...)
+}
+
+// PersonAuditChanges iterates over everything a person changed in
+// [from, to), the oldest change first. A zero from or to leaves that end
+// open.
+// Example:
+//
+//	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+//	for record, err := range client.PersonAuditChanges(ctx, "CmfPerson:uuid", from, from.AddDate(0, 3, 0)) {
+//	  ...
+//	}
+func (c *Client) PersonAuditChanges(
+	ctx context.Context,
+	personID string,
+	from, to time.Time,
+	opts ...IterOption,
+) iter.Seq2[models.Audit, error] {
+	return c.IterAudit(ctx, PersonAuditQuery(personID, from, to), opts...)
+}
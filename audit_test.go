@@ -0,0 +1,107 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The fixture is synthetic: the names of the unverified audit fields are not
+// confirmed by a recorded server response.
+func TestClient_AuditList(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": [
+		{"id": "CmfAudit:1", "action": "update", "parent_id": "CmfTask:1", "field_name": "priority",
+		 "old_value": 1, "new_value": 3, "cmf_owner_id": "CmfPerson:1", "cmf_created_at": "2026-03-01T10:00:00Z"}
+	]}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfAudit.list")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"fields":["*"]`)
+	}
+
+	records, _, err := client.AuditList(testCtx, NewQueryBuilder().Where(sq.Eq{AuditFieldParentID: "CmfTask:1"}))
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "priority", records[0].FieldName)
+	assert.Equal(t, float64(1), records[0].OldValue)
+	assert.Equal(t, float64(3), records[0].NewValue)
+	assert.Equal(t, "CmfPerson:1", records[0].CmfOwnerID)
+	require.NotNil(t, records[0].CmfCreatedAt)
+	assert.Equal(t, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), records[0].CmfCreatedAt.UTC())
+}
+
+func TestClient_AuditCount(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": 42}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfAudit.count")
+	}
+
+	count, err := client.AuditCount(testCtx, NewQueryBuilder())
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
+
+func TestClient_ObjectAuditHistory_Paginates(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": [{"id": "CmfAudit:1"}, {"id": "CmfAudit:2"}]}`),
+		mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": [{"id": "CmfAudit:3"}]}`),
+	}
+	var bodies []string
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		bodies = append(bodies, string(body))
+		return true
+	}
+
+	var ids []string
+	for record, err := range client.ObjectAuditHistory(testCtx, "CmfTask:1", PageSize(2)) {
+		require.NoError(t, err)
+		ids = append(ids, record.ID)
+	}
+
+	assert.Equal(t, []string{"CmfAudit:1", "CmfAudit:2", "CmfAudit:3"}, ids)
+	require.Len(t, bodies, 2)
+	assert.Contains(t, bodies[0], `"filter":["parent_id","==","CmfTask:1"]`)
+	assert.Contains(t, bodies[0], `"order_by":["cmf_created_at","id"]`)
+	assert.Contains(t, bodies[0], `"slice":[0,2]`)
+	assert.Contains(t, bodies[1], `"slice":[2,4]`)
+}
+
+func TestPersonAuditQuery(t *testing.T) {
+	from := time.Date(2026, 1, 1, 3, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	kwargs, err := PersonAuditQuery("CmfPerson:1", from, to).ToKwargs()
+
+	require.NoError(t, err)
+	body, err := json.Marshal(kwargs["filter"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		["cmf_owner_id", "==", "CmfPerson:1"],
+		["cmf_created_at", ">=", "2026-01-01T00:00:00Z"],
+		["cmf_created_at", "<", "2026-04-01T00:00:00Z"]
+	]`, string(body))
+
+	kwargs, err = PersonAuditQuery("CmfPerson:1", time.Time{}, time.Time{}).ToKwargs()
+
+	require.NoError(t, err)
+	assert.Equal(t, []any{"cmf_owner_id", "==", "CmfPerson:1"}, kwargs["filter"])
+}
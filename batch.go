@@ -0,0 +1,464 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	encjson "encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+const defaultBatchConcurrency = 4
+
+var (
+	ErrBatchNotExecuted = errors.New("batch is not executed")
+	errBatchRejected    = errors.New("batch rejected by server")
+)
+
+// batchRejectStatuses are the HTTP statuses a server without JSON-RPC batch
+// support answers a batch with.
+var batchRejectStatuses = []int{
+	http.StatusBadRequest,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusUnsupportedMediaType,
+	http.StatusUnprocessableEntity,
+	http.StatusNotImplemented,
+}
+
+// Batch queues RPC calls and sends them as a single JSON-RPC batch — one HTTP
+// round-trip instead of one per call. Responses are correlated by call ID.
+//
+// When the server rejects batches, the calls are transparently sent one by
+// one with bounded concurrency, and the client remembers not to try batching
+// again.
+//
+// Example:
+//
+//	b := client.Batch(ctx)
+//	task := evateamclient.BatchGet[models.Task](b, evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityTask).Where(sq.Eq{"code": "PROJ-1"}))
+//	open := evateamclient.BatchCount(b, evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityTask).Where(sq.Eq{"cache_status_type": "OPEN"}))
+//	if err := b.Do(); err != nil {
+//	  return err
+//	}
+//	t, _, err := task.Result()
+//	n, _, err := open.Result()
+type Batch struct {
+	client      *Client
+	ctx         context.Context
+	calls       []batchCall
+	concurrency int
+}
+
+// batchCall is the type-erased view of a BatchCall.
+type batchCall interface {
+	request() *RPCRequest
+	executed() bool
+	resolve(raw []byte, statusCode int)
+	fail(err error)
+}
+
+// Batch creates an empty batch bound to ctx.
+func (c *Client) Batch(ctx context.Context) *Batch {
+	return &Batch{
+		client:      c,
+		ctx:         ctx,
+		concurrency: defaultBatchConcurrency,
+	}
+}
+
+// Concurrency sets how many single calls run in parallel when the server
+// rejects batches. Defaults to 4.
+func (b *Batch) Concurrency(n int) *Batch {
+	if n > 0 {
+		b.concurrency = n
+	}
+	return b
+}
+
+// Len returns the number of queued calls.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Do sends all queued calls. The returned error reports a failure of the
+// whole batch (e.g. a transport error); per-call errors are returned by
+// BatchCall.Result.
+func (b *Batch) Do() error {
+	const skip = 2
+	fname := functionName(skip)
+
+	pending := make([]batchCall, 0, len(b.calls))
+	for _, call := range b.calls {
+		if call.executed() {
+			continue
+		}
+		if err := b.client.validateWrite(b.ctx, call.request()); err != nil {
+			call.fail(err)
+			continue
+		}
+		pending = append(pending, call)
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+	defer func() {
+		for _, call := range pending {
+			b.client.cacheInvalidate(b.ctx, call.request().Method)
+		}
+	}()
+
+	if len(pending) == 1 || b.client.batchUnsupported.Load() {
+		return b.doSingle(pending, fname)
+	}
+
+	err := b.doBatch(pending, fname)
+	if errors.Is(err, errBatchRejected) {
+		b.client.batchUnsupported.Store(true)
+		b.client.logDebug(b.ctx, "Batch rejected, falling back to single calls", "func", fname, "error", err)
+
+		return b.doSingle(pending, fname)
+	}
+
+	return err
+}
+
+// batchResponse is a single element of a JSON-RPC batch response.
+type batchResponse struct {
+	CallID string             `json:"callid"`
+	Result encjson.RawMessage `json:"result"`
+	Meta   *models.Meta       `json:"meta,omitempty"`
+	Error  *RPCError          `json:"error,omitempty"`
+}
+
+func (b *Batch) doBatch(calls []batchCall, fname string) error {
+	reqs := make([]*RPCRequest, 0, len(calls))
+	byCallID := make(map[string]batchCall, len(calls))
+	for _, call := range calls {
+		reqs = append(reqs, call.request())
+		byCallID[call.request().CallID] = call
+	}
+
+	body := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  BatchMethod,
+		CallID:  newCallID(),
+		Calls:   reqs,
+	}
+
+	raw, statusCode, err := b.client.roundTrip(b.ctx, body, fname)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && slices.Contains(batchRejectStatuses, apiErr.StatusCode) {
+			return errors.WithMessage(errBatchRejected, err.Error())
+		}
+		for _, call := range calls {
+			call.fail(err)
+		}
+
+		return err
+	}
+
+	var items []encjson.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return b.batchNotArray(calls, body.CallID, raw, statusCode)
+	}
+
+	resolved := make(map[string]bool, len(calls))
+	for i, item := range items {
+		var resp batchResponse
+		if err := json.Unmarshal(item, &resp); err != nil {
+			continue
+		}
+
+		call, ok := byCallID[resp.CallID]
+		if !ok && resp.CallID == "" && i < len(calls) {
+			// No call ID echoed back: fall back to positional correlation.
+			call, ok = calls[i], true
+		}
+		if !ok || resolved[call.request().CallID] {
+			continue
+		}
+
+		resolved[call.request().CallID] = true
+		call.resolve(item, statusCode)
+	}
+
+	for _, call := range calls {
+		if !resolved[call.request().CallID] {
+			req := call.request()
+			call.fail(errors.Errorf("no response for %s call %s in batch", req.Method, req.CallID))
+		}
+	}
+
+	return nil
+}
+
+// batchNotArray handles a batch answered with something else than an array.
+// A server without batch support answers with a single invalid-request or
+// method-not-found error object: the batch is rejected. Any other answer (an
+// auth or rate-limit error, ...) fails the calls of this batch only, and
+// batching stays enabled.
+func (b *Batch) batchNotArray(calls []batchCall, callID string, raw []byte, statusCode int) error {
+	var errResp rpcErrorResponse
+	if err := json.Unmarshal(raw, &errResp); err == nil && errResp.Error != nil {
+		switch errResp.Error.Code {
+		case rpcCodeInvalidRequest, rpcCodeMethodNotFound:
+			return errors.WithMessage(errBatchRejected, string(raw))
+		}
+
+		err := newRPCError(BatchMethod, callID, statusCode, errResp.Error, raw)
+		for _, call := range calls {
+			call.fail(err)
+		}
+
+		return err
+	}
+
+	err := errors.Errorf("unexpected batch response: %s", raw)
+	for _, call := range calls {
+		call.fail(err)
+	}
+
+	return err
+}
+
+func (b *Batch) doSingle(calls []batchCall, fname string) error {
+	sem := make(chan struct{}, b.concurrency)
+	var wg sync.WaitGroup
+
+	for i, call := range calls {
+		select {
+		case sem <- struct{}{}:
+		case <-b.ctx.Done():
+		}
+		if err := b.ctx.Err(); err != nil {
+			for _, call := range calls[i:] {
+				call.fail(err)
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(call batchCall) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			raw, statusCode, err := b.client.roundTrip(b.ctx, call.request(), fname)
+			if err != nil {
+				call.fail(err)
+				return
+			}
+			call.resolve(raw, statusCode)
+		}(call)
+	}
+	wg.Wait()
+
+	return b.ctx.Err()
+}
+
+// BatchCall is a call queued in a Batch. Its result is available once
+// Batch.Do returns.
+type BatchCall[T any] struct {
+	req    *RPCRequest
+	decode func(raw encjson.RawMessage) (T, error)
+	result T
+	meta   *models.Meta
+	err    error
+	done   bool
+}
+
+// Result returns the decoded result, the response meta (nil when the server
+// sent none) and the call error.
+func (c *BatchCall[T]) Result() (T, *models.Meta, error) {
+	if !c.done {
+		var zero T
+		return zero, nil, errors.WithStack(ErrBatchNotExecuted)
+	}
+
+	return c.result, c.meta, c.err
+}
+
+func (c *BatchCall[T]) request() *RPCRequest {
+	return c.req
+}
+
+func (c *BatchCall[T]) executed() bool {
+	return c.done
+}
+
+func (c *BatchCall[T]) resolve(raw []byte, statusCode int) {
+	c.done = true
+
+	var resp batchResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		c.err = errors.WithMessage(err, "unmarshal response body")
+		return
+	}
+	if resp.Error != nil {
+		c.err = errors.WithStack(newRPCError(c.req.Method, c.req.CallID, statusCode, resp.Error, raw))
+		return
+	}
+
+	c.meta = resp.Meta
+	if c.decode != nil {
+		c.result, c.err = c.decode(resp.Result)
+		return
+	}
+	if err := json.Unmarshal(resp.Result, &c.result); err != nil {
+		c.err = errors.WithMessagef(err, "unmarshal %s result", c.req.Method)
+	}
+}
+
+func (c *BatchCall[T]) fail(err error) {
+	c.done = true
+	c.err = err
+}
+
+// enqueue adds a call to the batch. A non-nil err marks the call as failed
+// upfront, e.g. when its query cannot be built; it is never sent.
+func enqueue[T any](b *Batch, req *RPCRequest, err error) *BatchCall[T] {
+	call := &BatchCall[T]{req: req}
+	if err != nil {
+		call.fail(err)
+	}
+	b.calls = append(b.calls, call)
+
+	return call
+}
+
+// BatchGet queues an <Entity>.get call built from qb.
+// Example:
+//
+//	call := evateamclient.BatchGet[models.Project](b, evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityProject).Where(sq.Eq{"code": "PROJ"}))
+func BatchGet[T any](b *Batch, qb *QueryBuilder) *BatchCall[T] {
+	req, err := queryRequest(qb, ".get")
+	return enqueue[T](b, req, err)
+}
+
+// BatchList queues an <Entity>.list call built from qb.
+// Example:
+//
+//	call := evateamclient.BatchList[models.TaskBrowse](b, evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityTask).Where(sq.Eq{"project_id": "CmfProject:uuid"}))
+func BatchList[T any](b *Batch, qb *QueryBuilder) *BatchCall[[]T] {
+	req, err := queryRequest(qb, ".list")
+	return enqueue[[]T](b, req, err)
+}
+
+// BatchCount queues an <Entity>.count call built from qb.
+// Example:
+//
+//	call := evateamclient.BatchCount(b, evateamclient.NewQueryBuilder().From(evateamclient.EntityTask))
+func BatchCount(b *Batch, qb *QueryBuilder) *BatchCall[int] {
+	req, err := queryRequest(qb, ".count")
+	return enqueue[int](b, req, err)
+}
+
+// BatchCreate queues an <entity>.create call. The result is the ID of the
+// created object, whether the server returned a bare ID or the object itself.
+// Example:
+//
+//	call := evateamclient.BatchCreate(b, evateamclient.EntityTask, map[string]any{
+//	  "name": "New Task", "parent": "CmfProject:uuid",
+//	})
+func BatchCreate(b *Batch, entity string, kwargs map[string]any) *BatchCall[string] {
+	req := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  entity + ".create",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	call := enqueue[string](b, req, nil)
+	call.decode = writeResultID(req.Method)
+
+	return call
+}
+
+// BatchUpdate queues an <entity>.update call of the object with the given ID.
+// The result is the ID of the updated object.
+// Example:
+//
+//	call := evateamclient.BatchUpdate(b, evateamclient.EntityTask, "CmfTask:uuid", map[string]any{
+//	  "priority": 5,
+//	})
+func BatchUpdate(b *Batch, entity, id string, kwargs map[string]any) *BatchCall[string] {
+	req := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  entity + ".update",
+		CallID:  newCallID(),
+		Args:    []any{id},
+		Kwargs:  kwargs,
+	}
+
+	var err error
+	if id == "" {
+		err = errors.New("id is required")
+	}
+	call := enqueue[string](b, req, err)
+	call.decode = writeResultID(req.Method)
+
+	return call
+}
+
+// queryRequest builds an RPC request for the entity of qb; suffix is the
+// method name with a leading dot, e.g. ".get".
+func queryRequest(qb *QueryBuilder, suffix string) (*RPCRequest, error) {
+	kwargs, err := qb.ToKwargs()
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := qb.ToMethod(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  strings.TrimSuffix(method, ".list") + suffix,
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}, nil
+}
+
+// writeResultID decodes a create/update result into the object ID. The
+// result is either a bare ID string or the object itself (see
+// parseWriteResult).
+func writeResultID(method string) func(raw encjson.RawMessage) (string, error) {
+	return func(raw encjson.RawMessage) (string, error) {
+		var id string
+		if err := json.Unmarshal(raw, &id); err == nil {
+			if id == "" {
+				return "", emptyResultErr(method)
+			}
+			return id, nil
+		}
+
+		var obj struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &obj); err != nil || obj.ID == "" {
+			return "", emptyResultErr(method)
+		}
+
+		return obj.ID, nil
+	}
+}
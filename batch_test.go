@@ -0,0 +1,372 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/imroc/req/v3"
+	"github.com/raoptimus/evateamclient.go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchServer is a fake HTTPClient answering batch and single requests.
+type batchServer struct {
+	mu sync.Mutex
+	// rejectBatch answers batch requests with HTTP 400.
+	rejectBatch bool
+	// batchReply, if set, answers batch requests with HTTP 200 and this body.
+	batchReply string
+	// reverse answers batch calls in reverse order.
+	reverse bool
+	// answer returns the response envelope of a single call.
+	answer func(call map[string]any) string
+
+	batches int
+	singles int
+}
+
+func (s *batchServer) Post(_ context.Context, body []byte, _ string) (*req.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.HasPrefix(string(body), "[") {
+		s.batches++
+		if s.rejectBatch {
+			return mockResponse(http.StatusBadRequest, "batch is not supported"), nil
+		}
+		if s.batchReply != "" {
+			return mockResponse(http.StatusOK, s.batchReply), nil
+		}
+
+		var calls []map[string]any
+		if err := json.Unmarshal(body, &calls); err != nil {
+			return nil, err
+		}
+		parts := make([]string, 0, len(calls))
+		for _, call := range calls {
+			parts = append(parts, s.answer(call))
+		}
+		if s.reverse {
+			for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+				parts[i], parts[j] = parts[j], parts[i]
+			}
+		}
+
+		return mockResponse(http.StatusOK, "["+strings.Join(parts, ",")+"]"), nil
+	}
+
+	s.singles++
+	var call map[string]any
+	if err := json.Unmarshal(body, &call); err != nil {
+		return nil, err
+	}
+
+	return mockResponse(http.StatusOK, s.answer(call)), nil
+}
+
+func defaultBatchAnswer(call map[string]any) string {
+	callID := call["callid"]
+	switch call["method"] {
+	case "CmfTask.get":
+		return fmt.Sprintf(`{"jsonrpc":"2.2","callid":%q,"result":{"id":"CmfTask:1","code":"PROJ-1"}}`, callID)
+	case "CmfTask.count":
+		return fmt.Sprintf(`{"jsonrpc":"2.2","callid":%q,"result":42}`, callID)
+	case "CmfProject.list":
+		return fmt.Sprintf(`{"jsonrpc":"2.2","callid":%q,"result":[{"id":"CmfProject:1"},{"id":"CmfProject:2"}],"meta":{"Project":{"verbose_name":"Project"}}}`, callID)
+	case "CmfTask.create":
+		return fmt.Sprintf(`{"jsonrpc":"2.2","callid":%q,"result":"CmfTask:new"}`, callID)
+	case "CmfTask.update":
+		return fmt.Sprintf(`{"jsonrpc":"2.2","callid":%q,"result":{"id":"CmfTask:1"}}`, callID)
+	default:
+		return fmt.Sprintf(`{"jsonrpc":"2.2","callid":%q,"error":{"code":-32601,"message":"method not found"}}`, callID)
+	}
+}
+
+func newBatchTestClient(t *testing.T, srv *batchServer) *Client {
+	t.Helper()
+
+	client, _ := newTestClient(t)
+	if srv.answer == nil {
+		srv.answer = defaultBatchAnswer
+	}
+	client.httpClient = srv
+
+	return client
+}
+
+func queueAll(b *Batch) (*BatchCall[models.Task], *BatchCall[int], *BatchCall[[]models.Project], *BatchCall[string], *BatchCall[string]) {
+	task := BatchGet[models.Task](b, NewQueryBuilder().From(EntityTask).Where(sq.Eq{"code": "PROJ-1"}))
+	count := BatchCount(b, NewQueryBuilder().From(EntityTask))
+	projects := BatchList[models.Project](b, NewQueryBuilder().From(EntityProject))
+	created := BatchCreate(b, EntityTask, map[string]any{"name": "New"})
+	updated := BatchUpdate(b, EntityTask, "CmfTask:1", map[string]any{"priority": 5})
+
+	return task, count, projects, created, updated
+}
+
+func TestBatch_Do_SendsSingleRequestAndCorrelatesByCallID(t *testing.T) {
+	srv := &batchServer{reverse: true}
+	client := newBatchTestClient(t, srv)
+
+	b := client.Batch(testCtx)
+	task, count, projects, created, updated := queueAll(b)
+	require.NoError(t, b.Do())
+
+	assert.Equal(t, 1, srv.batches)
+	assert.Equal(t, 0, srv.singles)
+
+	gotTask, _, err := task.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ-1", gotTask.Code)
+
+	gotCount, _, err := count.Result()
+	require.NoError(t, err)
+	assert.Equal(t, 42, gotCount)
+
+	gotProjects, meta, err := projects.Result()
+	require.NoError(t, err)
+	assert.Len(t, gotProjects, 2)
+	require.NotNil(t, meta)
+	assert.Equal(t, "Project", meta.Project.VerboseName)
+
+	id, _, err := created.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "CmfTask:new", id)
+
+	id, _, err = updated.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "CmfTask:1", id)
+}
+
+func TestBatch_Do_PerCallRPCError(t *testing.T) {
+	srv := &batchServer{}
+	client := newBatchTestClient(t, srv)
+
+	b := client.Batch(testCtx)
+	count := BatchCount(b, NewQueryBuilder().From(EntityTask))
+	missing := BatchCount(b, NewQueryBuilder().From("CmfUnknown"))
+	require.NoError(t, b.Do())
+
+	n, _, err := count.Result()
+	require.NoError(t, err)
+	assert.Equal(t, 42, n)
+
+	_, _, err = missing.Result()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrMethodNotFound)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "CmfUnknown.count", apiErr.Method)
+}
+
+func TestBatch_Do_FallsBackToSingleCallsWhenRejected(t *testing.T) {
+	srv := &batchServer{rejectBatch: true}
+	client := newBatchTestClient(t, srv)
+
+	b := client.Batch(testCtx).Concurrency(2)
+	task, count, projects, created, updated := queueAll(b)
+	require.NoError(t, b.Do())
+
+	assert.Equal(t, 1, srv.batches)
+	assert.Equal(t, 5, srv.singles)
+
+	gotTask, _, err := task.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ-1", gotTask.Code)
+
+	gotCount, _, err := count.Result()
+	require.NoError(t, err)
+	assert.Equal(t, 42, gotCount)
+
+	gotProjects, _, err := projects.Result()
+	require.NoError(t, err)
+	assert.Len(t, gotProjects, 2)
+
+	id, _, err := created.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "CmfTask:new", id)
+
+	id, _, err = updated.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "CmfTask:1", id)
+
+	// The client remembers that batches are unsupported.
+	b = client.Batch(testCtx)
+	queueAll(b)
+	require.NoError(t, b.Do())
+	assert.Equal(t, 1, srv.batches)
+	assert.Equal(t, 10, srv.singles)
+}
+
+func TestBatch_Do_SingleCallsStopOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(testCtx)
+	defer cancel()
+	srv := &batchServer{answer: func(call map[string]any) string {
+		cancel()
+		return defaultBatchAnswer(call)
+	}}
+	client := newBatchTestClient(t, srv)
+	client.batchUnsupported.Store(true)
+
+	b := client.Batch(ctx).Concurrency(1)
+	first := BatchCount(b, NewQueryBuilder().From(EntityTask))
+	rest := []*BatchCall[int]{
+		BatchCount(b, NewQueryBuilder().From(EntityTask)),
+		BatchCount(b, NewQueryBuilder().From(EntityTask)),
+	}
+	err := b.Do()
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, srv.singles, "no call waits for a slot after cancellation")
+	_, _, err = first.Result()
+	require.NoError(t, err)
+	for _, call := range rest {
+		_, _, err = call.Result()
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func TestBatch_Do_NonArrayReply(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		wantSends bool // the calls are resent one by one
+	}{
+		{"invalid request rejects batching", `{"jsonrpc":"2.2","error":{"code":-32600,"message":"Invalid Request"}}`, true},
+		{"method not found rejects batching", `{"jsonrpc":"2.2","error":{"code":-32601,"message":"Method not found"}}`, true},
+		{"auth error fails the batch only", `{"jsonrpc":"2.2","error":{"code":-32000,"message":"Token expired"}}`, false},
+		{"rate limit fails the batch only", `{"jsonrpc":"2.2","error":{"code":429,"message":"Too many requests"}}`, false},
+		{"garbage fails the batch only", `<html>oops</html>`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &batchServer{batchReply: tt.reply}
+			client := newBatchTestClient(t, srv)
+
+			b := client.Batch(testCtx)
+			task, count, _, _, _ := queueAll(b)
+			err := b.Do()
+
+			assert.Equal(t, tt.wantSends, client.batchUnsupported.Load())
+			if tt.wantSends {
+				require.NoError(t, err)
+				assert.Equal(t, 5, srv.singles)
+				_, _, err = task.Result()
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, 0, srv.singles)
+			_, _, err = count.Result()
+			assert.Error(t, err)
+
+			// The next batch is still sent as a batch.
+			srv.batchReply = ""
+			b = client.Batch(testCtx)
+			task, _, _, _, _ = queueAll(b)
+			require.NoError(t, b.Do())
+			assert.Equal(t, 2, srv.batches)
+			_, _, err = task.Result()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBatch_Do_TransportErrorFailsAllCalls(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.err = fmt.Errorf("connection refused")
+
+	b := client.Batch(testCtx)
+	first := BatchCount(b, NewQueryBuilder().From(EntityTask))
+	second := BatchCount(b, NewQueryBuilder().From(EntityProject))
+
+	err := b.Do()
+	require.Error(t, err)
+
+	_, _, err = first.Result()
+	require.Error(t, err)
+	_, _, err = second.Result()
+	require.Error(t, err)
+}
+
+func TestBatch_Do_MissingResponse(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `[{"jsonrpc":"2.2","callid":"unknown","result":1}]`)
+
+	b := client.Batch(testCtx)
+	first := BatchCount(b, NewQueryBuilder().From(EntityTask))
+	BatchCount(b, NewQueryBuilder().From(EntityProject))
+	require.NoError(t, b.Do())
+
+	_, _, err := first.Result()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no response")
+}
+
+func TestBatch_InvalidQueryIsNotSent(t *testing.T) {
+	srv := &batchServer{}
+	client := newBatchTestClient(t, srv)
+
+	b := client.Batch(testCtx)
+	invalid := BatchGet[models.Task](b, NewQueryBuilder())
+	emptyID := BatchUpdate(b, EntityTask, "", nil)
+	count := BatchCount(b, NewQueryBuilder().From(EntityTask))
+	require.NoError(t, b.Do())
+
+	assert.Equal(t, 0, srv.batches)
+	assert.Equal(t, 1, srv.singles)
+
+	_, _, err := invalid.Result()
+	require.Error(t, err)
+	_, _, err = emptyID.Result()
+	require.Error(t, err)
+	n, _, err := count.Result()
+	require.NoError(t, err)
+	assert.Equal(t, 42, n)
+}
+
+func TestBatchCall_Result_BeforeDo(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	call := BatchCount(client.Batch(testCtx), NewQueryBuilder().From(EntityTask))
+
+	_, _, err := call.Result()
+	assert.ErrorIs(t, err, ErrBatchNotExecuted)
+}
+
+func TestRPCRequest_MarshalJSON_Batch(t *testing.T) {
+	body := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  BatchMethod,
+		Calls: []*RPCRequest{
+			{JSONRPC: "2.2", Method: "CmfTask.count", CallID: "a"},
+			{JSONRPC: "2.2", Method: "CmfProject.count", CallID: "b"},
+		},
+	}
+
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"jsonrpc":"2.2","method":"CmfTask.count","callid":"a"},
+		{"jsonrpc":"2.2","method":"CmfProject.count","callid":"b"}
+	]`, string(data))
+	assert.Equal(t, []string{"CmfTask.count", "CmfProject.count"}, body.methods())
+}
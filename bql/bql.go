@@ -0,0 +1,227 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+// Package bql is an AST of EVA filters (BQL) that renders straight to the
+// "filter" kwarg of list methods.
+//
+// A condition is [field, op, value]; a list of expressions is their AND;
+// OR and NOT are written inline and groups nest to any depth:
+//
+//	Cond{"code", Eq, "T-1"}             -> ["code", "==", "T-1"]
+//	Cond{"parent_id", Eq, nil}          -> ["parent_id", "==", null]
+//	Cond{"tags", Contains, "TAG-1"}     -> ["tags", "contains", "TAG-1"]
+//	And{a, b}                           -> [a, b]
+//	Or{a, b}                            -> [a, "or", b]
+//	Not{a}                              -> ["not", a]
+//	And{Or{a, b}, Not{Or{c, d}}}        -> [[a, "or", b], ["not", [c, "or", d]]]
+//
+// Parse reads such a filter back.
+package bql
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Op is a comparison operator of a condition.
+type Op string
+
+// Eq and NotEq with a nil value test for NULL. Contains and NotContains
+// test membership in m2m fields such as executors, tags and lists.
+const (
+	Eq          Op = "=="
+	NotEq       Op = "!="
+	Gt          Op = ">"
+	GtOrEq      Op = ">="
+	Lt          Op = "<"
+	LtOrEq      Op = "<="
+	In          Op = "IN"
+	NotIn       Op = "NOT IN"
+	Like        Op = "LIKE"
+	NotLike     Op = "NOT LIKE"
+	ILike       Op = "ILIKE"
+	NotILike    Op = "NOT ILIKE"
+	Contains    Op = "contains"
+	NotContains Op = "not contains"
+)
+
+const (
+	keywordOr  = "or"
+	keywordNot = "not"
+)
+
+var ErrUnsupported = errors.New("unsupported filter")
+
+// Expr is a filter expression: Cond, And, Or or Not.
+type Expr interface {
+	filter() (any, error)
+}
+
+// Cond compares a field with a value.
+type Cond struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+// And matches when all expressions match. An empty And matches everything.
+type And []Expr
+
+// Or matches when any expression matches.
+type Or []Expr
+
+// Not matches when the expression does not match.
+type Not struct {
+	Expr Expr
+}
+
+// Filter renders e as the EVA "filter" kwarg; nil means no filter.
+func Filter(e Expr) (any, error) {
+	if e == nil {
+		return nil, nil
+	}
+
+	return e.filter()
+}
+
+func (c Cond) filter() (any, error) {
+	if c.Field == "" {
+		return nil, errors.Wrapf(ErrUnsupported, "condition %q without field", c.Op)
+	}
+
+	switch c.Op {
+	case Eq, NotEq:
+		return []any{c.Field, string(c.Op), c.Value}, nil
+	case Gt, GtOrEq, Lt, LtOrEq, Like, NotLike, ILike, NotILike, Contains, NotContains:
+		if c.Value == nil {
+			return nil, errors.Errorf("%s %s: value must not be nil", c.Field, c.Op)
+		}
+		return []any{c.Field, string(c.Op), c.Value}, nil
+	case In, NotIn:
+		values, ok := toSlice(c.Value)
+		if !ok {
+			return nil, errors.Errorf("%s %s: value must be a slice, got %T", c.Field, c.Op, c.Value)
+		}
+		return []any{c.Field, string(c.Op), values}, nil
+	default:
+		return nil, errors.Wrapf(ErrUnsupported, "operator %q", c.Op)
+	}
+}
+
+func (a And) filter() (any, error) {
+	items := make([]any, 0, len(a))
+	for _, e := range a.flatten() {
+		item, err := render(e)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	switch len(items) {
+	case 0:
+		return nil, nil
+	case 1:
+		return items[0], nil
+	default:
+		return items, nil
+	}
+}
+
+// flatten inlines nested Ands, which mean the same as their items.
+func (a And) flatten() []Expr {
+	exprs := make([]Expr, 0, len(a))
+	for _, e := range a {
+		if nested, ok := e.(And); ok {
+			exprs = append(exprs, nested.flatten()...)
+			continue
+		}
+		exprs = append(exprs, e)
+	}
+
+	return exprs
+}
+
+func (o Or) filter() (any, error) {
+	exprs := o.flatten()
+	if len(exprs) == 0 {
+		return nil, errors.Wrap(ErrUnsupported, "empty OR")
+	}
+
+	items := make([]any, 0, 2*len(exprs)-1)
+	for i, e := range exprs {
+		item, err := render(e)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			items = append(items, keywordOr)
+		}
+		items = append(items, item)
+	}
+	if len(items) == 1 {
+		return items[0], nil
+	}
+
+	return items, nil
+}
+
+// flatten inlines nested Ors, which mean the same as their items.
+func (o Or) flatten() []Expr {
+	exprs := make([]Expr, 0, len(o))
+	for _, e := range o {
+		if nested, ok := e.(Or); ok {
+			exprs = append(exprs, nested.flatten()...)
+			continue
+		}
+		exprs = append(exprs, e)
+	}
+
+	return exprs
+}
+
+func (n Not) filter() (any, error) {
+	item, err := render(n.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return []any{keywordNot, item}, nil
+}
+
+// render renders an operand of And, Or or Not, which must not be empty.
+func render(e Expr) (any, error) {
+	if e == nil {
+		return nil, errors.Wrap(ErrUnsupported, "nil expression")
+	}
+	item, err := e.filter()
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, errors.Wrap(ErrUnsupported, "empty group")
+	}
+
+	return item, nil
+}
+
+// toSlice converts any slice or array to []any.
+func toSlice(v any) ([]any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+
+	return values, true
+}
@@ -0,0 +1,267 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package bql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	open := Cond{Field: "cache_status_type", Op: Eq, Value: "OPEN"}
+	mine := Cond{Field: "responsible_id", Op: Eq, Value: "CmfPerson:1"}
+
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected any
+	}{
+		{"nil", nil, nil},
+		{"empty and", And{}, nil},
+		{"cond", open, []any{"cache_status_type", "==", "OPEN"}},
+		{"in", Cond{Field: "lists", Op: In, Value: []string{"S-1", "S-2"}}, []any{"lists", "IN", []any{"S-1", "S-2"}}},
+		{"and", And{open, mine}, []any{
+			[]any{"cache_status_type", "==", "OPEN"},
+			[]any{"responsible_id", "==", "CmfPerson:1"},
+		}},
+		{"nested and is flattened", And{open, And{mine}}, []any{
+			[]any{"cache_status_type", "==", "OPEN"},
+			[]any{"responsible_id", "==", "CmfPerson:1"},
+		}},
+		{"single and", And{open}, []any{"cache_status_type", "==", "OPEN"}},
+		{"or", Or{open, mine}, []any{
+			[]any{"cache_status_type", "==", "OPEN"},
+			"or",
+			[]any{"responsible_id", "==", "CmfPerson:1"},
+		}},
+		{"not", Not{open}, []any{"not", []any{"cache_status_type", "==", "OPEN"}}},
+		{"not in", Cond{Field: "lists", Op: NotIn, Value: []string{"S-1"}}, []any{"lists", "NOT IN", []any{"S-1"}}},
+		{"is null", Cond{Field: "parent_id", Op: Eq, Value: nil}, []any{"parent_id", "==", nil}},
+		{"is not null", Cond{Field: "parent_id", Op: NotEq, Value: nil}, []any{"parent_id", "!=", nil}},
+		{"not like", Cond{Field: "name", Op: NotLike, Value: "%x%"}, []any{"name", "NOT LIKE", "%x%"}},
+		{"ilike", Cond{Field: "name", Op: ILike, Value: "%x%"}, []any{"name", "ILIKE", "%x%"}},
+		{"not ilike", Cond{Field: "name", Op: NotILike, Value: "%x%"}, []any{"name", "NOT ILIKE", "%x%"}},
+		{"contains", Cond{Field: "tags", Op: Contains, Value: "TAG-1"}, []any{"tags", "contains", "TAG-1"}},
+		{"not contains", Cond{Field: "executors", Op: NotContains, Value: "CmfPerson:1"}, []any{"executors", "not contains", "CmfPerson:1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := Filter(tt.expr)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
+
+func TestFilter_Invalid(t *testing.T) {
+	for name, expr := range map[string]Expr{
+		"no field":        Cond{Op: Eq, Value: 1},
+		"unknown op":      Cond{Field: "a", Op: "~", Value: 1},
+		"empty or":        Or{},
+		"empty nested or": Or{Or{}},
+		"empty operand":   Or{And{}, Cond{Field: "a", Op: Eq, Value: 1}},
+		"nil in not":      Not{},
+		"invalid nested":  And{Not{Cond{Field: "a", Op: "~"}}},
+	} {
+		_, err := Filter(expr)
+		require.ErrorIs(t, err, ErrUnsupported, name)
+	}
+
+	_, err := Filter(Cond{Field: "a", Op: In, Value: "x"})
+	require.Error(t, err, "IN needs a list")
+	_, err = Filter(Cond{Field: "a", Op: Contains})
+	require.Error(t, err, "contains needs a value")
+}
+
+func TestFromSquirrel(t *testing.T) {
+	tests := []struct {
+		name     string
+		pred     any
+		expected Expr
+	}{
+		{"nil", nil, And{}},
+		{"eq", sq.Eq{"a": 1}, Cond{Field: "a", Op: Eq, Value: 1}},
+		{"eq in", sq.Eq{"a": []int{1, 2}}, Cond{Field: "a", Op: In, Value: []int{1, 2}}},
+		{"map", map[string]any{"a": 1}, Cond{Field: "a", Op: Eq, Value: 1}},
+		{"not eq", sq.NotEq{"a": 1}, Cond{Field: "a", Op: NotEq, Value: 1}},
+		{"gt", sq.Gt{"a": 1}, Cond{Field: "a", Op: Gt, Value: 1}},
+		{"gt or eq", sq.GtOrEq{"a": 1}, Cond{Field: "a", Op: GtOrEq, Value: 1}},
+		{"lt", sq.Lt{"a": 1}, Cond{Field: "a", Op: Lt, Value: 1}},
+		{"lt or eq", sq.LtOrEq{"a": 1}, Cond{Field: "a", Op: LtOrEq, Value: 1}},
+		{"like", sq.Like{"a": "%x%"}, Cond{Field: "a", Op: Like, Value: "%x%"}},
+		{"not like", sq.NotLike{"a": "%x%"}, Cond{Field: "a", Op: NotLike, Value: "%x%"}},
+		{"ilike", sq.ILike{"a": "%x%"}, Cond{Field: "a", Op: ILike, Value: "%x%"}},
+		{"not ilike", sq.NotILike{"a": "%x%"}, Cond{Field: "a", Op: NotILike, Value: "%x%"}},
+		{"is null", sq.Eq{"a": nil}, Cond{Field: "a", Op: Eq, Value: nil}},
+		{"is not null", sq.NotEq{"a": nil}, Cond{Field: "a", Op: NotEq, Value: nil}},
+		{"not in", sq.NotEq{"a": []int{1, 2}}, Cond{Field: "a", Op: NotIn, Value: []int{1, 2}}},
+		{"several fields in key order", sq.Eq{"b": 2, "a": 1}, And{
+			Cond{Field: "a", Op: Eq, Value: 1},
+			Cond{Field: "b", Op: Eq, Value: 2},
+		}},
+		{"and", sq.And{sq.Eq{"a": 1}, sq.Gt{"b": 2}}, And{
+			Cond{Field: "a", Op: Eq, Value: 1},
+			Cond{Field: "b", Op: Gt, Value: 2},
+		}},
+		{"or", sq.Or{sq.Eq{"a": 1}, sq.Eq{"b": 2}}, Or{
+			Cond{Field: "a", Op: Eq, Value: 1},
+			Cond{Field: "b", Op: Eq, Value: 2},
+		}},
+		{"nested", sq.And{sq.Or{sq.Eq{"a": 1}, sq.And{sq.Eq{"b": 2}, sq.Lt{"c": 3}}}, sq.NotEq{"d": 4}}, And{
+			Or{
+				Cond{Field: "a", Op: Eq, Value: 1},
+				And{Cond{Field: "b", Op: Eq, Value: 2}, Cond{Field: "c", Op: Lt, Value: 3}},
+			},
+			Cond{Field: "d", Op: NotEq, Value: 4},
+		}},
+		{"expr", Not{Cond{Field: "a", Op: Eq, Value: 1}}, Not{Cond{Field: "a", Op: Eq, Value: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := FromSquirrel(tt.pred)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expr)
+		})
+	}
+}
+
+func TestFromSquirrel_Unsupported(t *testing.T) {
+	for name, pred := range map[string]any{
+		"raw sql":       "a = 1",
+		"expr":          sq.Expr("a = ?", 1),
+		"error":         errors.New("bad"),
+		"nested in and": sq.And{sq.Eq{"a": 1}, sq.Expr("b = 2")},
+		"gt list":       sq.Gt{"a": []int{1}},
+		"like nil":      sq.Like{"a": nil},
+		"nested in or":  sq.Or{sq.Eq{"a": 1}, sq.And{sq.Expr("b = 2")}},
+	} {
+		_, err := FromSquirrel(pred)
+		require.ErrorIs(t, err, ErrUnsupported, name)
+	}
+}
+
+func TestFilter_Nested(t *testing.T) {
+	a := Cond{Field: "a", Op: Eq, Value: 1}
+	b := Cond{Field: "b", Op: Eq, Value: 2}
+	c := Cond{Field: "c", Op: Eq, Value: 3}
+	fa, fb, fc := []any{"a", "==", 1}, []any{"b", "==", 2}, []any{"c", "==", 3}
+
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected any
+	}{
+		{"and of or", And{Or{a, b}, c}, []any{[]any{fa, "or", fb}, fc}},
+		{"or of and", Or{And{a, b}, c}, []any{[]any{fa, fb}, "or", fc}},
+		{"nested or is flattened", Or{a, Or{b, c}}, []any{fa, "or", fb, "or", fc}},
+		{"not of or", Not{Or{a, b}}, []any{"not", []any{fa, "or", fb}}},
+		{"not of and", Not{And{a, b}}, []any{"not", []any{fa, fb}}},
+		{"or of not", Or{Not{a}, b}, []any{[]any{"not", fa}, "or", fb}},
+		{"deep", And{a, Or{b, Not{And{a, Or{b, c}}}}}, []any{
+			fa,
+			[]any{fb, "or", []any{"not", []any{fa, []any{fb, "or", fc}}}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := Filter(tt.expr)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	a := Cond{Field: "cache_status_type", Op: Eq, Value: "OPEN"}
+	b := Cond{Field: "responsible_id", Op: Eq, Value: "CmfPerson:1"}
+	c := Cond{Field: "priority", Op: GtOrEq, Value: 3}
+	d := Cond{Field: "lists", Op: In, Value: []any{"S-1", "S-2"}}
+
+	for name, expr := range map[string]Expr{
+		"cond":       a,
+		"and":        And{a, b},
+		"or":         Or{a, b, c},
+		"not":        Not{a},
+		"and of or":  And{Or{a, b}, d},
+		"or of and":  Or{And{a, b}, And{c, d}},
+		"not of or":  Not{Or{a, b}},
+		"not of and": Not{And{a, d}},
+		"or of not":  Or{Not{a}, b},
+		"not of not": Not{Not{a}},
+		"deep":       And{Or{a, Not{And{b, Or{c, d}}}}, Not{d}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			filter, err := Filter(expr)
+			require.NoError(t, err)
+
+			parsed, err := Parse(filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, expr, parsed)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	a, b, c := Cond{Field: "a", Op: Eq, Value: 1.0}, Cond{Field: "b", Op: Eq, Value: 2.0}, Cond{Field: "c", Op: Eq, Value: 3.0}
+
+	tests := []struct {
+		name     string
+		filter   any
+		expected Expr
+	}{
+		{"nil", nil, And{}},
+		{"typed list", [][]any{{"a", "==", 1.0}, {"b", "==", 2.0}}, And{a, b}},
+		{"and binds tighter than or", []any{
+			[]any{"a", "==", 1.0}, "and", []any{"b", "==", 2.0}, "OR", []any{"c", "==", 3.0},
+		}, Or{And{a, b}, c}},
+		{"json", decode(t, `[["a","==",1],"or",["not",[["b","==",2],["c","==",3]]]]`), Or{a, Not{And{b, c}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expr)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, filter := range map[string]any{
+		"not a list":     "a == 1",
+		"leading or":     []any{"or", []any{"a", "==", 1}},
+		"trailing or":    []any{[]any{"a", "==", 1}, "or"},
+		"nested invalid": []any{[]any{"a", "==", 1}, []any{"not", 2}},
+	} {
+		_, err := Parse(filter)
+		require.ErrorIs(t, err, ErrUnsupported, name)
+	}
+}
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+
+	return v
+}
@@ -0,0 +1,99 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package bql
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const keywordAnd = "and"
+
+// Parse reads an EVA "filter" kwarg, as rendered by Filter or written by
+// hand, back to an Expr. Besides the implicit AND of a list, an inline "and"
+// is accepted; it binds tighter than "or". A nil filter is an empty And.
+func Parse(filter any) (Expr, error) {
+	if filter == nil {
+		return And{}, nil
+	}
+
+	items, ok := toSlice(filter)
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupported, "filter %v is not a list", filter)
+	}
+	if cond, ok := parseCond(items); ok {
+		return cond, nil
+	}
+	if len(items) == 2 && isKeyword(items[0], keywordNot) {
+		e, err := Parse(items[1])
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: e}, nil
+	}
+
+	var or Or
+	and := And{}
+	for i, item := range items {
+		switch {
+		case isKeyword(item, keywordOr):
+			if len(and) == 0 || i == len(items)-1 {
+				return nil, errors.Wrapf(ErrUnsupported, "dangling %q in %v", item, filter)
+			}
+			or = append(or, unwrap(and))
+			and = And{}
+		case isKeyword(item, keywordAnd):
+			if len(and) == 0 || i == len(items)-1 {
+				return nil, errors.Wrapf(ErrUnsupported, "dangling %q in %v", item, filter)
+			}
+		default:
+			e, err := Parse(item)
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, e)
+		}
+	}
+	if or == nil {
+		return and, nil
+	}
+
+	return append(or, unwrap(and)), nil
+}
+
+// parseCond reads [field, op, value].
+func parseCond(items []any) (Cond, bool) {
+	if len(items) != 3 {
+		return Cond{}, false
+	}
+	field, ok := items[0].(string)
+	if !ok {
+		return Cond{}, false
+	}
+	op, ok := items[1].(string)
+	if !ok {
+		return Cond{}, false
+	}
+
+	return Cond{Field: field, Op: Op(op), Value: items[2]}, true
+}
+
+func isKeyword(item any, keyword string) bool {
+	s, ok := item.(string)
+	return ok && strings.EqualFold(s, keyword)
+}
+
+// unwrap returns the only operand of a single-item And.
+func unwrap(and And) Expr {
+	if len(and) == 1 {
+		return and[0]
+	}
+	return and
+}
@@ -0,0 +1,103 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package bql
+
+import (
+	"maps"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+// FromSquirrel converts a Squirrel predicate (sq.Eq, sq.NotEq, sq.Gt,
+// sq.GtOrEq, sq.Lt, sq.LtOrEq, sq.Like, sq.NotLike, sq.ILike, sq.NotILike,
+// sq.And, sq.Or or a plain map[string]any) to an Expr, nested groups
+// included. Like Squirrel, sq.Eq{f: nil} tests for NULL and a list value of
+// sq.Eq / sq.NotEq means IN / NOT IN. An Expr is returned
+// as is; any other predicate, such as raw SQL, is ErrUnsupported.
+func FromSquirrel(pred any) (Expr, error) {
+	switch p := pred.(type) {
+	case nil:
+		return And{}, nil
+	case Expr:
+		return p, nil
+	case sq.And:
+		exprs, err := fromSquirrelList(p)
+		return And(exprs), err
+	case sq.Or:
+		exprs, err := fromSquirrelList(p)
+		return Or(exprs), err
+	case sq.Eq:
+		return fromMap(p, Eq)
+	case map[string]any:
+		return fromMap(p, Eq)
+	case sq.NotEq:
+		return fromMap(p, NotEq)
+	case sq.Gt:
+		return fromMap(p, Gt)
+	case sq.GtOrEq:
+		return fromMap(p, GtOrEq)
+	case sq.Lt:
+		return fromMap(p, Lt)
+	case sq.LtOrEq:
+		return fromMap(p, LtOrEq)
+	case sq.Like:
+		return fromMap(p, Like)
+	case sq.NotLike:
+		return fromMap(p, NotLike)
+	case sq.ILike:
+		return fromMap(p, ILike)
+	case sq.NotILike:
+		return fromMap(p, NotILike)
+	default:
+		return nil, errors.Wrapf(ErrUnsupported, "predicate %T", pred)
+	}
+}
+
+func fromSquirrelList(preds []sq.Sqlizer) ([]Expr, error) {
+	list := make([]Expr, 0, len(preds))
+	for _, pred := range preds {
+		e, err := FromSquirrel(pred)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+	}
+
+	return list, nil
+}
+
+// fromMap converts a Squirrel field map to the AND of its conditions in
+// field order, like Squirrel renders it.
+func fromMap(m map[string]any, op Op) (Expr, error) {
+	and := make(And, 0, len(m))
+	for _, field := range slices.Sorted(maps.Keys(m)) {
+		value := m[field]
+		_, isSlice := toSlice(value)
+
+		switch {
+		case value == nil && op != Eq && op != NotEq:
+			return nil, errors.Wrapf(ErrUnsupported, "%s %s nil", field, op)
+		case isSlice && op == Eq:
+			and = append(and, Cond{Field: field, Op: In, Value: value})
+		case isSlice && op == NotEq:
+			and = append(and, Cond{Field: field, Op: NotIn, Value: value})
+		case isSlice:
+			return nil, errors.Wrapf(ErrUnsupported, "%s %s with a list value", field, op)
+		default:
+			and = append(and, Cond{Field: field, Op: op, Value: value})
+		}
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+
+	return and, nil
+}
@@ -0,0 +1,272 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+	defaultCircuitHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is returned without sending a request while the circuit
+// breaker of the host is open. It also matches ErrServerUnavailable.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all requests fast with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig configures the circuit breaker.
+//
+// The breaker opens after FailureThreshold consecutive failed attempts
+// (transport errors or 5xx responses), fails fast for OpenTimeout, then lets
+// HalfOpenRequests probes through: a successful probe closes it, a failed one
+// opens it again.
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenRequests int
+}
+
+// DefaultCircuitBreakerConfig returns a config opening after 5 consecutive
+// failures for 30s with a single half-open probe.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold: defaultCircuitFailureThreshold,
+		OpenTimeout:      defaultCircuitOpenTimeout,
+		HalfOpenRequests: defaultCircuitHalfOpenRequests,
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker keyed by API host.
+//
+// Example:
+//
+//	client, err := evateamclient.NewClient(cfg,
+//	  evateamclient.WithCircuitBreaker(evateamclient.DefaultCircuitBreakerConfig()),
+//	)
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Option {
+	return func(c *Client) {
+		if cfg.FailureThreshold <= 0 {
+			cfg.FailureThreshold = defaultCircuitFailureThreshold
+		}
+		if cfg.OpenTimeout <= 0 {
+			cfg.OpenTimeout = defaultCircuitOpenTimeout
+		}
+		if cfg.HalfOpenRequests <= 0 {
+			cfg.HalfOpenRequests = defaultCircuitHalfOpenRequests
+		}
+		c.breakers = &circuitBreakers{cfg: cfg, byHost: make(map[string]*circuitBreaker)}
+	}
+}
+
+// Health describes the client's view of the API host.
+type Health struct {
+	Host string
+	// Circuit is always CircuitClosed without a circuit breaker.
+	Circuit             CircuitState
+	ConsecutiveFailures int
+	// OpenUntil is when an open circuit lets the next probe through.
+	OpenUntil time.Time
+}
+
+// Healthy reports whether requests are currently let through.
+func (h Health) Healthy() bool {
+	return h.Circuit != CircuitOpen
+}
+
+// Health returns the circuit breaker state of the API host.
+//
+// Example:
+//
+//	if h := client.Health(); !h.Healthy() {
+//	  log.Printf("EVA is down, retry after %s", h.OpenUntil)
+//	}
+func (c *Client) Health() Health {
+	host := c.baseURL.Host
+	if c.breakers == nil {
+		return Health{Host: host, Circuit: CircuitClosed}
+	}
+
+	return c.breakers.get(host).health(host)
+}
+
+// circuitBreakers holds a breaker per host.
+type circuitBreakers struct {
+	cfg    CircuitBreakerConfig
+	mu     sync.Mutex
+	byHost map[string]*circuitBreaker
+}
+
+func (b *circuitBreakers) get(host string) *circuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cb, ok := b.byHost[host]
+	if !ok {
+		cb = &circuitBreaker{cfg: b.cfg, now: time.Now}
+		b.byHost[host] = cb
+	}
+
+	return cb
+}
+
+type circuitBreaker struct {
+	cfg      CircuitBreakerConfig
+	now      func() time.Time
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+// allow reports whether an attempt may be sent. It returns the state after
+// the check and whether the state changed (open → half-open).
+func (b *circuitBreaker) allow() (allowed bool, state CircuitState, changed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = CircuitHalfOpen
+		b.probes = 0
+		changed = true
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		return true, b.state, changed
+	case CircuitHalfOpen:
+		if b.probes < b.cfg.HalfOpenRequests {
+			b.probes++
+			return true, b.state, changed
+		}
+	}
+
+	return false, b.state, changed
+}
+
+// record registers the outcome of an allowed attempt and reports the state
+// change, if any.
+func (b *circuitBreaker) record(failed bool) (state CircuitState, changed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prev := b.state
+	if failed {
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.cfg.FailureThreshold {
+			b.state = CircuitOpen
+			b.openedAt = b.now()
+		}
+	} else {
+		b.failures = 0
+		b.state = CircuitClosed
+	}
+
+	return b.state, b.state != prev
+}
+
+// release returns the probe of an attempt that ended without a verdict, e.g.
+// because its ctx was cancelled.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *circuitBreaker) health(host string) Health {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h := Health{Host: host, Circuit: b.state, ConsecutiveFailures: b.failures}
+	if b.state == CircuitOpen {
+		h.OpenUntil = b.openedAt.Add(b.cfg.OpenTimeout)
+	}
+
+	return h
+}
+
+// circuitAllow checks the breaker of the client host before an attempt.
+func (c *Client) circuitAllow() (*circuitBreaker, error) {
+	if c.breakers == nil {
+		return nil, nil
+	}
+
+	host := c.baseURL.Host
+	cb := c.breakers.get(host)
+	allowed, state, changed := cb.allow()
+	if changed {
+		c.recordCircuitState(host, state)
+	}
+	if !allowed {
+		return nil, errors.WithStack(fmt.Errorf("%s: %w: %w", host, ErrCircuitOpen, ErrServerUnavailable))
+	}
+
+	return cb, nil
+}
+
+// circuitRecord registers the outcome of an attempt. Cancelled attempts are
+// neither successes nor failures.
+func (c *Client) circuitRecord(ctx context.Context, cb *circuitBreaker, resp *req.Response, err error) {
+	if cb == nil {
+		return
+	}
+	if err != nil && ctx.Err() != nil {
+		cb.release()
+		return
+	}
+
+	failed := err != nil || (resp != nil && resp.StatusCode >= http.StatusInternalServerError)
+	if state, changed := cb.record(failed); changed {
+		c.recordCircuitState(c.baseURL.Host, state)
+	}
+}
+
+func (c *Client) recordCircuitState(host string, state CircuitState) {
+	if c.metrics != nil {
+		c.metrics.RecordCircuitState(host, state)
+	}
+	c.logDebug(context.Background(), "Circuit breaker state changed", "host", host, "state", state.String())
+}
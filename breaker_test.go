@@ -0,0 +1,185 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stateRecorder captures RecordCircuitState calls.
+type stateRecorder struct {
+	states []CircuitState
+}
+
+func (r *stateRecorder) RecordRequestDuration(int, string, string, string, float64) {}
+
+func (r *stateRecorder) RecordRateLimitWait(string, string, string, float64) {}
+
+func (r *stateRecorder) RecordCircuitState(_ string, state CircuitState) {
+	r.states = append(r.states, state)
+}
+
+func (r *stateRecorder) RecordRetry(int, string, string, string) {}
+
+// newBreakerTestClient returns a client whose breaker opens after 2 failures
+// and uses a controllable clock.
+func newBreakerTestClient(t *testing.T) (*Client, *sequentialMockHTTPClient, *time.Time) {
+	t.Helper()
+
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})(client)
+
+	now := time.Now()
+	client.breakers.get(client.baseURL.Host).now = func() time.Time { return now }
+
+	return client, mockHTTP, &now
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t)
+	metrics := &stateRecorder{}
+	WithMetrics(metrics)(client)
+
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusBadGateway, "down"),
+		nil,
+	}
+	mockHTTP.errors = []error{nil, errors.New("connection refused")}
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+	require.Error(t, err)
+	assert.True(t, client.Health().Healthy())
+
+	_, _, err = client.Task(testCtx, "TASK-1", nil)
+	require.Error(t, err)
+
+	health := client.Health()
+	assert.False(t, health.Healthy())
+	assert.Equal(t, CircuitOpen, health.Circuit)
+	assert.Equal(t, 2, health.ConsecutiveFailures)
+	assert.Equal(t, []CircuitState{CircuitOpen}, metrics.states)
+
+	// Fails fast without a request.
+	_, _, err = client.Task(testCtx, "TASK-1", nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+	assert.Equal(t, 2, mockHTTP.callIdx)
+}
+
+func TestCircuitBreaker_ClientErrorsDoNotTrip(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusNotFound, "missing"),
+		mockResponse(http.StatusTooManyRequests, "slow down"),
+		mockResponse(http.StatusBadGateway, "down"),
+		mockResponse(http.StatusOK, taskGetOKBody),
+		mockResponse(http.StatusBadGateway, "down"),
+	}
+
+	for range mockHTTP.responses {
+		_, _, _ = client.Task(testCtx, "TASK-1", nil)
+	}
+
+	assert.Equal(t, CircuitClosed, client.Health().Circuit)
+	assert.Equal(t, 1, client.Health().ConsecutiveFailures)
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	client, mockHTTP, now := newBreakerTestClient(t)
+	metrics := &stateRecorder{}
+	WithMetrics(metrics)(client)
+
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusServiceUnavailable, "down"),
+		mockResponse(http.StatusServiceUnavailable, "down"),
+		mockResponse(http.StatusServiceUnavailable, "still down"),
+		mockResponse(http.StatusOK, taskGetOKBody),
+	}
+
+	for range 2 {
+		_, _, _ = client.Task(testCtx, "TASK-1", nil)
+	}
+	require.Equal(t, CircuitOpen, client.Health().Circuit)
+
+	// A failed probe re-opens the circuit.
+	*now = now.Add(time.Minute)
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, CircuitOpen, client.Health().Circuit)
+
+	// A successful probe closes it.
+	*now = now.Add(time.Minute)
+	_, _, err = client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, client.Health().Circuit)
+
+	assert.Equal(t, []CircuitState{
+		CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed,
+	}, metrics.states)
+}
+
+func TestCircuitBreaker_HalfOpen_LimitsProbes(t *testing.T) {
+	cb := &circuitBreaker{
+		cfg:   CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Second, HalfOpenRequests: 1},
+		now:   time.Now,
+		state: CircuitOpen,
+	}
+
+	allowed, state, changed := cb.allow()
+	assert.True(t, allowed)
+	assert.Equal(t, CircuitHalfOpen, state)
+	assert.True(t, changed)
+
+	allowed, _, _ = cb.allow()
+	assert.False(t, allowed, "only one probe at a time")
+
+	cb.release()
+	allowed, _, _ = cb.allow()
+	assert.True(t, allowed, "a released probe frees the slot")
+}
+
+func TestCircuitBreaker_CancelledRequestIsNotAFailure(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t)
+
+	ctx, cancel := context.WithCancel(testCtx)
+	cancel()
+	mockHTTP.errors = []error{context.Canceled, context.Canceled}
+
+	for range 2 {
+		_, _, _ = client.Task(ctx, "TASK-1", nil)
+	}
+
+	assert.Equal(t, CircuitClosed, client.Health().Circuit)
+	assert.Equal(t, 0, client.Health().ConsecutiveFailures)
+}
+
+func TestClient_Health_WithoutBreaker(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	health := client.Health()
+
+	assert.True(t, health.Healthy())
+	assert.Equal(t, "api.eva.team", health.Host)
+	assert.Equal(t, CircuitClosed, health.Circuit)
+}
+
+func TestCircuitState_String(t *testing.T) {
+	assert.Equal(t, "closed", CircuitClosed.String())
+	assert.Equal(t, "open", CircuitOpen.String())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+}
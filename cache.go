@@ -0,0 +1,290 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	cacheKeyPrefix         = "eva:"
+	defaultMemoryCacheSize = 1024
+)
+
+// CacheStore stores raw RPC responses. Implementations must be safe for
+// concurrent use. Errors are logged and otherwise ignored: the cache never
+// fails a call.
+type CacheStore interface {
+	// Get returns the value of a key and whether it was found and not expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores a value for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// DeletePrefix removes all keys starting with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// DefaultCacheTTLs are the per-entity TTLs used by WithCache. Only entities
+// listed here (or added via WithCacheTTL) are cached.
+var DefaultCacheTTLs = map[string]time.Duration{
+	EntityLogicType: time.Hour,
+	EntityTag:       10 * time.Minute,
+	EntityPerson:    10 * time.Minute,
+	EntityProject:   5 * time.Minute,
+	EntityList:      5 * time.Minute,
+}
+
+// responseCache caches read responses per entity.
+type responseCache struct {
+	store CacheStore
+	ttls  map[string]time.Duration
+	// scope keeps apart the keys of clients sharing a store but talking to
+	// other hosts or holding tokens with other permissions.
+	scope string
+}
+
+// WithCache enables a read-through cache of read methods (*.get, *.list,
+// *.count, ...) for the entities in DefaultCacheTTLs. Any write method of an
+// entity sent through the client (e.g. CmfProject.update) invalidates all
+// cached responses of that entity. Keys are scoped by the EVA host and API
+// token, so one store may be shared by clients of different hosts or users.
+//
+// Example:
+//
+//	client, err := evateamclient.NewClient(cfg,
+//	  evateamclient.WithCache(evateamclient.NewMemoryCache(1000)),
+//	  evateamclient.WithCacheTTL(evateamclient.EntityTask, 30*time.Second),
+//	)
+func WithCache(store CacheStore) Option {
+	return func(c *Client) {
+		ttls := make(map[string]time.Duration, len(DefaultCacheTTLs))
+		if c.cache != nil {
+			ttls = c.cache.ttls
+		} else {
+			for entity, ttl := range DefaultCacheTTLs {
+				ttls[entity] = ttl
+			}
+		}
+		c.cache = &responseCache{store: store, ttls: ttls, scope: cacheScope(c.baseURL, c.apiToken)}
+	}
+}
+
+// WithCacheTTL sets the cache TTL of an entity; 0 disables caching of it.
+// Must follow WithCache.
+func WithCacheTTL(entity string, ttl time.Duration) Option {
+	return func(c *Client) {
+		if c.cache == nil {
+			return
+		}
+		if ttl <= 0 {
+			delete(c.cache.ttls, entity)
+			return
+		}
+		c.cache.ttls[entity] = ttl
+	}
+}
+
+// lookup returns the cache key and TTL of a request, or "" if the request
+// is not cacheable.
+func (rc *responseCache) lookup(body *RPCRequest) (string, time.Duration) {
+	if rc == nil || len(body.Calls) > 0 || !isIdempotentMethod(body.Method) {
+		return "", 0
+	}
+	entity := methodEntity(body.Method)
+	ttl, ok := rc.ttls[entity]
+	if !ok {
+		return "", 0
+	}
+
+	digest, ok := requestDigest(body)
+	if !ok {
+		return "", 0
+	}
+
+	return rc.entityPrefix(entity) + body.Method + ":" + digest, ttl
+}
+
+// cacheScope returns a hash of the scheme and host of baseURL and of token.
+func cacheScope(baseURL *url.URL, token string) string {
+	var origin string
+	if baseURL != nil {
+		origin = baseURL.Scheme + "://" + baseURL.Host
+	}
+	sum := sha256.Sum256([]byte(origin + "\x00" + token))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// requestDigest returns a hash of the request args and kwargs. Map keys are
+// sorted on marshal, so equal kwargs give equal digests.
+func requestDigest(body *RPCRequest) (string, bool) {
+	params, err := json.Marshal([]any{body.Args, body.Kwargs})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(params)
+
+	return hex.EncodeToString(sum[:]), true
+}
+
+// entityPrefix returns the key prefix of the cached responses of an entity.
+func (rc *responseCache) entityPrefix(entity string) string {
+	return cacheKeyPrefix + rc.scope + ":" + entity + ":"
+}
+
+// methodEntity returns the entity of an RPC method, e.g. "CmfTask" for
+// "CmfTask.get".
+func methodEntity(method string) string {
+	entity, _, _ := strings.Cut(method, ".")
+	return entity
+}
+
+// cacheGet returns the cached response body of a request.
+func (c *Client) cacheGet(ctx context.Context, key string) ([]byte, bool) {
+	if key == "" || isFreshRead(ctx) {
+		return nil, false
+	}
+	value, ok, err := c.cache.store.Get(ctx, key)
+	if err != nil {
+		c.logDebug(ctx, "Cache get failed", "key", key, "error", err)
+		return nil, false
+	}
+
+	return value, ok
+}
+
+func (c *Client) cacheSet(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if key == "" {
+		return
+	}
+	if err := c.cache.store.Set(ctx, key, value, ttl); err != nil {
+		c.logDebug(ctx, "Cache set failed", "key", key, "error", err)
+	}
+}
+
+// cacheInvalidate drops the cached responses of the entities written by the
+// given methods.
+func (c *Client) cacheInvalidate(ctx context.Context, methods ...string) {
+	if c.cache == nil {
+		return
+	}
+	for _, method := range methods {
+		if isIdempotentMethod(method) {
+			continue
+		}
+		entity := methodEntity(method)
+		if _, ok := c.cache.ttls[entity]; !ok {
+			continue
+		}
+		if err := c.cache.store.DeletePrefix(ctx, c.cache.entityPrefix(entity)); err != nil {
+			c.logDebug(ctx, "Cache invalidation failed", "entity", entity, "error", err)
+		}
+	}
+}
+
+// MemoryCache is an in-memory LRU CacheStore.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+	now        func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an LRU cache holding up to maxEntries responses
+// (1024 if maxEntries <= 0).
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultMemoryCacheSize
+	}
+
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+func (m *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := el.Value.(*memoryCacheEntry)
+	if !m.now().Before(entry.expiresAt) {
+		m.remove(el)
+		return nil, false, nil
+	}
+	m.ll.MoveToFront(el)
+
+	return entry.value, true, nil
+}
+
+func (m *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt := m.now().Add(ttl)
+	if el, ok := m.items[key]; ok {
+		entry := el.Value.(*memoryCacheEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		m.ll.MoveToFront(el)
+		return nil
+	}
+
+	m.items[key] = m.ll.PushFront(&memoryCacheEntry{key: key, value: value, expiresAt: expiresAt})
+	for m.ll.Len() > m.maxEntries {
+		m.remove(m.ll.Back())
+	}
+
+	return nil
+}
+
+func (m *MemoryCache) DeletePrefix(_ context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, el := range m.items {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of cached entries, including expired ones not yet
+// evicted.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ll.Len()
+}
+
+func (m *MemoryCache) remove(el *list.Element) {
+	m.ll.Remove(el)
+	delete(m.items, el.Value.(*memoryCacheEntry).key)
+}
@@ -0,0 +1,196 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const projectGetOKBody = `{"jsonrpc":"2.2","result":{"id":"CmfProject:1","code":"PROJ","name":"Project"}}`
+
+func TestMemoryCache_GetSet(t *testing.T) {
+	m := NewMemoryCache(10)
+
+	require.NoError(t, m.Set(testCtx, "a", []byte("1"), time.Minute))
+	value, ok, err := m.Get(testCtx, "a")
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(value))
+
+	_, ok, _ = m.Get(testCtx, "missing")
+	assert.False(t, ok)
+}
+
+func TestMemoryCache_Expires(t *testing.T) {
+	m := NewMemoryCache(10)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	require.NoError(t, m.Set(testCtx, "a", []byte("1"), time.Minute))
+	now = now.Add(time.Minute)
+
+	_, ok, _ := m.Get(testCtx, "a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	m := NewMemoryCache(2)
+
+	require.NoError(t, m.Set(testCtx, "a", []byte("1"), time.Minute))
+	require.NoError(t, m.Set(testCtx, "b", []byte("2"), time.Minute))
+	_, _, _ = m.Get(testCtx, "a")
+	require.NoError(t, m.Set(testCtx, "c", []byte("3"), time.Minute))
+
+	_, okA, _ := m.Get(testCtx, "a")
+	_, okB, _ := m.Get(testCtx, "b")
+	_, okC, _ := m.Get(testCtx, "c")
+	assert.True(t, okA)
+	assert.False(t, okB, "b is least recently used")
+	assert.True(t, okC)
+}
+
+func TestMemoryCache_DeletePrefix(t *testing.T) {
+	m := NewMemoryCache(10)
+	require.NoError(t, m.Set(testCtx, "eva:CmfProject:x", []byte("1"), time.Minute))
+	require.NoError(t, m.Set(testCtx, "eva:CmfTag:y", []byte("2"), time.Minute))
+
+	require.NoError(t, m.DeletePrefix(testCtx, "eva:CmfProject:"))
+
+	_, ok, _ := m.Get(testCtx, "eva:CmfProject:x")
+	assert.False(t, ok)
+	_, ok, _ = m.Get(testCtx, "eva:CmfTag:y")
+	assert.True(t, ok)
+}
+
+func TestClient_Cache_ReadThrough(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, projectGetOKBody)
+	WithCache(NewMemoryCache(10))(client)
+
+	for range 3 {
+		project, _, err := client.Project(testCtx, "PROJ", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "PROJ", project.Code)
+	}
+	assert.Equal(t, 1, mockHTTP.calls)
+
+	// Different kwargs are a different key.
+	_, _, err := client.Project(testCtx, "OTHER", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mockHTTP.calls)
+}
+
+func TestClient_Cache_ScopedByHostAndToken(t *testing.T) {
+	store := NewMemoryCache(10)
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, projectGetOKBody)
+	WithCache(store)(client)
+
+	other, otherHTTP := newTestClient(t)
+	other.apiToken = "other-token"
+	otherHTTP.response = mockResponse(http.StatusOK, projectGetOKBody)
+	WithCache(store)(other)
+
+	_, _, err := client.Project(testCtx, "PROJ", nil)
+	require.NoError(t, err)
+	_, _, err = other.Project(testCtx, "PROJ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, otherHTTP.calls)
+	assert.Equal(t, 2, store.Len())
+
+	// A write invalidates the entries of its own scope only.
+	client.cacheInvalidate(testCtx, "CmfProject.update")
+	assert.Equal(t, 1, store.Len())
+	_, _, err = other.Project(testCtx, "PROJ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, otherHTTP.calls)
+
+	assert.NotEqual(t, cacheScope(client.baseURL, "t"), cacheScope(&url.URL{Scheme: "https", Host: "eva.example.com"}, "t"))
+}
+
+func TestClient_Cache_UncachedEntity(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, taskGetOKBody)
+	WithCache(NewMemoryCache(10))(client)
+
+	for range 2 {
+		_, _, err := client.Task(testCtx, "TASK-1", nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, mockHTTP.calls)
+
+	WithCacheTTL(EntityTask, time.Minute)(client)
+	for range 2 {
+		_, _, err := client.Task(testCtx, "TASK-1", nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, mockHTTP.calls)
+}
+
+func TestClient_Cache_RPCErrorNotCached(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	WithCache(NewMemoryCache(10))(client)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","error":{"code":-32000,"message":"boom"}}`),
+		mockResponse(http.StatusOK, projectGetOKBody),
+	}
+
+	_, _, err := client.Project(testCtx, "PROJ", nil)
+	require.Error(t, err)
+	_, _, err = client.Project(testCtx, "PROJ", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, mockHTTP.callIdx)
+}
+
+func TestClient_Cache_WriteInvalidatesEntity(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	store := NewMemoryCache(10)
+	WithCache(store)(client)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, projectGetOKBody),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","error":{"code":-32000,"message":"boom"}}`),
+		mockResponse(http.StatusOK, projectGetOKBody),
+	}
+
+	_, _, err := client.Project(testCtx, "PROJ", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, store.Len())
+
+	// Even a failed write invalidates: it may have been applied.
+	require.Error(t, client.ProjectDelete(testCtx, "CmfProject:1"))
+	assert.Equal(t, 0, store.Len())
+
+	_, _, err = client.Project(testCtx, "PROJ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, mockHTTP.callIdx)
+}
+
+func TestClient_Cache_BatchWriteInvalidatesEntity(t *testing.T) {
+	srv := &batchServer{}
+	client := newBatchTestClient(t, srv)
+	store := NewMemoryCache(10)
+	WithCache(store)(client)
+	require.NoError(t, store.Set(testCtx, client.cache.entityPrefix(EntityProject)+"x", []byte("{}"), time.Minute))
+
+	b := client.Batch(testCtx)
+	BatchUpdate(b, EntityProject, "CmfProject:1", map[string]any{"name": "x"})
+	require.NoError(t, b.Do())
+
+	assert.Equal(t, 0, store.Len())
+}
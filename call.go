@@ -0,0 +1,117 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+// callResponse is the envelope of any RPC response with a typed result.
+type callResponse[T any] struct {
+	JSONRPC string       `json:"jsonrpc"`
+	Result  T            `json:"result"`
+	Meta    *models.Meta `json:"meta,omitempty"`
+}
+
+// Call sends any RPC method, including ones the client does not wrap, with
+// the same auth, retries, logging, metrics and error handling as the typed
+// methods. The result is decoded into T; meta is nil when the server sent
+// none.
+//
+// Example:
+//
+//	type GanttTask struct {
+//	  ID    string `json:"id"`
+//	  Start string `json:"plan_start_date"`
+//	}
+//	task, _, err := evateamclient.Call[GanttTask](ctx, client, "CmfGanttTask.update",
+//	  []any{"CmfGanttTask:uuid"}, map[string]any{"plan_start_date": "2026-01-10"})
+func Call[T any](
+	ctx context.Context,
+	c *Client,
+	method string,
+	args []any,
+	kwargs map[string]any,
+) (T, *models.Meta, error) {
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  method,
+		CallID:  newCallID(),
+		Args:    args,
+		Kwargs:  kwargs,
+	}
+
+	return doCall[T](ctx, c, reqBody)
+}
+
+// Get retrieves a single object of the entity of qb via <Entity>.get.
+//
+// Example:
+//
+//	company, _, err := evateamclient.Get[MyCompany](ctx, client, evateamclient.NewQueryBuilder().
+//	  Select("id", "name").
+//	  From("CmfCompany").
+//	  Where(sq.Eq{"code": "ACME"}))
+func Get[T any](ctx context.Context, c *Client, qb *QueryBuilder) (*T, *models.Meta, error) {
+	reqBody, err := queryRequest(qb, ".get")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doCall[*T](ctx, c, reqBody)
+}
+
+// List retrieves objects of the entity of qb via <Entity>.list.
+//
+// Example:
+//
+//	companies, meta, err := evateamclient.List[MyCompany](ctx, client, evateamclient.NewQueryBuilder().
+//	  Select("id", "name").
+//	  From("CmfCompany").
+//	  OrderBy("name").
+//	  Limit(50))
+func List[T any](ctx context.Context, c *Client, qb *QueryBuilder) ([]T, *models.Meta, error) {
+	reqBody, err := queryRequest(qb, ".list")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doCall[[]T](ctx, c, reqBody)
+}
+
+// Count counts objects of the entity of qb via <Entity>.count.
+//
+// Example:
+//
+//	n, err := evateamclient.Count(ctx, client, evateamclient.NewQueryBuilder().
+//	  From("CmfNotepad").
+//	  Where(sq.Eq{"cmf_owner_id": "CmfPerson:uuid"}))
+func Count(ctx context.Context, c *Client, qb *QueryBuilder) (int, error) {
+	reqBody, err := queryRequest(qb, ".count")
+	if err != nil {
+		return 0, err
+	}
+
+	count, _, err := doCall[int](ctx, c, reqBody)
+
+	return count, err
+}
+
+func doCall[T any](ctx context.Context, c *Client, reqBody *RPCRequest) (T, *models.Meta, error) {
+	var resp callResponse[T]
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		var zero T
+		return zero, nil, errors.WithMessagef(err, "failed to call %s", reqBody.Method)
+	}
+
+	return resp.Result, resp.Meta, nil
+}
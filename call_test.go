@@ -0,0 +1,144 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	encjson "encoding/json"
+	"net/http"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCompany struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestCall_SendsArgsAndKwargs(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{
+		"jsonrpc": "2.2",
+		"result": {"id": "CmfGanttTask:1", "name": "Design"}
+	}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfGanttTask.update")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		var parsed struct {
+			Method string         `json:"method"`
+			Args   []any          `json:"args"`
+			Kwargs map[string]any `json:"kwargs"`
+		}
+		if err := encjson.Unmarshal(body, &parsed); !assert.NoError(t, err) {
+			return false
+		}
+		return assert.Equal(t, "CmfGanttTask.update", parsed.Method) &&
+			assert.Equal(t, []any{"CmfGanttTask:1"}, parsed.Args) &&
+			assert.Equal(t, map[string]any{"name": "Design"}, parsed.Kwargs)
+	}
+
+	result, meta, err := Call[testCompany](testCtx, client, "CmfGanttTask.update",
+		[]any{"CmfGanttTask:1"}, map[string]any{"name": "Design"})
+
+	require.NoError(t, err)
+	assert.Nil(t, meta)
+	assert.Equal(t, testCompany{ID: "CmfGanttTask:1", Name: "Design"}, result)
+}
+
+func TestCall_RPCError(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{
+		"jsonrpc": "2.2",
+		"error": {"code": -32601, "message": "Method not found"}
+	}`)
+
+	_, _, err := Call[any](testCtx, client, "CmfNope.get", nil, nil)
+
+	require.Error(t, err)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "CmfNope.get", apiErr.Method)
+	assert.Contains(t, err.Error(), "failed to call CmfNope.get")
+}
+
+func TestGet_UsesEntityGetMethod(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{
+		"jsonrpc": "2.2",
+		"result": {"id": "CmfCompany:1", "name": "ACME"},
+		"meta": {"Project": {"verbose_name": "Company"}}
+	}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfCompany.get")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"filter":["code","==","ACME"]`)
+	}
+
+	company, meta, err := Get[testCompany](testCtx, client, NewQueryBuilder().
+		Select("id", "name").
+		From("CmfCompany").
+		Where(sq.Eq{"code": "ACME"}))
+
+	require.NoError(t, err)
+	require.NotNil(t, company)
+	assert.Equal(t, "ACME", company.Name)
+	require.NotNil(t, meta)
+}
+
+func TestGet_NullResult(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":null}`)
+
+	company, _, err := Get[testCompany](testCtx, client, NewQueryBuilder().From("CmfCompany"))
+
+	require.NoError(t, err)
+	assert.Nil(t, company)
+}
+
+func TestList_UsesEntityListMethod(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{
+		"jsonrpc": "2.2",
+		"result": [{"id": "CmfCompany:1", "name": "ACME"}, {"id": "CmfCompany:2", "name": "Globex"}]
+	}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfCompany.list")
+	}
+
+	companies, _, err := List[testCompany](testCtx, client, NewQueryBuilder().From("CmfCompany").Limit(2))
+
+	require.NoError(t, err)
+	assert.Equal(t, []testCompany{{ID: "CmfCompany:1", Name: "ACME"}, {ID: "CmfCompany:2", Name: "Globex"}}, companies)
+}
+
+func TestCount_UsesEntityCountMethod(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":42}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfNotepad.count")
+	}
+
+	n, err := Count(testCtx, client, NewQueryBuilder().From("CmfNotepad"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, n)
+}
+
+func TestList_WithoutFrom(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+
+	_, _, err := List[testCompany](testCtx, client, NewQueryBuilder())
+
+	require.Error(t, err)
+	assert.Equal(t, 0, mockHTTP.calls)
+}
@@ -0,0 +1,412 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"cmp"
+	"context"
+	encjson "encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/bql"
+)
+
+// ChunkConfig configures the splitting of list requests whose filter has an
+// oversized IN list, e.g. sq.Eq{"id": ids} with thousands of IDs.
+type ChunkConfig struct {
+	// Size is the maximum number of IN values per request; 0 disables
+	// chunking.
+	Size int
+	// Concurrency bounds the number of chunk requests in flight.
+	Concurrency int
+}
+
+// DefaultChunkConfig is the chunking of a client without WithChunking.
+//
+// Chunking is on by default, since a request with thousands of IN values
+// tends to hit the limits of the server. It has a cost with an offset: any
+// chunk may hold any rows of the merged window, so each chunk is sent with
+// slice [0, offset+limit] and a deep offset fetches offset+limit rows per
+// chunk. Page such filters with keyset pagination (QueryBuilder.Keyset), or
+// set Size to 0 to disable chunking.
+var DefaultChunkConfig = ChunkConfig{
+	Size:        500,
+	Concurrency: 4,
+}
+
+// WithChunking sets how <Entity>.list requests with an IN list of more than
+// cfg.Size values in their top-level AND filter are split: one request per
+// chunk of values, run concurrently. The results are merged, deduplicated
+// by ID and sorted by the order_by of the request; its slice applies to the
+// merged result. Only the largest oversized IN list is split per level;
+// chunks are split again if they still hold one. The first failed chunk
+// cancels the others.
+//
+// <Entity>.count requests are split the same way and their counts summed,
+// unless they are grouped (group_by) or the IN list is on an m2m field
+// (lists, tags, ...), where a row may match several chunks: those are sent
+// unsplit.
+//
+// Example:
+//
+//	client, err := evateamclient.NewClient(cfg,
+//	  evateamclient.WithChunking(evateamclient.ChunkConfig{Size: 200, Concurrency: 8}),
+//	)
+func WithChunking(cfg ChunkConfig) Option {
+	return func(c *Client) {
+		c.chunking = cfg
+	}
+}
+
+// send sends a request, split into chunks when its filter has an oversized
+// IN list, and returns the raw response body.
+func (c *Client) send(ctx context.Context, body *RPCRequest, fname string) ([]byte, error) {
+	chunks := c.chunking.split(body)
+	if chunks == nil {
+		return c.callCoalesced(ctx, body, fname)
+	}
+
+	// The first failed chunk fails the request: cancel its siblings.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		responses = make([][]byte, len(chunks))
+		sem       = make(chan struct{}, max(1, c.chunking.Concurrency))
+		wg        sync.WaitGroup
+		failOnce  sync.Once
+		failErr   error
+	)
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := c.send(ctx, chunk, fname)
+			if err != nil {
+				failOnce.Do(func() {
+					failErr = err
+					cancel()
+				})
+				return
+			}
+			responses[i] = resp
+		}()
+	}
+	wg.Wait()
+
+	if failErr != nil {
+		return nil, failErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(body.Method, ".count") {
+		return sumChunkCounts(responses)
+	}
+
+	return mergeChunks(body, responses)
+}
+
+// split returns the chunk requests of body, nil if it needs no chunking.
+func (cfg ChunkConfig) split(body *RPCRequest) []*RPCRequest {
+	isCount := strings.HasSuffix(body.Method, ".count")
+	if cfg.Size <= 0 || len(body.Calls) > 0 || (!isCount && !strings.HasSuffix(body.Method, ".list")) {
+		return nil
+	}
+	kwargs, ok := body.Kwargs.(map[string]any)
+	if !ok || kwargs["filter"] == nil || (isCount && kwargs["group_by"] != nil) {
+		return nil
+	}
+	expr, err := bql.Parse(kwargs["filter"])
+	if err != nil {
+		return nil
+	}
+
+	and, ok := expr.(bql.And)
+	if !ok {
+		and = bql.And{expr}
+	}
+	// Pick the largest oversized IN list.
+	at, values := -1, []any(nil)
+	for i, e := range and {
+		cond, ok := e.(bql.Cond)
+		if !ok || !strings.EqualFold(string(cond.Op), string(bql.In)) {
+			continue
+		}
+		if v := sliceValues(cond.Value); len(v) > cfg.Size && len(v) > len(values) {
+			at, values = i, v
+		}
+	}
+	if at < 0 {
+		return nil
+	}
+	if isCount {
+		// Chunk counts add up only if no row matches values of two chunks:
+		// a row has one value of a scalar field but many of an m2m field.
+		field := and[at].(bql.Cond).Field
+		if m2mFields[field[strings.LastIndex(field, ".")+1:]] {
+			return nil
+		}
+		values = uniqueValues(values)
+	}
+
+	chunks := make([]*RPCRequest, 0, (len(values)+cfg.Size-1)/cfg.Size)
+	for chunkValues := range slices.Chunk(values, cfg.Size) {
+		chunkAnd := slices.Clone(and)
+		cond := and[at].(bql.Cond)
+		chunkAnd[at] = bql.Cond{Field: cond.Field, Op: bql.In, Value: chunkValues}
+		filter, err := bql.Filter(chunkAnd)
+		if err != nil {
+			return nil
+		}
+
+		chunkKwargs := make(map[string]any, len(kwargs))
+		for k, v := range kwargs {
+			chunkKwargs[k] = v
+		}
+		chunkKwargs["filter"] = filter
+		// Each chunk may hold any rows of the merged window.
+		if _, end, ok := sliceWindow(kwargs["slice"]); ok {
+			chunkKwargs["slice"] = []int{0, end}
+		}
+
+		chunk := *body
+		chunk.CallID = newCallID()
+		chunk.Kwargs = chunkKwargs
+		chunks = append(chunks, &chunk)
+	}
+
+	return chunks
+}
+
+// mergeChunks merges the list responses of the chunks of body into one
+// response: results deduplicated by ID, sorted by order_by and cut to the
+// slice of body. The meta is the one of the first chunk.
+func mergeChunks(body *RPCRequest, responses [][]byte) ([]byte, error) {
+	kwargs, _ := body.Kwargs.(map[string]any)
+
+	var (
+		merged struct {
+			JSONRPC string               `json:"jsonrpc"`
+			Result  []encjson.RawMessage `json:"result"`
+			Meta    encjson.RawMessage   `json:"meta,omitempty"`
+		}
+		rows []map[string]any
+		seen = make(map[string]bool)
+	)
+	for i, resp := range responses {
+		var chunk struct {
+			JSONRPC string               `json:"jsonrpc"`
+			Result  []encjson.RawMessage `json:"result"`
+			Meta    encjson.RawMessage   `json:"meta"`
+		}
+		if err := json.Unmarshal(resp, &chunk); err != nil {
+			return nil, errors.WithMessage(err, "unmarshal chunk response")
+		}
+		if i == 0 {
+			merged.JSONRPC, merged.Meta = chunk.JSONRPC, chunk.Meta
+		}
+
+		for _, raw := range chunk.Result {
+			var row map[string]any
+			if err := json.Unmarshal(raw, &row); err != nil {
+				return nil, errors.WithMessage(err, "unmarshal chunk row")
+			}
+			if id, ok := row["id"].(string); ok && id != "" {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+			}
+			merged.Result = append(merged.Result, raw)
+			rows = append(rows, row)
+		}
+	}
+
+	if orderBy := stringValues(kwargs["order_by"]); len(orderBy) > 0 {
+		idx := make([]int, len(rows))
+		for i := range idx {
+			idx[i] = i
+		}
+		slices.SortStableFunc(idx, func(a, b int) int {
+			return compareRows(rows[a], rows[b], orderBy)
+		})
+		sorted := make([]encjson.RawMessage, len(idx))
+		for i, j := range idx {
+			sorted[i] = merged.Result[j]
+		}
+		merged.Result = sorted
+	}
+
+	if start, end, ok := sliceWindow(kwargs["slice"]); ok {
+		start, end = min(start, len(merged.Result)), min(end, len(merged.Result))
+		merged.Result = merged.Result[start:max(start, end)]
+	}
+	if merged.Result == nil {
+		merged.Result = []encjson.RawMessage{}
+	}
+
+	return json.Marshal(merged)
+}
+
+// sumChunkCounts merges the count responses of the chunks of a request into
+// one response with the sum of their counts.
+func sumChunkCounts(responses [][]byte) ([]byte, error) {
+	var merged struct {
+		JSONRPC string `json:"jsonrpc"`
+		Result  int64  `json:"result"`
+	}
+	for _, resp := range responses {
+		var chunk struct {
+			JSONRPC string `json:"jsonrpc"`
+			Result  int64  `json:"result"`
+		}
+		if err := json.Unmarshal(resp, &chunk); err != nil {
+			return nil, errors.WithMessage(err, "unmarshal chunk count")
+		}
+		merged.JSONRPC = chunk.JSONRPC
+		merged.Result += chunk.Result
+	}
+
+	return json.Marshal(merged)
+}
+
+// uniqueValues drops repeated values, so that no value is counted by two
+// chunks.
+func uniqueValues(values []any) []any {
+	seen := make(map[string]bool, len(values))
+	unique := make([]any, 0, len(values))
+	for _, v := range values {
+		key := fmt.Sprintf("%T:%v", v, v)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, v)
+		}
+	}
+
+	return unique
+}
+
+// compareRows compares rows by order_by keys ("name", "-priority").
+func compareRows(a, b map[string]any, orderBy []string) int {
+	for _, key := range orderBy {
+		field, desc := strings.CutPrefix(key, "-")
+		n := compareValues(a[field], b[field])
+		if desc {
+			n = -n
+		}
+		if n != 0 {
+			return n
+		}
+	}
+
+	return 0
+}
+
+// compareValues orders nil first, then numbers, booleans and strings by
+// value; other values by their text.
+func compareValues(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok {
+			return cmp.Compare(a, b)
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0
+			case !a:
+				return -1
+			default:
+				return 1
+			}
+		}
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// sliceValues returns the elements of a slice value, nil for other values.
+func sliceValues(v any) []any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+
+	return values
+}
+
+// stringValues returns the strings of a []string or []any value.
+func stringValues(v any) []string {
+	var result []string
+	for _, item := range sliceValues(v) {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// sliceWindow reads a [start, end] slice kwarg of any integer slice type.
+func sliceWindow(v any) (start, end int, ok bool) {
+	values := sliceValues(v)
+	if len(values) != 2 {
+		return 0, 0, false
+	}
+	bounds := make([]int, 2)
+	for i, value := range values {
+		rv := reflect.ValueOf(value)
+		switch {
+		case rv.CanInt():
+			bounds[i] = int(rv.Int())
+		case rv.CanUint():
+			bounds[i] = int(rv.Uint())
+		case rv.CanFloat():
+			bounds[i] = int(rv.Float())
+		default:
+			return 0, 0, false
+		}
+	}
+
+	return bounds[0], bounds[1], true
+}
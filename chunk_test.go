@@ -0,0 +1,258 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/imroc/req/v3"
+	"github.com/raoptimus/evateamclient.go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkHTTPClient answers each request with respond, safe for concurrent use.
+type chunkHTTPClient struct {
+	mu      sync.Mutex
+	bodies  []string
+	respond func(kwargs map[string]any) *req.Response
+}
+
+func (m *chunkHTTPClient) Post(_ context.Context, body []byte, _ string) (*req.Response, error) {
+	var reqBody struct {
+		Kwargs map[string]any `json:"kwargs"`
+	}
+	if err := json.Unmarshal(body, &reqBody); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.bodies = append(m.bodies, string(body))
+	m.mu.Unlock()
+
+	return m.respond(reqBody.Kwargs), nil
+}
+
+func taskIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("CmfTask:%04d", i)
+	}
+	return ids
+}
+
+func TestChunkConfig_Split(t *testing.T) {
+	body := &RPCRequest{
+		Method: "CmfTask.list",
+		Kwargs: map[string]any{
+			"filter": []any{
+				[]any{"project_id", "==", "CmfProject:1"},
+				[]any{"id", "IN", taskIDs(1200)},
+				[]any{"lists", "IN", []string{"SPR-1", "SPR-2"}},
+			},
+			"fields": []string{"id", "name"},
+			"slice":  []uint64{10, 30},
+		},
+	}
+
+	chunks := ChunkConfig{Size: 500}.split(body)
+
+	require.Len(t, chunks, 3)
+	for i, chunk := range chunks {
+		kwargs := chunk.Kwargs.(map[string]any)
+		filter := kwargs["filter"].([]any)
+		require.Len(t, filter, 3)
+		assert.Equal(t, []any{"project_id", "==", "CmfProject:1"}, filter[0])
+		assert.Equal(t, []any{"lists", "IN", []any{"SPR-1", "SPR-2"}}, filter[2])
+		idCond := filter[1].([]any)
+		assert.Equal(t, "IN", idCond[1])
+		assert.Len(t, idCond[2], []int{500, 500, 200}[i])
+		assert.Equal(t, []string{"id", "name"}, kwargs["fields"])
+		assert.Equal(t, []int{0, 30}, kwargs["slice"])
+		assert.NotEqual(t, body.CallID, chunk.CallID)
+	}
+}
+
+func TestChunkConfig_Split_NotNeeded(t *testing.T) {
+	ids := taskIDs(600)
+	tests := []struct {
+		name    string
+		cfg     ChunkConfig
+		method  string
+		filter  any
+		groupBy []string
+	}{
+		{"small IN", ChunkConfig{Size: 1000}, "CmfTask.list", []any{"id", "IN", ids}, nil},
+		{"disabled", ChunkConfig{}, "CmfTask.list", []any{"id", "IN", ids}, nil},
+		{"count on m2m field", ChunkConfig{Size: 500}, "CmfTask.count", []any{"lists", "IN", ids}, nil},
+		{"grouped count", ChunkConfig{Size: 500}, "CmfTask.count", []any{"id", "IN", ids}, []string{"cache_status_type"}},
+		{"NOT IN", ChunkConfig{Size: 500}, "CmfTask.list", []any{"id", "NOT IN", ids}, nil},
+		{"IN under OR", ChunkConfig{Size: 500}, "CmfTask.list", []any{[]any{"id", "IN", ids}, "or", []any{"a", "==", 1}}, nil},
+		{"no filter", ChunkConfig{Size: 500}, "CmfTask.list", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kwargs := map[string]any{"filter": tt.filter}
+			if tt.groupBy != nil {
+				kwargs["group_by"] = tt.groupBy
+			}
+			body := &RPCRequest{Method: tt.method, Kwargs: kwargs}
+
+			assert.Nil(t, tt.cfg.split(body))
+		})
+	}
+}
+
+func TestClient_ChunkedList_MergesDedupesOrdersAndSlices(t *testing.T) {
+	client, err := NewClient(&Config{BaseURL: "https://api.eva.team", APIToken: "test"},
+		WithChunking(ChunkConfig{Size: 100, Concurrency: 2}))
+	require.NoError(t, err)
+
+	priority := func(id string) int {
+		var n int
+		_, _ = fmt.Sscanf(id, "CmfTask:%d", &n)
+		return n % 7
+	}
+	mockHTTP := &chunkHTTPClient{respond: func(kwargs map[string]any) *req.Response {
+		var rows []string
+		for _, id := range kwargs["filter"].([]any)[2].([]any) {
+			rows = append(rows, fmt.Sprintf(`{"id": %q, "priority": %d}`, id, priority(id.(string))))
+		}
+		// Every chunk also returns the same extra row.
+		rows = append(rows, `{"id": "CmfTask:9999", "priority": 6}`)
+		body := `{"jsonrpc": "2.2", "result": [`
+		for i, row := range rows {
+			if i > 0 {
+				body += ","
+			}
+			body += row
+		}
+		return mockResponse(http.StatusOK, body+`], "meta": {"Project": {"class_name": "CmfTask"}}}`)
+	}}
+	client.httpClient = mockHTTP
+
+	ids := taskIDs(250)
+	qb := NewQueryBuilder().
+		Select("id", "priority").
+		From(EntityTask).
+		Where(sq.Eq{"id": ids}).
+		OrderBy("-priority", "id").
+		Offset(5).
+		Limit(20)
+	tasks, meta, err := client.TasksList(testCtx, qb)
+
+	require.NoError(t, err)
+	assert.Len(t, mockHTTP.bodies, 3)
+	for _, body := range mockHTTP.bodies {
+		assert.Contains(t, body, `"slice":[0,25]`)
+	}
+
+	expected := append(slices.Clone(ids), "CmfTask:9999")
+	sort.SliceStable(expected, func(i, j int) bool {
+		pi, pj := priority(expected[i]), priority(expected[j])
+		if pi != pj {
+			return pi > pj
+		}
+		return expected[i] < expected[j]
+	})
+	got := make([]string, len(tasks))
+	for i := range tasks {
+		got[i] = tasks[i].ID
+	}
+	assert.Equal(t, expected[5:25], got)
+	require.NotNil(t, meta)
+	assert.Equal(t, "CmfTask", meta.Project.ClassName)
+}
+
+func TestClient_ChunkedList_ChunkError(t *testing.T) {
+	client, err := NewClient(&Config{BaseURL: "https://api.eva.team", APIToken: "test"},
+		WithChunking(ChunkConfig{Size: 100, Concurrency: 4}))
+	require.NoError(t, err)
+	client.httpClient = &chunkHTTPClient{respond: func(kwargs map[string]any) *req.Response {
+		if len(kwargs["filter"].([]any)[2].([]any)) < 100 {
+			return mockResponse(http.StatusForbidden, "forbidden")
+		}
+		return mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": []}`)
+	}}
+
+	_, _, err = List[models.Person](testCtx, client, NewQueryBuilder().
+		From(EntityPerson).
+		Where(sq.Eq{"id": taskIDs(150)}))
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestClient_ChunkedCount_SumsChunks(t *testing.T) {
+	client, err := NewClient(&Config{BaseURL: "https://api.eva.team", APIToken: "test"},
+		WithChunking(ChunkConfig{Size: 100, Concurrency: 2}))
+	require.NoError(t, err)
+	mockHTTP := &chunkHTTPClient{respond: func(kwargs map[string]any) *req.Response {
+		ids := kwargs["filter"].([]any)[2].([]any)
+		return mockResponse(http.StatusOK, fmt.Sprintf(`{"jsonrpc": "2.2", "result": %d}`, len(ids)))
+	}}
+	client.httpClient = mockHTTP
+
+	ids := taskIDs(250)
+	count, err := client.TaskCount(testCtx, NewQueryBuilder().
+		From(EntityTask).
+		Where(sq.Eq{"id": append(ids, ids[:10]...)}))
+
+	require.NoError(t, err)
+	assert.Equal(t, 250, count, "repeated IDs are counted once")
+	assert.Len(t, mockHTTP.bodies, 3)
+}
+
+// failFastHTTPClient fails the first request and holds the others until
+// their ctx is cancelled.
+type failFastHTTPClient struct {
+	mu        sync.Mutex
+	calls     int
+	cancelled int
+}
+
+func (m *failFastHTTPClient) Post(ctx context.Context, _ []byte, _ string) (*req.Response, error) {
+	m.mu.Lock()
+	m.calls++
+	first := m.calls == 1
+	m.mu.Unlock()
+	if first {
+		return mockResponse(http.StatusForbidden, "forbidden"), nil
+	}
+
+	<-ctx.Done()
+	m.mu.Lock()
+	m.cancelled++
+	m.mu.Unlock()
+
+	return nil, ctx.Err()
+}
+
+func TestClient_ChunkedList_ChunkErrorCancelsSiblings(t *testing.T) {
+	client, err := NewClient(&Config{BaseURL: "https://api.eva.team", APIToken: "test"},
+		WithChunking(ChunkConfig{Size: 100, Concurrency: 2}))
+	require.NoError(t, err)
+	mockHTTP := &failFastHTTPClient{}
+	client.httpClient = mockHTTP
+
+	_, _, err = List[models.Person](testCtx, client, NewQueryBuilder().
+		From(EntityPerson).
+		Where(sq.Eq{"id": taskIDs(1000)}))
+
+	require.ErrorIs(t, err, ErrForbidden)
+	assert.Less(t, mockHTTP.calls, 10, "no chunk is sent after the failure")
+	assert.Equal(t, mockHTTP.calls-1, mockHTTP.cancelled)
+}
@@ -10,12 +10,15 @@ package evateamclient
 
 import (
 	"context"
+	"net/http"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/imroc/req/v3"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -33,10 +36,19 @@ type httpClient struct {
 }
 
 func (h *httpClient) Post(ctx context.Context, body []byte, url string) (*req.Response, error) {
-	return h.hc.R().
+	r := h.hc.R().
 		SetContext(ctx).
-		SetBodyBytes(body).
-		Post(url)
+		SetBodyBytes(body)
+	for key, values := range requestHeaderFromContext(ctx) {
+		if r.Headers == nil {
+			r.Headers = make(http.Header)
+		}
+		for _, value := range values {
+			r.Headers.Add(key, value)
+		}
+	}
+
+	return r.Post(url)
 }
 
 // Client is the EVA Team API client
@@ -45,8 +57,27 @@ type Client struct {
 	baseURL    *url.URL
 	apiToken   string
 	httpClient HTTPClient
-	logger     Logger
-	debug      bool
+	// streamClient sends file uploads and downloads, see stream.
+	streamClient *http.Client
+	logger       Logger
+	retryPolicy  *RetryPolicy
+	rateLimiter  *rateLimiter
+	breakers     *circuitBreakers
+	cache        *responseCache
+	chunking     ChunkConfig
+	flights      *flightGroup
+	schemas      *SchemaRegistry
+	middlewares  []Middleware
+	tracer       trace.Tracer
+	debug        bool
+	// validateWrites enables the checks of WithWriteValidation.
+	validateWrites bool
+	// serverGroupBy enables <Entity>.count with group_by, see WithServerGroupBy.
+	serverGroupBy bool
+	// batchUnsupported is set once the server rejected a JSON-RPC batch.
+	batchUnsupported atomic.Bool
+	// groupByUnsupported is set once the server rejected or ignored group_by.
+	groupByUnsupported atomic.Bool
 }
 
 // Config holds client configuration
@@ -100,11 +131,14 @@ func NewClient(cfg *Config, opts ...Option) (*Client, error) {
 		SetCommonHeader("Content-Type", "application/json")
 
 	c := &Client{
-		baseURL:    baseURL.JoinPath(basePath),
-		apiToken:   cfg.APIToken,
-		httpClient: &httpClient{hc: hc},
-		debug:      cfg.Debug,
+		baseURL:      baseURL.JoinPath(basePath),
+		apiToken:     cfg.APIToken,
+		httpClient:   &httpClient{hc: hc},
+		streamClient: &http.Client{Transport: hc.GetClient().Transport},
+		debug:        cfg.Debug,
+		chunking:     DefaultChunkConfig,
 	}
+	c.schemas = NewSchemaRegistry(c)
 
 	for _, opt := range opts {
 		opt(c)
@@ -126,69 +160,162 @@ func (c *Client) doRequest(ctx context.Context, body *RPCRequest, result any) er
 		return errors.WithStack(ErrRPCMethodIsRequired)
 	}
 
-	reqURL := c.baseURL.String() + "?m=" + url.QueryEscape(body.Method)
-	const skip = 2
-	fname := functionName(skip)
-	startTime := time.Now()
-
-	var (
-		err           error
-		reqBodyBytes  []byte
-		respBodyBytes []byte
-		statusCode    int
-	)
+	if err := c.validateWrite(ctx, body); err != nil {
+		return err
+	}
 
-	defer func() {
-		if c.metrics != nil {
-			c.metrics.RecordRequestDuration(statusCode, body.Method, c.baseURL.Host, fname, time.Since(startTime).Seconds())
+	// A write may have been applied even if it failed, so it always
+	// invalidates the cached reads of its entity.
+	defer c.cacheInvalidate(ctx, body.Method)
+
+	cacheKey, cacheTTL := c.cache.lookup(body)
+	respBodyBytes, cached := c.cacheGet(ctx, cacheKey)
+	if !cached {
+		const skip = 2
+		var err error
+		respBodyBytes, err = c.send(ctx, body, functionName(skip))
+		if err != nil {
+			return err
 		}
-		if c.debug {
-			c.logDebug(ctx, "Request",
-				"method", body.Method,
-				"url", reqURL,
-				"func", fname,
-				"requestBody", string(reqBodyBytes),
-				"responseBody", string(respBodyBytes),
-				"responseStatus", statusCode,
-				"duration", time.Since(startTime).String(),
-				"error", err,
-			)
-		}
-	}()
-
-	reqBodyBytes, err = json.Marshal(body)
-	if err != nil {
-		return errors.WithMessage(err, "marshal request body")
+		c.cacheSet(ctx, cacheKey, respBodyBytes, cacheTTL)
 	}
 
-	resp, err := c.httpClient.Post(ctx, reqBodyBytes, reqURL)
-	if err != nil {
-		return errors.WithMessage(err, "http request failed")
+	if result != nil {
+		if err := json.Unmarshal(respBodyBytes, result); err != nil {
+			return errors.WithMessage(err, "unmarshal response body")
+		}
 	}
 
-	statusCode = resp.StatusCode
-	respBodyBytes = resp.Bytes()
+	return nil
+}
 
-	if resp.IsErrorState() {
-		return errors.Errorf("API error %d: %s", resp.StatusCode, string(resp.Bytes()))
+// call sends a single request and returns the raw response body of a
+// successful call; an RPC error in a 200 OK body is returned as *APIError.
+func (c *Client) call(ctx context.Context, body *RPCRequest, fname string) ([]byte, error) {
+	respBodyBytes, statusCode, err := c.roundTrip(ctx, body, fname)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check for RPC error in 200 OK response
 	var rpcErr rpcErrorResponse
 	if err := json.Unmarshal(respBodyBytes, &rpcErr); err != nil {
-		return errors.WithMessage(err, "unmarshal rpc error check")
+		return nil, errors.WithMessage(err, "unmarshal rpc error check")
 	}
 	if rpcErr.Error != nil {
-		return errors.WithMessagef(rpcErr.Error, "RPC error %d", rpcErr.Error.Code)
+		return nil, errors.WithStack(newRPCError(body.Method, body.CallID, statusCode, rpcErr.Error, respBodyBytes))
 	}
 
-	if result != nil {
-		if err := json.Unmarshal(respBodyBytes, result); err != nil {
-			return errors.WithMessage(err, "unmarshal response body")
-		}
+	return respBodyBytes, nil
+}
+
+// roundTrip sends a single or batch request through the middleware chain
+// and returns the raw response body. Non-2xx responses are turned into an
+// *APIError; RPC errors in the body are left to the caller.
+func (c *Client) roundTrip(ctx context.Context, body *RPCRequest, fname string) ([]byte, int, error) {
+	resp, err := c.handler()(withCaller(ctx, fname), body)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return nil
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, resp.StatusCode, errors.WithStack(newHTTPError(body.Method, body.CallID, resp.StatusCode, resp.Body))
+	}
+
+	return resp.Body, resp.StatusCode, nil
+}
+
+// transport is the innermost RoundTripFunc: it sends the request over HTTP.
+func (c *Client) transport(ctx context.Context, body *RPCRequest) (*RPCResponse, error) {
+	reqBodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.WithMessage(err, "marshal request body")
+	}
+	if len(body.Header) > 0 {
+		ctx = withRequestHeader(ctx, body.Header)
+	}
+
+	resp, err := c.post(ctx, body.Method, body.methods(), callerFromContext(ctx), reqBodyBytes, c.requestURL(body))
+	if err != nil {
+		return nil, errors.WithMessage(err, "http request failed")
+	}
+
+	var header http.Header
+	if resp.Response != nil {
+		header = resp.Header
+	}
+
+	return &RPCResponse{
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		Body:       resp.Bytes(),
+	}, nil
+}
+
+func (c *Client) requestURL(body *RPCRequest) string {
+	return c.baseURL.String() + "?m=" + url.QueryEscape(body.Method)
+}
+
+// post sends the request body, retrying transient failures according to the
+// client's retry policy. Every attempt passes the circuit breaker and waits for
+// the rate limiter first. Returns the last response or transport error.
+// method labels metrics and logs: BatchMethod for a batch, whatever methods
+// it carries, so that the label set stays bounded.
+func (c *Client) post(
+	ctx context.Context,
+	method string,
+	methods []string,
+	fname string,
+	reqBody []byte,
+	reqURL string,
+) (*req.Response, error) {
+	attempts := c.retryPolicy.attemptsFor(methods...)
+
+	for attempt := 1; ; attempt++ {
+		cb, err := c.circuitAllow()
+		if err != nil {
+			return nil, err
+		}
+
+		waited, limited, waitErr := c.rateLimiter.wait(ctx, methods)
+		if limited && c.metrics != nil {
+			c.metrics.RecordRateLimitWait(method, c.baseURL.Host, fname, waited.Seconds())
+		}
+		if waitErr != nil {
+			if cb != nil {
+				cb.release()
+			}
+			return nil, waitErr
+		}
+
+		resp, err := c.httpClient.Post(ctx, reqBody, reqURL)
+		c.circuitRecord(ctx, cb, resp, err)
+		if attempt >= attempts || !c.retryPolicy.shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+
+		var statusCode int
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		wait := c.retryPolicy.backoff(attempt, retryAfter(resp))
+
+		if c.metrics != nil {
+			c.metrics.RecordRetry(statusCode, method, c.baseURL.Host, fname)
+		}
+		c.logDebug(ctx, "Retrying request",
+			"method", method,
+			"func", fname,
+			"attempt", attempt,
+			"responseStatus", statusCode,
+			"backoff", wait.String(),
+			"error", err,
+		)
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
 }
 
 func (c *Client) logDebug(ctx context.Context, msg string, args ...any) {
@@ -0,0 +1,142 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"sync"
+)
+
+type freshReadKey struct{}
+
+// WithRequestCoalescing deduplicates identical in-flight read requests: while
+// a read (same method, args and kwargs) is running, identical reads wait for
+// it and share its response instead of sending their own HTTP call.
+//
+// The shared call is cancelled only once all of its waiters have given up,
+// or when the deadline of the caller that started it passes; a waiter whose
+// ctx ends returns ctx.Err() right away.
+//
+// Example:
+//
+//	client, err := evateamclient.NewClient(cfg, evateamclient.WithRequestCoalescing())
+func WithRequestCoalescing() Option {
+	return func(c *Client) {
+		c.flights = &flightGroup{calls: make(map[string]*flight)}
+	}
+}
+
+// FreshRead returns a ctx whose requests bypass request coalescing and the
+// response cache. The response still refreshes the cache.
+//
+// Example:
+//
+//	task, _, err := client.Task(evateamclient.FreshRead(ctx), "TASK-1", nil)
+func FreshRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadKey{}, true)
+}
+
+func isFreshRead(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadKey{}).(bool)
+	return fresh
+}
+
+// flightGroup tracks in-flight calls by key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	body    []byte
+	err     error
+}
+
+// do runs fn once per key at a time and returns its result to every caller
+// that asked for the same key meanwhile.
+func (g *flightGroup) do(
+	ctx context.Context,
+	key string,
+	fn func(ctx context.Context) ([]byte, error),
+) ([]byte, error) {
+	g.mu.Lock()
+	f, ok := g.calls[key]
+	if !ok {
+		sharedCtx, cancel := sharedContext(ctx)
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = f
+
+		go func() {
+			f.body, f.err = fn(sharedCtx)
+
+			g.mu.Lock()
+			g.forget(key, f)
+			g.mu.Unlock()
+
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.body, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody waits anymore: later callers start a new call
+			// instead of joining the cancelled one.
+			g.forget(key, f)
+			f.cancel()
+		}
+		g.mu.Unlock()
+
+		return nil, ctx.Err()
+	}
+}
+
+// sharedContext returns the ctx of a shared call: it keeps the values and
+// the deadline of the first caller's ctx but not its cancellation, so the
+// call is bounded by that deadline rather than only by the HTTP timeout.
+func sharedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+
+	return context.WithCancel(detached)
+}
+
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
+
+// callCoalesced sends a request through the flight group if it is an
+// idempotent read and coalescing is enabled.
+func (c *Client) callCoalesced(ctx context.Context, body *RPCRequest, fname string) ([]byte, error) {
+	if c.flights == nil || len(body.Calls) > 0 || !isIdempotentMethod(body.Method) || isFreshRead(ctx) {
+		return c.call(ctx, body, fname)
+	}
+	digest, ok := requestDigest(body)
+	if !ok {
+		return c.call(ctx, body, fname)
+	}
+
+	return c.flights.do(ctx, body.Method+":"+digest, func(ctx context.Context) ([]byte, error) {
+		return c.call(ctx, body, fname)
+	})
+}
@@ -0,0 +1,249 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHTTPClient holds every request until release is closed.
+type blockingHTTPClient struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+	body    string
+}
+
+func newBlockingHTTPClient(body string) *blockingHTTPClient {
+	return &blockingHTTPClient{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+		body:    body,
+	}
+}
+
+func (b *blockingHTTPClient) Post(ctx context.Context, _ []byte, _ string) (*req.Response, error) {
+	b.calls.Add(1)
+	b.started <- struct{}{}
+	select {
+	case <-b.release:
+		return mockResponse(http.StatusOK, b.body), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newCoalescingTestClient(t *testing.T, body string) (*Client, *blockingHTTPClient) {
+	t.Helper()
+
+	client, _ := newTestClient(t)
+	mockHTTP := newBlockingHTTPClient(body)
+	client.httpClient = mockHTTP
+	WithRequestCoalescing()(client)
+
+	return client, mockHTTP
+}
+
+// waitWaiters waits until n callers wait for the in-flight calls.
+func waitWaiters(t *testing.T, g *flightGroup, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		total := 0
+		for _, f := range g.calls {
+			total += f.waiters
+		}
+		return total == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestClient_Coalescing_SharesInFlightRead(t *testing.T) {
+	client, mockHTTP := newCoalescingTestClient(t, projectGetOKBody)
+
+	const n = 5
+	var wg sync.WaitGroup
+	codes := make([]string, n)
+	errs := make([]error, n)
+	for i := range n {
+		wg.Go(func() {
+			project, _, err := client.Project(testCtx, "PROJ", nil)
+			errs[i] = err
+			if project != nil {
+				codes[i] = project.Code
+			}
+		})
+	}
+
+	waitWaiters(t, client.flights, n)
+	close(mockHTTP.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), mockHTTP.calls.Load())
+	for i := range n {
+		require.NoError(t, errs[i])
+		assert.Equal(t, "PROJ", codes[i])
+	}
+}
+
+func TestClient_Coalescing_DifferentKwargsNotShared(t *testing.T) {
+	client, mockHTTP := newCoalescingTestClient(t, projectGetOKBody)
+
+	var wg sync.WaitGroup
+	for _, code := range []string{"PROJ", "OTHER"} {
+		wg.Go(func() {
+			_, _, _ = client.Project(testCtx, code, nil)
+		})
+	}
+
+	waitWaiters(t, client.flights, 2)
+	close(mockHTTP.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), mockHTTP.calls.Load())
+}
+
+func TestClient_Coalescing_FreshReadBypasses(t *testing.T) {
+	client, mockHTTP := newCoalescingTestClient(t, projectGetOKBody)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		_, _, _ = client.Project(testCtx, "PROJ", nil)
+	})
+	<-mockHTTP.started
+
+	wg.Go(func() {
+		_, _, err := client.Project(FreshRead(testCtx), "PROJ", nil)
+		assert.NoError(t, err)
+	})
+	<-mockHTTP.started
+
+	close(mockHTTP.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), mockHTTP.calls.Load())
+}
+
+func TestClient_Coalescing_WritesNotShared(t *testing.T) {
+	client, mockHTTP := newCoalescingTestClient(t, `{"jsonrpc":"2.2","result":"CmfProject:1"}`)
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			_ = client.ProjectDelete(testCtx, "CmfProject:1")
+		})
+	}
+	<-mockHTTP.started
+	<-mockHTTP.started
+
+	close(mockHTTP.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), mockHTTP.calls.Load())
+}
+
+func TestClient_Coalescing_WaiterCancelDoesNotAbortOthers(t *testing.T) {
+	client, mockHTTP := newCoalescingTestClient(t, projectGetOKBody)
+
+	ctx, cancel := context.WithCancel(testCtx)
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, _, err := client.Project(ctx, "PROJ", nil)
+		leaderErr <- err
+	}()
+	<-mockHTTP.started
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		project, _, err := client.Project(testCtx, "PROJ", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "PROJ", project.Code)
+	})
+	waitWaiters(t, client.flights, 2)
+
+	cancel()
+	require.ErrorIs(t, <-leaderErr, context.Canceled)
+
+	close(mockHTTP.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), mockHTTP.calls.Load())
+}
+
+func TestFlightGroup_LastWaiterCancelsCall(t *testing.T) {
+	g := &flightGroup{calls: make(map[string]*flight)}
+
+	ctx, cancel := context.WithCancel(testCtx)
+	callCtx := make(chan context.Context, 1)
+	done := make(chan error, 1)
+	go func() {
+		_, err := g.do(ctx, "k", func(ctx context.Context) ([]byte, error) {
+			callCtx <- ctx
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		done <- err
+	}()
+
+	shared := <-callCtx
+	cancel()
+
+	require.ErrorIs(t, <-done, context.Canceled)
+	select {
+	case <-shared.Done():
+	case <-time.After(time.Second):
+		t.Fatal("shared call was not cancelled")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	assert.Empty(t, g.calls, "a cancelled call is not joined by later callers")
+}
+
+func TestFlightGroup_SharedCallKeepsFirstCallerDeadline(t *testing.T) {
+	g := &flightGroup{calls: make(map[string]*flight)}
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(testCtx, deadline)
+	defer cancel()
+
+	_, err := g.do(ctx, "k", func(ctx context.Context) ([]byte, error) {
+		got, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, deadline, got)
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
+
+func TestClient_FreshRead_BypassesCache(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, projectGetOKBody)
+	store := NewMemoryCache(10)
+	WithCache(store)(client)
+
+	_, _, err := client.Project(testCtx, "PROJ", nil)
+	require.NoError(t, err)
+	_, _, err = client.Project(FreshRead(testCtx), "PROJ", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, mockHTTP.calls)
+	assert.Equal(t, 1, store.Len(), "a fresh read refreshes the cached entry")
+}
@@ -0,0 +1,361 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	encjson "encoding/json"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+// Company field constants for type-safe queries
+const (
+	CompanyFieldID            = "id"
+	CompanyFieldClassName     = "class_name"
+	CompanyFieldCode          = "code"
+	CompanyFieldName          = "name"
+	CompanyFieldINN           = "inn"
+	CompanyFieldKPP           = "kpp"
+	CompanyFieldOGRN          = "ogrn"
+	CompanyFieldCmfOwnerID    = "cmf_owner_id"
+	CompanyFieldCmfCreatedAt  = "cmf_created_at"
+	CompanyFieldCmfModifiedAt = "cmf_modified_at"
+	CompanyFieldCmfDeleted    = "cmf_deleted"
+)
+
+var (
+	// DefaultCompanyFields - standard projection for single company queries
+	DefaultCompanyFields = []string{
+		CompanyFieldID,
+		CompanyFieldClassName,
+		CompanyFieldCode,
+		CompanyFieldName,
+		CompanyFieldINN,
+		CompanyFieldKPP,
+		CompanyFieldOGRN,
+		CompanyFieldCmfOwnerID,
+		CompanyFieldCmfCreatedAt,
+		CompanyFieldCmfModifiedAt,
+	}
+
+	// DefaultCompanyListFields - optimized for LIST queries (lighter payload)
+	DefaultCompanyListFields = []string{
+		CompanyFieldID,
+		CompanyFieldCode,
+		CompanyFieldName,
+		CompanyFieldINN,
+	}
+)
+
+// Company retrieves a single company by ID or code
+// Example:
+//
+//	company, meta, err := client.Company(ctx, "CMP-000001", nil)
+func (c *Client) Company(
+	ctx context.Context,
+	idOrCode string,
+	fields []string,
+) (*models.Company, *models.Meta, error) {
+	field := CompanyFieldCode
+	if strings.Contains(idOrCode, ":") {
+		field = CompanyFieldID
+	}
+
+	qb := NewQueryBuilder().
+		Select(fields...).
+		From(EntityCompany).
+		Where(sq.Eq{field: idOrCode}).
+		Limit(1)
+
+	return c.CompanyQuery(ctx, qb)
+}
+
+// CompanyQuery executes query using REAL Squirrel API
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  Select("id", "name", "inn").
+//	  From(evateamclient.EntityCompany).
+//	  Where(sq.Eq{"inn": "7701234567"})
+//	company, meta, err := client.CompanyQuery(ctx, qb)
+func (c *Client) CompanyQuery(ctx context.Context, qb *QueryBuilder) (*models.Company, *models.Meta, error) {
+	kwargs, err := qb.ToKwargs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, hasFields := kwargs["fields"]; !hasFields {
+		kwargs["fields"] = DefaultCompanyFields
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfCompany.get",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp models.CompanyResponse
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get company")
+	}
+
+	return &resp.Result, &resp.Meta, nil
+}
+
+// CompaniesList retrieves list using REAL Squirrel
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityCompany).
+//	  Where(sq.ILike{"name": "%soft%"}).
+//	  OrderBy("name")
+//	companies, meta, err := client.CompaniesList(ctx, qb)
+func (c *Client) CompaniesList(ctx context.Context, qb *QueryBuilder) ([]models.Company, *models.Meta, error) {
+	kwargs, err := qb.From(EntityCompany).ToKwargs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, hasFields := kwargs["fields"]; !hasFields {
+		kwargs["fields"] = DefaultCompanyListFields
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfCompany.list",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp models.CompanyListResponse
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get companies")
+	}
+
+	return resp.Result, &resp.Meta, nil
+}
+
+// CompanyCount counts using REAL Squirrel
+// Example:
+//
+//	count, err := client.CompanyCount(ctx, evateamclient.NewQueryBuilder())
+func (c *Client) CompanyCount(ctx context.Context, qb *QueryBuilder) (int, error) {
+	count, err := Count(ctx, c, qb.From(EntityCompany))
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get company count")
+	}
+
+	return count, nil
+}
+
+// CompanyPersons retrieves the persons linked to a company.
+//
+// Neither the OAS nor the library knows the field of CmfPerson referencing
+// CmfCompany: the OAS documents no CmfPerson fields. The field is looked up
+// in the live schema of CmfPerson (Client.Schemas) as the one whose meta
+// has class_name CmfCompany. When there is none, CompanyPersons fails with
+// ErrNoRelation rather than send a filter that matches nothing.
+// Example:
+//
+//	persons, meta, err := client.CompanyPersons(ctx, "CmfCompany:uuid", nil)
+func (c *Client) CompanyPersons(
+	ctx context.Context,
+	companyID string,
+	fields []string,
+) ([]models.Person, *models.Meta, error) {
+	field, err := c.companyRelation(ctx, EntityPerson)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	qb := NewQueryBuilder().
+		Select(fields...).
+		From(EntityPerson).
+		Where(sq.Eq{field: companyID}).
+		OrderBy(PersonFieldName)
+
+	return c.PersonsList(ctx, qb)
+}
+
+// CompanyProjects retrieves the projects linked to a company. The field of
+// CmfProject referencing CmfCompany is looked up in the live schema, as for
+// CompanyPersons; without one it fails with ErrNoRelation.
+// Example:
+//
+//	projects, meta, err := client.CompanyProjects(ctx, "CmfCompany:uuid", nil)
+func (c *Client) CompanyProjects(
+	ctx context.Context,
+	companyID string,
+	fields []string,
+) ([]models.Project, *models.Meta, error) {
+	field, err := c.companyRelation(ctx, EntityProject)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	qb := NewQueryBuilder().
+		Select(fields...).
+		From(EntityProject).
+		Where(sq.Eq{field: companyID}).
+		OrderBy(ProjectFieldName)
+
+	return c.ProjectsList(ctx, qb)
+}
+
+// companyRelation returns the field of entity referencing CmfCompany in
+// its live schema.
+func (c *Client) companyRelation(ctx context.Context, entity string) (string, error) {
+	schema, err := c.Schemas().Schema(ctx, entity)
+	if err != nil {
+		return "", err
+	}
+	field, ok := schema.RelationTo(EntityCompany)
+	if !ok {
+		return "", errors.Wrapf(ErrNoRelation, "%s has no field referencing %s", entity, EntityCompany)
+	}
+
+	return field, nil
+}
+
+// CRUD Operations
+
+// CompanyCreateParams contains parameters for creating a new company
+type CompanyCreateParams struct {
+	Name string `json:"name"`
+	INN  string `json:"inn,omitempty"`
+	KPP  string `json:"kpp,omitempty"`
+	OGRN string `json:"ogrn,omitempty"`
+}
+
+// CompanyCreate creates a new company
+// Example:
+//
+//	company, err := client.CompanyCreate(ctx, &evateamclient.CompanyCreateParams{
+//	  Name: "ООО «Пример»",
+//	  INN:  "7701234567",
+//	})
+func (c *Client) CompanyCreate(
+	ctx context.Context,
+	params *CompanyCreateParams,
+) (*models.Company, error) {
+	if params == nil || params.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	kwargs := map[string]any{
+		CompanyFieldName: params.Name,
+	}
+	if params.INN != "" {
+		kwargs[CompanyFieldINN] = params.INN
+	}
+	if params.KPP != "" {
+		kwargs[CompanyFieldKPP] = params.KPP
+	}
+	if params.OGRN != "" {
+		kwargs[CompanyFieldOGRN] = params.OGRN
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfCompany.create",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp struct {
+		JSONRPC string             `json:"jsonrpc"`
+		Result  encjson.RawMessage `json:"result"`
+	}
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+
+	return parseWriteResult(ctx, resp.Result, "CmfCompany.create", c.companyByID, companyHasEmptyID)
+}
+
+// companyByID fetches a company by ID or code, for the two-phase
+// create/update follow-up `.get` when CmfCompany.create/update returns a bare
+// string.
+func (c *Client) companyByID(ctx context.Context, idOrCode string) (*models.Company, error) {
+	company, _, err := c.Company(ctx, idOrCode, DefaultCompanyFields)
+	return company, err
+}
+
+func companyHasEmptyID(company *models.Company) bool {
+	return company == nil || company.ID == ""
+}
+
+// CompanyUpdate updates an existing company. The OAS allows name, inn, kpp,
+// ogrn and cmf_owner (owner login).
+// Example:
+//
+//	updates := map[string]any{
+//	  "kpp": "770101001",
+//	}
+//	company, err := client.CompanyUpdate(ctx, "CmfCompany:uuid", updates)
+func (c *Client) CompanyUpdate(
+	ctx context.Context,
+	companyID string,
+	updates map[string]any,
+) (*models.Company, error) {
+	if companyID == "" {
+		return nil, errors.New("companyID is required")
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfCompany.update",
+		CallID:  newCallID(),
+		Args:    []any{companyID},
+		Kwargs:  updates,
+	}
+
+	var resp struct {
+		JSONRPC string             `json:"jsonrpc"`
+		Result  encjson.RawMessage `json:"result"`
+	}
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+
+	return parseWriteResult(ctx, resp.Result, "CmfCompany.update", c.companyByID, companyHasEmptyID)
+}
+
+// CompanyDelete deletes a company by ID. The OAS omits CmfCompany.delete;
+// it is the generic CMF delete every model exposes.
+// Example:
+//
+//	err := client.CompanyDelete(ctx, "CmfCompany:uuid")
+func (c *Client) CompanyDelete(
+	ctx context.Context,
+	companyID string,
+) error {
+	if companyID == "" {
+		return errors.New("companyID is required")
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfCompany.delete",
+		CallID:  newCallID(),
+		Args:    []any{companyID},
+	}
+
+	var resp struct {
+		JSONRPC string `json:"jsonrpc"`
+		Result  any    `json:"result"`
+	}
+
+	return c.doRequest(ctx, reqBody, &resp)
+}
@@ -0,0 +1,174 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	encjson "encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/imroc/req/v3"
+	"github.com/raoptimus/evateamclient.go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Company_ByCode_FiltersByCode(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": {
+		"id": "CmfCompany:1", "code": "CMP-000001", "name": "Acme", "inn": "7701234567"
+	}}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfCompany.get")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"filter":["code","==","CMP-000001"]`)
+	}
+
+	company, _, err := client.Company(testCtx, "CMP-000001", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "CmfCompany:1", company.ID)
+	assert.Equal(t, "7701234567", company.INN)
+}
+
+func TestClient_CompaniesList_Success_ReturnsCompanies(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": [
+		{"id": "CmfCompany:1", "name": "Acme"},
+		{"id": "CmfCompany:2", "name": "Globex"}
+	]}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfCompany.list")
+	}
+
+	companies, _, err := client.CompaniesList(testCtx, NewQueryBuilder())
+
+	require.NoError(t, err)
+	assert.Len(t, companies, 2)
+}
+
+func TestClient_CompanyCreate_ResultIsIDString_FetchesCreatedCompany(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":"CmfCompany:new-1"}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfCompany:new-1","name":"Acme","inn":"7701234567"}}`),
+	}
+	var createKwargs map[string]any
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		var parsed struct {
+			Method string         `json:"method"`
+			Kwargs map[string]any `json:"kwargs"`
+		}
+		if err := encjson.Unmarshal(body, &parsed); !assert.NoError(t, err) {
+			return false
+		}
+		if parsed.Method == "CmfCompany.create" {
+			createKwargs = parsed.Kwargs
+		}
+		return true
+	}
+
+	company, err := client.CompanyCreate(testCtx, &CompanyCreateParams{Name: "Acme", INN: "7701234567"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "CmfCompany:new-1", company.ID)
+	assert.Equal(t, map[string]any{"name": "Acme", "inn": "7701234567"}, createKwargs)
+	assert.Equal(t, 2, mockHTTP.callIdx, "expected create + follow-up get")
+}
+
+func TestClient_CompanyCreate_EmptyName_ReturnsErrorWithoutRequest(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.err = errors.New("must not be called")
+
+	company, err := client.CompanyCreate(testCtx, &CompanyCreateParams{INN: "7701234567"})
+
+	require.Error(t, err)
+	assert.Nil(t, company)
+	assert.Equal(t, 0, mockHTTP.calls, "validation must fail before any HTTP request")
+}
+
+func TestClient_CompanyUpdate_Success_SendsIDInArgs(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfCompany:1","kpp":"770101001"}}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfCompany.update")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		var parsed struct {
+			Args   []any          `json:"args"`
+			Kwargs map[string]any `json:"kwargs"`
+		}
+		if err := encjson.Unmarshal(body, &parsed); !assert.NoError(t, err) {
+			return false
+		}
+		return assert.Equal(t, []any{"CmfCompany:1"}, parsed.Args) &&
+			assert.Equal(t, map[string]any{"kpp": "770101001"}, parsed.Kwargs)
+	}
+
+	company, err := client.CompanyUpdate(testCtx, "CmfCompany:1", map[string]any{"kpp": "770101001"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "770101001", company.KPP)
+}
+
+func TestClient_CompanyDelete_Success_SendsIDInArgs(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":true}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfCompany.delete")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"args":["CmfCompany:1"]`)
+	}
+
+	err := client.CompanyDelete(testCtx, "CmfCompany:1")
+
+	assert.NoError(t, err)
+}
+
+func TestClient_CompanyPersons_FiltersBySchemaRelation(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	client.Schemas().Store(EntityPerson, &models.Meta{Project: models.ProjectMeta{
+		Fields: map[string]models.FieldMeta{
+			"name":        {APIAllow: true},
+			"company_ref": {APIAllow: true, ClassName: EntityCompany},
+		},
+	}})
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": [
+		{"id": "CmfPerson:1", "name": "Alice"}
+	]}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfPerson.list")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"filter":["company_ref","==","CmfCompany:1"]`)
+	}
+
+	persons, _, err := client.CompanyPersons(testCtx, "CmfCompany:1", nil)
+
+	require.NoError(t, err)
+	require.Len(t, persons, 1)
+	assert.Equal(t, "Alice", persons[0].Name)
+}
+
+func TestClient_CompanyProjects_NoRelation_ReturnsErrNoRelation(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	client.Schemas().Store(EntityProject, &models.Meta{Project: models.ProjectMeta{
+		Fields: map[string]models.FieldMeta{
+			"name": {APIAllow: true},
+		},
+	}})
+
+	_, _, err := client.CompanyProjects(testCtx, "CmfCompany:1", nil)
+
+	require.ErrorIs(t, err, ErrNoRelation)
+	assert.Zero(t, mockHTTP.calls)
+}
@@ -0,0 +1,429 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"context"
+	encjson "encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+// maxArchiveReply caps the JSON reply read from
+// CmfDocument.download_all_attachment.
+const maxArchiveReply = 1 << 20
+
+// errNoServerArchive reports a download_all_attachment reply holding
+// neither an archive nor its URL.
+var errNoServerArchive = errors.New("no archive in reply")
+
+// ArchiveProgress is reported while DocumentDownloadAllAttachments writes
+// the archive.
+type ArchiveProgress struct {
+	// Path is the archive path of the file just added; empty for the
+	// server archive.
+	Path string
+	// Files is the number of files added so far out of Total; both are 0
+	// for the server archive.
+	Files int
+	Total int
+	// Bytes is the number of bytes written to w so far.
+	Bytes int64
+}
+
+// ArchiveOption configures DocumentDownloadAllAttachments.
+type ArchiveOption func(*archiveConfig)
+
+type archiveConfig struct {
+	onProgress func(ArchiveProgress)
+	clientSide bool
+}
+
+// OnArchiveProgress calls fn after each file added to the client-side
+// archive, or after each chunk of the server archive written to w.
+func OnArchiveProgress(fn func(ArchiveProgress)) ArchiveOption {
+	return func(cfg *archiveConfig) {
+		cfg.onProgress = fn
+	}
+}
+
+// ClientSideArchive skips CmfDocument.download_all_attachment and always
+// builds the archive on the client.
+func ClientSideArchive() ArchiveOption {
+	return func(cfg *archiveConfig) {
+		cfg.clientSide = true
+	}
+}
+
+// DocumentDownloadAllAttachments streams a zip archive of the attachments of
+// a document (by ID) to w.
+//
+// The archive is produced by the server with
+// CmfDocument.download_all_attachment. When the server cannot produce it
+// (the method is rejected, fails with an unclassified error or replies
+// without an archive), the archive is built on the client instead: the
+// document subtree is walked with DocumentPageTree and every attachment is
+// stored under the path of its document in the tree, e.g.
+// "Handbook/Onboarding/checklist.pdf". Attachments are streamed one by one,
+// so memory use does not depend on their size.
+//
+// Nothing is written to w before the mode is decided, but w holds a partial
+// archive when an error is returned midway.
+//
+// Example:
+//
+//	f, err := os.Create("handbook.zip")
+//	...
+//	defer f.Close()
+//	err = client.DocumentDownloadAllAttachments(ctx, "CmfDocument:uuid", f,
+//	  evateamclient.OnArchiveProgress(func(p evateamclient.ArchiveProgress) {
+//	    log.Printf("%d/%d %s", p.Files, p.Total, p.Path)
+//	  }))
+func (c *Client) DocumentDownloadAllAttachments(
+	ctx context.Context,
+	docID string,
+	w io.Writer,
+	opts ...ArchiveOption,
+) error {
+	if docID == "" {
+		return errors.New("docID is required")
+	}
+
+	var cfg archiveConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if !cfg.clientSide {
+		err := c.serverDocumentArchive(ctx, docID, w, cfg.onProgress)
+		if !serverArchiveUnavailable(err) {
+			return err
+		}
+	}
+
+	return c.clientDocumentArchive(ctx, docID, w, cfg.onProgress)
+}
+
+// serverDocumentArchive streams the archive of CmfDocument.download_all_attachment:
+// the response body itself, or the file at the URL of a JSON-RPC result.
+func (c *Client) serverDocumentArchive(
+	ctx context.Context,
+	docID string,
+	w io.Writer,
+	onProgress func(ArchiveProgress),
+) error {
+	const method = "CmfDocument.download_all_attachment"
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  method,
+		CallID:  newCallID(),
+		Args:    []any{docID},
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return errors.WithMessage(err, "marshal request body")
+	}
+
+	resp, err := c.stream(ctx, http.MethodPost, c.requestURL(reqBody), method, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if onProgress != nil {
+		w = &progressWriter{w: w, report: onProgress}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		if _, err := io.Copy(w, resp.Body); err != nil {
+			return errors.WithMessagef(err, "%s: copy body", method)
+		}
+		return nil
+	}
+
+	var rpcResp struct {
+		Result encjson.RawMessage `json:"result"`
+		Error  *RPCError          `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxArchiveReply)).Decode(&rpcResp); err != nil {
+		return errors.WithMessage(err, "unmarshal response body")
+	}
+	if rpcResp.Error != nil {
+		return errors.WithStack(newRPCError(method, reqBody.CallID, resp.StatusCode, rpcResp.Error, nil))
+	}
+
+	archiveURL := archiveReplyURL(rpcResp.Result)
+	if archiveURL == "" {
+		return errors.Wrap(errNoServerArchive, method)
+	}
+	_, err = c.download(ctx, archiveURL, method, w)
+
+	return err
+}
+
+// archiveReplyURL returns the archive URL of a download_all_attachment
+// result: a string, or an object with a url.
+func archiveReplyURL(result encjson.RawMessage) string {
+	var s string
+	if json.Unmarshal(result, &s) == nil {
+		return s
+	}
+	var obj struct {
+		URL string `json:"url"`
+	}
+	if json.Unmarshal(result, &obj) == nil {
+		return obj.URL
+	}
+
+	return ""
+}
+
+// serverArchiveUnavailable reports whether err means the server cannot
+// produce the archive, as opposed to a failure the client-side archive
+// would hit too (auth, rate limits, outages, cancellation).
+func serverArchiveUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, errNoServerArchive) || errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrMethodNotFound) || errors.Is(err, ErrValidation) {
+		return true
+	}
+
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Kind() == nil
+}
+
+// clientDocumentArchive zips the attachments of the document subtree of
+// docID into w.
+func (c *Client) clientDocumentArchive(
+	ctx context.Context,
+	docID string,
+	w io.Writer,
+	onProgress func(ArchiveProgress),
+) error {
+	dirs, err := c.documentTreeDirs(ctx, docID)
+	if err != nil {
+		return err
+	}
+
+	docIDs := make([]string, 0, len(dirs))
+	for id := range dirs {
+		docIDs = append(docIDs, id)
+	}
+	slices.Sort(docIDs)
+
+	qb := NewQueryBuilder().
+		Select(DefaultAttachmentFields...).
+		From(EntityAttachment).
+		Where(sq.Eq{AttachmentFieldParentID: docIDs}).
+		OrderBy(AttachmentFieldCmfCreatedAt, AttachmentFieldID)
+	var attachments []models.Attachment
+	for att, err := range Iter[models.Attachment](ctx, c, qb) {
+		if err != nil {
+			return errors.WithMessage(err, "list document attachments")
+		}
+		attachments = append(attachments, att)
+	}
+
+	files := archiveFiles(dirs, attachments)
+	cw := &progressWriter{w: w}
+	zw := zip.NewWriter(cw)
+	for i, file := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.path,
+			Method:   zip.Deflate,
+			Modified: file.att.CmfCreatedAt,
+		})
+		if err != nil {
+			return errors.WithMessagef(err, "add %s", file.path)
+		}
+		if file.att.URL != "" {
+			_, err = c.download(ctx, file.att.URL, "CmfAttachment.download", fw)
+		} else {
+			_, err = c.AttachmentDownload(ctx, file.att.ID, fw)
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "add %s", file.path)
+		}
+
+		if onProgress != nil {
+			onProgress(ArchiveProgress{Path: file.path, Files: i + 1, Total: len(files), Bytes: cw.n})
+		}
+	}
+
+	return errors.WithMessage(zw.Close(), "close archive")
+}
+
+// documentTreeDirs maps each document of the subtree of docID to its
+// archive directory, the path of names from the root document.
+func (c *Client) documentTreeDirs(ctx context.Context, docID string) (map[string]string, error) {
+	docs := map[string]models.Document{}
+	children := map[string][]string{}
+	add := func(doc models.Document) {
+		if _, ok := docs[doc.ID]; ok || doc.ID == "" {
+			return
+		}
+		docs[doc.ID] = doc
+		if doc.ID != docID {
+			children[doc.ParentID] = append(children[doc.ParentID], doc.ID)
+		}
+	}
+
+	// The page tree may return one level only: expand the branches whose
+	// children are missing.
+	expanded := map[string]bool{}
+	for queue := []string{docID}; len(queue) > 0; queue = queue[1:] {
+		nodeID := queue[0]
+		if expanded[nodeID] {
+			continue
+		}
+		expanded[nodeID] = true
+
+		nodes, err := c.DocumentPageTree(ctx, nodeID)
+		if err != nil {
+			return nil, errors.WithMessage(err, "walk document tree")
+		}
+		for _, doc := range nodes {
+			add(doc)
+		}
+		for _, doc := range nodes {
+			if doc.TreeNodeIsBranch && len(children[doc.ID]) == 0 {
+				queue = append(queue, doc.ID)
+			}
+		}
+	}
+
+	if _, ok := docs[docID]; !ok {
+		root, err := c.documentByID(ctx, docID)
+		if err != nil {
+			return nil, err
+		}
+		if documentHasEmptyID(root) {
+			return nil, errors.Wrapf(ErrNotFound, "document %s", docID)
+		}
+		docs[docID] = *root
+	}
+
+	dirs := make(map[string]string, len(docs))
+	var walk func(id, dir string)
+	walk = func(id, dir string) {
+		dirs[id] = dir
+		kids := children[id]
+		slices.SortStableFunc(kids, func(a, b string) int {
+			return cmp.Compare(docs[a].OrderNo, docs[b].OrderNo)
+		})
+		names := archiveNames{}
+		for _, kid := range kids {
+			if _, seen := dirs[kid]; !seen {
+				walk(kid, path.Join(dir, names.unique(archiveName(docs[kid].Name, docs[kid].Code))))
+			}
+		}
+	}
+	walk(docID, archiveName(docs[docID].Name, docs[docID].Code))
+
+	return dirs, nil
+}
+
+// archiveFile is an attachment and its path in the archive.
+type archiveFile struct {
+	path string
+	att  models.Attachment
+}
+
+// archiveFiles places the attachments in the directories of their
+// documents, with names unique per directory, subdirectories included.
+func archiveFiles(dirs map[string]string, attachments []models.Attachment) []archiveFile {
+	names := map[string]archiveNames{}
+	for _, dir := range dirs {
+		names[dir] = archiveNames{}
+	}
+	for _, dir := range dirs {
+		if parent, ok := names[path.Dir(dir)]; ok {
+			parent.unique(path.Base(dir))
+		}
+	}
+
+	files := make([]archiveFile, 0, len(attachments))
+	for _, att := range attachments {
+		dir, ok := dirs[att.ParentID]
+		if !ok {
+			continue
+		}
+		name := names[dir].unique(archiveName(att.Name, att.Code))
+		files = append(files, archiveFile{path: path.Join(dir, name), att: att})
+	}
+	slices.SortStableFunc(files, func(a, b archiveFile) int {
+		return strings.Compare(a.path, b.path)
+	})
+
+	return files
+}
+
+// archiveName makes a document or file name safe as an archive path
+// segment, falling back to fallback for empty names.
+func archiveName(name, fallback string) string {
+	name = strings.TrimSpace(strings.NewReplacer("/", "_", "\\", "_").Replace(name))
+	if name == "" {
+		name = strings.TrimSpace(fallback)
+	}
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+
+	return name
+}
+
+// archiveNames dedupes names within an archive directory:
+// "a.txt", "a (2).txt", ...
+type archiveNames map[string]bool
+
+func (n archiveNames) unique(name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	candidate := name
+	for i := 2; n[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	n[strings.ToLower(candidate)] = true
+
+	return candidate
+}
+
+// progressWriter counts the bytes written to w and reports them after each
+// write when report is set.
+type progressWriter struct {
+	w      io.Writer
+	n      int64
+	report func(ArchiveProgress)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	if p.report != nil {
+		p.report(ArchiveProgress{Bytes: p.n})
+	}
+
+	return n, err
+}
@@ -0,0 +1,175 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newArchiveServer serves CmfDocument.download_all_attachment with
+// archiveReply, and the document tree Handbook > {Onboarding > Week 1/2, FAQ}
+// with attachments under /media/.
+func newArchiveServer(t *testing.T, archiveReply func(w http.ResponseWriter)) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var reqBody struct {
+			Kwargs map[string]any `json:"kwargs"`
+		}
+		_ = json.Unmarshal(body, &reqBody)
+
+		switch r.URL.Query().Get("m") {
+		case "CmfDocument.download_all_attachment":
+			assert.Contains(t, string(body), `"args":["CmfDocument:root"]`)
+			archiveReply(w)
+		case "CmfDocument.macros_page_tree_get":
+			switch reqBody.Kwargs["node_id"] {
+			case "CmfDocument:root":
+				_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": [
+					{"id": "CmfDocument:root", "name": "Handbook", "tree_node_is_branch": true},
+					{"id": "CmfDocument:faq", "name": "FAQ", "parent_id": "CmfDocument:root", "orderno": 2},
+					{"id": "CmfDocument:onb", "name": "Onboarding", "parent_id": "CmfDocument:root", "orderno": 1, "tree_node_is_branch": true}
+				]}`))
+			case "CmfDocument:onb":
+				_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": [
+					{"id": "CmfDocument:w1", "name": "Week 1/2", "parent_id": "CmfDocument:onb"}
+				]}`))
+			default:
+				_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": []}`))
+			}
+		case "CmfAttachment.list":
+			_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": [
+				{"id": "CmfAttachment:1", "name": "logo.png", "parent_id": "CmfDocument:root", "url": "/media/1"},
+				{"id": "CmfAttachment:2", "name": "checklist.txt", "parent_id": "CmfDocument:w1", "url": "/media/2"},
+				{"id": "CmfAttachment:3", "name": "checklist.txt", "parent_id": "CmfDocument:w1", "url": "/media/3"},
+				{"id": "CmfAttachment:4", "name": "FAQ", "parent_id": "CmfDocument:root", "url": "/media/4"}
+			]}`))
+		case "":
+			_, _ = w.Write([]byte("content of " + r.URL.Path))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(&Config{BaseURL: srv.URL, APIToken: "test-token"})
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestClient_DocumentDownloadAllAttachments_ServerArchive(t *testing.T) {
+	client := newArchiveServer(t, func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/zip")
+		_, _ = w.Write([]byte("PK server archive"))
+	})
+
+	var buf bytes.Buffer
+	var progress []ArchiveProgress
+	err := client.DocumentDownloadAllAttachments(testCtx, "CmfDocument:root", &buf,
+		OnArchiveProgress(func(p ArchiveProgress) { progress = append(progress, p) }))
+
+	require.NoError(t, err)
+	assert.Equal(t, "PK server archive", buf.String())
+	require.NotEmpty(t, progress)
+	assert.Equal(t, int64(17), progress[len(progress)-1].Bytes)
+}
+
+func TestClient_DocumentDownloadAllAttachments_ServerArchiveURL(t *testing.T) {
+	client := newArchiveServer(t, func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": {"url": "/media/archive.zip"}}`))
+	})
+
+	var buf bytes.Buffer
+	err := client.DocumentDownloadAllAttachments(testCtx, "CmfDocument:root", &buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, "content of /media/archive.zip", buf.String())
+}
+
+func TestClient_DocumentDownloadAllAttachments_ClientFallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply func(w http.ResponseWriter)
+		opts  []ArchiveOption
+	}{
+		{"method not found", func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "error": {"code": -32601, "message": "Method not found"}}`))
+		}, nil},
+		{"server error", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}, nil},
+		{"no archive in reply", func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"jsonrpc": "2.2", "result": null}`))
+		}, nil},
+		{"forced", func(w http.ResponseWriter) {
+			t.Error("server archive requested")
+		}, []ArchiveOption{ClientSideArchive()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newArchiveServer(t, tt.reply)
+
+			var buf bytes.Buffer
+			var progress []ArchiveProgress
+			opts := append(tt.opts, OnArchiveProgress(func(p ArchiveProgress) { progress = append(progress, p) }))
+			err := client.DocumentDownloadAllAttachments(testCtx, "CmfDocument:root", &buf, opts...)
+			require.NoError(t, err)
+
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			require.NoError(t, err)
+			contents := map[string]string{}
+			for _, f := range zr.File {
+				rc, err := f.Open()
+				require.NoError(t, err)
+				content, _ := io.ReadAll(rc)
+				_ = rc.Close()
+				contents[f.Name] = string(content)
+			}
+			assert.Equal(t, map[string]string{
+				"Handbook/FAQ (2)": "content of /media/4",
+				"Handbook/Onboarding/Week 1_2/checklist (2).txt": "content of /media/3",
+				"Handbook/Onboarding/Week 1_2/checklist.txt":     "content of /media/2",
+				"Handbook/logo.png":                              "content of /media/1",
+			}, contents)
+
+			require.Len(t, progress, 4)
+			for i, p := range progress {
+				assert.Equal(t, i+1, p.Files)
+				assert.Equal(t, 4, p.Total)
+			}
+			assert.Equal(t, "Handbook/logo.png", progress[3].Path)
+		})
+	}
+}
+
+func TestClient_DocumentDownloadAllAttachments_NoFallbackOnAuthError(t *testing.T) {
+	client := newArchiveServer(t, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	var buf bytes.Buffer
+	err := client.DocumentDownloadAllAttachments(testCtx, "CmfDocument:root", &buf)
+
+	require.ErrorIs(t, err, ErrUnauthorized)
+	assert.Zero(t, buf.Len())
+}
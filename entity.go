@@ -18,8 +18,11 @@ const (
 	EntityComment       = "CmfComment"
 	EntityRelation      = "CmfRelationOption" // Task links
 	EntityFile          = "CmfRFile"
+	EntityAttachment    = "CmfAttachment"
 	EntityAudit         = "CmfAudit"
 	EntityStatusHistory = "CmfStatusHistory"
 	EntityLogicType     = "CmfLogicType"
 	EntityTag           = "CmfTag"
+	EntityCompany       = "CmfCompany" // Contractors, customers
+	EntityNotepad       = "CmfNotepad" // Personal notes
 )
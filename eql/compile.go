@@ -0,0 +1,291 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+// Package eql is a human-readable query language compiled to
+// evateamclient.QueryBuilder:
+//
+//	project = MOB AND status in (OPEN, IN_PROGRESS) AND responsible = @ivanov
+//	  AND deadline < now()+7d ORDER BY -priority LIMIT 50
+//
+// Conditions are joined with AND, OR, NOT and parentheses. Operators are
+// =, !=, <, <=, >, >=, [NOT] IN (...), [NOT] LIKE, [NOT] ILIKE,
+// [NOT] CONTAINS and IS [NOT] NULL. Values are quoted or bare strings,
+// numbers, true, false, null, @login, and dates relative to now() or
+// today() shifted by m(inutes), h(ours), d(ays) or w(eeks): now()+7d,
+// today()-1w, or just -2h (from now).
+//
+// Some fields are aliases that also resolve their values through a
+// Resolver: project (code to project_id), sprint, release and list (code to
+// lists), responsible (login to responsible_id), executor (login to
+// executors), author (login to cmf_owner_id), tag (tags) and status
+// (cache_status_type). = and != on the m2m fields lists, executors and tags
+// mean CONTAINS and NOT CONTAINS. @login is resolved on any field.
+package eql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/bql"
+)
+
+// Resolver maps human-readable references to EVA IDs.
+type Resolver interface {
+	PersonID(ctx context.Context, login string) (string, error)
+	ProjectID(ctx context.Context, code string) (string, error)
+	ListID(ctx context.Context, code string) (string, error)
+}
+
+type refKind int
+
+const (
+	refNone refKind = iota
+	refPerson
+	refProject
+	refList
+)
+
+type fieldSpec struct {
+	name string
+	ref  refKind
+	m2m  bool
+}
+
+var fieldAliases = map[string]fieldSpec{
+	"project":                              {name: evateamclient.TaskFieldProjectID, ref: refProject},
+	evateamclient.TaskFieldProjectID:       {name: evateamclient.TaskFieldProjectID, ref: refProject},
+	"sprint":                               {name: evateamclient.TaskFieldLists, ref: refList, m2m: true},
+	"release":                              {name: evateamclient.TaskFieldLists, ref: refList, m2m: true},
+	"list":                                 {name: evateamclient.TaskFieldLists, ref: refList, m2m: true},
+	evateamclient.TaskFieldLists:           {name: evateamclient.TaskFieldLists, ref: refList, m2m: true},
+	evateamclient.TaskFieldResponsible:     {name: evateamclient.TaskFieldResponsibleID, ref: refPerson},
+	evateamclient.TaskFieldResponsibleID:   {name: evateamclient.TaskFieldResponsibleID, ref: refPerson},
+	"executor":                             {name: evateamclient.TaskFieldExecutors, ref: refPerson, m2m: true},
+	evateamclient.TaskFieldExecutors:       {name: evateamclient.TaskFieldExecutors, ref: refPerson, m2m: true},
+	"author":                               {name: evateamclient.TaskFieldCmfOwnerID, ref: refPerson},
+	evateamclient.TaskFieldCmfOwnerID:      {name: evateamclient.TaskFieldCmfOwnerID, ref: refPerson},
+	"tag":                                  {name: evateamclient.TaskFieldTags, m2m: true},
+	evateamclient.TaskFieldTags:            {name: evateamclient.TaskFieldTags, m2m: true},
+	evateamclient.TaskFieldStatus:          {name: evateamclient.TaskFieldCacheStatusType},
+	evateamclient.TaskFieldCacheStatusType: {name: evateamclient.TaskFieldCacheStatusType},
+}
+
+// Compiler compiles queries, resolving references through a Resolver.
+// Resolved IDs are not cached between Compile calls.
+type Compiler struct {
+	resolver Resolver
+	now      func() time.Time
+}
+
+// Option configures a Compiler.
+type Option func(*Compiler)
+
+// WithNow sets the clock of now() and today(), time.Now by default.
+func WithNow(now func() time.Time) Option {
+	return func(c *Compiler) {
+		c.now = now
+	}
+}
+
+// NewCompiler creates a Compiler resolving references through r.
+func NewCompiler(r Resolver, opts ...Option) *Compiler {
+	c := &Compiler{resolver: r, now: time.Now}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Compile compiles a query on the entity (e.g. evateamclient.EntityTask)
+// using the client to resolve references.
+//
+// Example:
+//
+//	qb, err := eql.Compile(ctx, client, evateamclient.EntityTask,
+//	  "project = MOB AND responsible = @ivanov ORDER BY -priority LIMIT 50")
+//	tasks, meta, err := client.TasksList(ctx, qb)
+func Compile(ctx context.Context, client *evateamclient.Client, entity, src string) (*evateamclient.QueryBuilder, error) {
+	return NewCompiler(NewClientResolver(client)).Compile(ctx, entity, src)
+}
+
+// Compile compiles a query on the entity. Syntax and resolution errors are
+// *Error with the position of the offending token.
+func (c *Compiler) Compile(ctx context.Context, entity, src string) (*evateamclient.QueryBuilder, error) {
+	q, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	qb := evateamclient.NewQueryBuilder().From(entity)
+	if q.where != nil {
+		st := &compileState{Compiler: c, ctx: ctx, ids: make(map[refKey]string), now: c.now()}
+		where, err := st.expr(q.where)
+		if err != nil {
+			return nil, err
+		}
+		qb.Where(where)
+	}
+	if len(q.orderBy) > 0 {
+		orderBy := make([]string, len(q.orderBy))
+		for i, key := range q.orderBy {
+			orderBy[i] = fieldName(key.field)
+			if key.desc {
+				orderBy[i] = "-" + orderBy[i]
+			}
+		}
+		qb.OrderBy(orderBy...)
+	}
+	if q.limit != nil {
+		qb.Limit(*q.limit)
+	}
+	if q.offset != nil {
+		qb.Offset(*q.offset)
+	}
+
+	return qb, nil
+}
+
+// compileState compiles one query; ids caches its resolved references.
+type compileState struct {
+	*Compiler
+	ctx context.Context
+	ids map[refKey]string
+	now time.Time
+}
+
+type refKey struct {
+	ref  refKind
+	text string
+}
+
+func (st *compileState) expr(e expr) (bql.Expr, error) {
+	switch e := e.(type) {
+	case groupExpr:
+		items := make([]bql.Expr, len(e.items))
+		for i, item := range e.items {
+			compiled, err := st.expr(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = compiled
+		}
+		if e.or {
+			return bql.Or(items), nil
+		}
+		return bql.And(items), nil
+	case notExpr:
+		compiled, err := st.expr(e.expr)
+		if err != nil {
+			return nil, err
+		}
+		return bql.Not{Expr: compiled}, nil
+	default:
+		return st.cond(e.(condExpr))
+	}
+}
+
+func (st *compileState) cond(c condExpr) (bql.Expr, error) {
+	spec := lookupField(c.field)
+	op := c.op
+	if spec.m2m && c.values[0].kind != valNull {
+		switch op {
+		case bql.Eq:
+			op = bql.Contains
+		case bql.NotEq:
+			op = bql.NotContains
+		}
+	}
+
+	values := make([]any, len(c.values))
+	for i, v := range c.values {
+		resolved, err := st.value(spec, v)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = resolved
+	}
+	if c.list {
+		return bql.Cond{Field: spec.name, Op: op, Value: values}, nil
+	}
+
+	return bql.Cond{Field: spec.name, Op: op, Value: values[0]}, nil
+}
+
+func (st *compileState) value(spec fieldSpec, v value) (any, error) {
+	switch v.kind {
+	case valNumber:
+		return v.num, nil
+	case valBool:
+		return v.b, nil
+	case valNull:
+		return nil, nil
+	case valDate:
+		t := st.now
+		if v.text == "today" {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		}
+		return t.Add(v.shift).Format(time.RFC3339), nil
+	case valPerson:
+		return st.resolve(refPerson, v)
+	default:
+		if spec.ref == refNone || isID(v.text) {
+			return v.text, nil
+		}
+		return st.resolve(spec.ref, v)
+	}
+}
+
+func (st *compileState) resolve(ref refKind, v value) (string, error) {
+	key := refKey{ref: ref, text: v.text}
+	if id, ok := st.ids[key]; ok {
+		return id, nil
+	}
+
+	var (
+		id   string
+		err  error
+		what string
+	)
+	switch ref {
+	case refPerson:
+		what = "person @" + v.text
+		id, err = st.resolver.PersonID(st.ctx, v.text)
+	case refProject:
+		what = "project " + v.text
+		id, err = st.resolver.ProjectID(st.ctx, v.text)
+	default:
+		what = "list " + v.text
+		id, err = st.resolver.ListID(st.ctx, v.text)
+	}
+	if err != nil {
+		return "", &Error{Pos: v.pos, Msg: "cannot resolve " + what, Err: err}
+	}
+	st.ids[key] = id
+
+	return id, nil
+}
+
+func lookupField(field string) fieldSpec {
+	if spec, ok := fieldAliases[strings.ToLower(field)]; ok {
+		return spec
+	}
+
+	return fieldSpec{name: field}
+}
+
+func fieldName(field string) string {
+	return lookupField(field).name
+}
+
+// isID reports whether s is an EVA object ID such as CmfProject:uuid.
+func isID(s string) bool {
+	return strings.HasPrefix(s, "Cmf") && strings.Contains(s, ":")
+}
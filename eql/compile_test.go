@@ -0,0 +1,198 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package eql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/raoptimus/evateamclient.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnknown = errors.New("unknown")
+
+// fakeResolver resolves "X" to "<kind>:X" except "unknown", counting calls.
+type fakeResolver struct {
+	calls int
+}
+
+func (f *fakeResolver) resolve(kind, s string) (string, error) {
+	f.calls++
+	if s == "unknown" {
+		return "", errUnknown
+	}
+	return kind + ":" + s, nil
+}
+
+func (f *fakeResolver) PersonID(_ context.Context, login string) (string, error) {
+	return f.resolve("CmfPerson", login)
+}
+
+func (f *fakeResolver) ProjectID(_ context.Context, code string) (string, error) {
+	return f.resolve("CmfProject", code)
+}
+
+func (f *fakeResolver) ListID(_ context.Context, code string) (string, error) {
+	return f.resolve("CmfList", code)
+}
+
+var testNow = time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC)
+
+func compile(t *testing.T, src string) (map[string]any, error) {
+	t.Helper()
+
+	c := NewCompiler(&fakeResolver{}, WithNow(func() time.Time { return testNow }))
+	qb, err := c.Compile(context.Background(), evateamclient.EntityTask, src)
+	if err != nil {
+		return nil, err
+	}
+
+	return qb.ToKwargs()
+}
+
+func TestCompile_FullQuery(t *testing.T) {
+	kwargs, err := compile(t, `project = MOB AND status in (OPEN, IN_PROGRESS) AND responsible = @ivanov `+
+		`AND deadline < now()+7d ORDER BY -priority LIMIT 50`)
+
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		[]any{"project_id", "==", "CmfProject:MOB"},
+		[]any{"cache_status_type", "IN", []any{"OPEN", "IN_PROGRESS"}},
+		[]any{"responsible_id", "==", "CmfPerson:ivanov"},
+		[]any{"deadline", "<", "2026-10-23T15:30:00Z"},
+	}, kwargs["filter"])
+	assert.Equal(t, []string{"-priority"}, kwargs["order_by"])
+	assert.Equal(t, []uint64{0, 50}, kwargs["slice"])
+}
+
+func TestCompile_Conditions(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected any
+	}{
+		{`priority >= 3`, []any{"priority", ">=", int64(3)}},
+		{`mark = 1.5`, []any{"mark", "==", 1.5}},
+		{`priority > -1`, []any{"priority", ">", int64(-1)}},
+		{`name = "Fix \"login\" bug"`, []any{"name", "==", `Fix "login" bug`}},
+		{`name ILIKE '%mobile%'`, []any{"name", "ILIKE", "%mobile%"}},
+		{`name NOT LIKE 'tmp%'`, []any{"name", "NOT LIKE", "tmp%"}},
+		{`code NOT IN (MOB-1, MOB-2)`, []any{"code", "NOT IN", []any{"MOB-1", "MOB-2"}}},
+		{`parent_id IS NULL`, []any{"parent_id", "==", nil}},
+		{`epic_id is not null`, []any{"epic_id", "!=", nil}},
+		{`system = false`, []any{"system", "==", false}},
+		{`executor = @petrov`, []any{"executors", "contains", "CmfPerson:petrov"}},
+		{`executors != petrov`, []any{"executors", "not contains", "CmfPerson:petrov"}},
+		{`sprint = SPR-001`, []any{"lists", "contains", "CmfList:SPR-001"}},
+		{`tag CONTAINS TAG-1`, []any{"tags", "contains", "TAG-1"}},
+		{`tags = TAG-1`, []any{"tags", "contains", "TAG-1"}},
+		{`lists IS NULL`, []any{"lists", "==", nil}},
+		{`project = CmfProject:42`, []any{"project_id", "==", "CmfProject:42"}},
+		{`cmf_created_at >= today()-1w`, []any{"cmf_created_at", ">=", "2026-10-09T00:00:00Z"}},
+		{`cmf_modified_at > -2h`, []any{"cmf_modified_at", ">", "2026-10-16T13:30:00Z"}},
+		{`deadline < 2026-12-31`, []any{"deadline", "<", "2026-12-31"}},
+		{`waiting_for = @sidorov`, []any{"waiting_for", "==", "CmfPerson:sidorov"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			kwargs, err := compile(t, tt.src)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, kwargs["filter"])
+		})
+	}
+}
+
+func TestCompile_Grouping(t *testing.T) {
+	kwargs, err := compile(t, `(status = OPEN OR status = IN_PROGRESS) AND (responsible = @me OR executor = @me) `+
+		`AND NOT priority < 2`)
+
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		[]any{[]any{"cache_status_type", "==", "OPEN"}, "or", []any{"cache_status_type", "==", "IN_PROGRESS"}},
+		[]any{[]any{"responsible_id", "==", "CmfPerson:me"}, "or", []any{"executors", "contains", "CmfPerson:me"}},
+		[]any{"not", []any{"priority", "<", int64(2)}},
+	}, kwargs["filter"])
+}
+
+func TestCompile_AndBindsTighterThanOr(t *testing.T) {
+	kwargs, err := compile(t, `a = 1 OR b = 2 AND c = 3`)
+
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		[]any{"a", "==", int64(1)},
+		"or",
+		[]any{[]any{"b", "==", int64(2)}, []any{"c", "==", int64(3)}},
+	}, kwargs["filter"])
+}
+
+func TestCompile_OrderLimitOffsetOnly(t *testing.T) {
+	kwargs, err := compile(t, `order by status, cmf_created_at desc offset 20 limit 10`)
+
+	require.NoError(t, err)
+	assert.NotContains(t, kwargs, "filter")
+	assert.Equal(t, []string{"cache_status_type", "-cmf_created_at"}, kwargs["order_by"])
+	assert.Equal(t, []uint64{20, 30}, kwargs["slice"])
+}
+
+func TestCompile_ResolvesEachReferenceOnce(t *testing.T) {
+	r := &fakeResolver{}
+
+	_, err := NewCompiler(r).Compile(context.Background(), evateamclient.EntityTask,
+		`responsible = @me OR executor = @me OR author = @me`)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, r.calls)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+		msg string
+	}{
+		{`status = `, 9, "expected value, got end of query"},
+		{`status OPEN`, 7, `expected operator, got "OPEN"`},
+		{`(status = OPEN`, 14, "expected ')', got end of query"},
+		{`status in OPEN`, 10, `expected '(', got "OPEN"`},
+		{`status = OPEN ORDER priority`, 20, `expected BY, got "priority"`},
+		{`status = OPEN LIMIT ten`, 20, `expected a non-negative integer, got "ten"`},
+		{`status = 'OPEN`, 9, "unterminated string"},
+		{`status = OPEN extra`, 14, `expected AND, OR, ORDER BY, LIMIT or end of query, got "extra"`},
+		{`deadline < now()+7y`, 17, `expected duration like 7d, got "7y"`},
+		{`a = 1 AND = 2`, 10, `expected field name, got "="`},
+		{`name NOT = x`, 9, `expected IN, LIKE, ILIKE or CONTAINS after NOT, got "="`},
+		{`a = @`, 4, "expected login after '@'"},
+		{`a ; b`, 2, `unexpected ';'`},
+		{`responsible = @unknown`, 14, "cannot resolve person @unknown"},
+		{`a = 1 AND project = unknown`, 20, "cannot resolve project unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := compile(t, tt.src)
+
+			var qerr *Error
+			require.ErrorAs(t, err, &qerr)
+			assert.Equal(t, tt.pos, qerr.Pos)
+			assert.Equal(t, tt.msg, qerr.Msg)
+		})
+	}
+}
+
+func TestError_Message(t *testing.T) {
+	_, err := compile(t, `responsible = @unknown`)
+
+	require.ErrorIs(t, err, errUnknown)
+	assert.EqualError(t, err, "eql: column 15: cannot resolve person @unknown: unknown")
+}
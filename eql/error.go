@@ -0,0 +1,35 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package eql
+
+import "fmt"
+
+// Error is a syntax or resolution error at a position of the query.
+type Error struct {
+	// Pos is the byte offset of the offending token in the query.
+	Pos int
+	Msg string
+	// Err is the cause of a resolution error, if any.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("eql: column %d: %s: %v", e.Pos+1, e.Msg, e.Err)
+	}
+	return fmt.Sprintf("eql: column %d: %s", e.Pos+1, e.Msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func errorf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,184 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package eql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokPerson
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokPlus
+	tokMinus
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokEOF:
+		return "end of query"
+	case tokWord:
+		return "word"
+	case tokString:
+		return "string"
+	case tokPerson:
+		return "@login"
+	case tokOp:
+		return "operator"
+	case tokLParen:
+		return "'('"
+	case tokRParen:
+		return "')'"
+	case tokComma:
+		return "','"
+	case tokPlus:
+		return "'+'"
+	default:
+		return "'-'"
+	}
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // byte offset in the query
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return t.kind.String()
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// is reports whether t is the keyword kw, case-insensitively.
+func (t token) is(kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, kw)
+}
+
+// lex splits a query into tokens, the last one being tokEOF.
+func lex(query string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case r == '+':
+			tokens = append(tokens, token{kind: tokPlus, text: "+", pos: i})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokMinus, text: "-", pos: i})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			op := lexOp(query[i:])
+			if op == "" {
+				return nil, errorf(i, "unexpected %q", r)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		case r == '\'' || r == '"':
+			text, end, err := lexString(query, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokString, text: text, pos: i})
+			i = end
+		case r == '@':
+			end := scanWord(query, i+1)
+			if end == i+1 {
+				return nil, errorf(i, "expected login after '@'")
+			}
+			tokens = append(tokens, token{kind: tokPerson, text: query[i+1 : end], pos: i})
+			i = end
+		case isWordRune(r):
+			end := scanWord(query, i)
+			tokens = append(tokens, token{kind: tokWord, text: query[i:end], pos: i})
+			i = end
+		default:
+			return nil, errorf(i, "unexpected %q", r)
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(query)}), nil
+}
+
+func lexOp(s string) string {
+	for _, op := range []string{"==", "!=", "<>", "<=", ">=", "=", "<", ">"} {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+// lexString reads a quoted string starting at query[start]; a backslash
+// escapes the next character.
+func lexString(query string, start int) (text string, end int, err error) {
+	quote := query[start]
+	var b strings.Builder
+	for i := start + 1; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\\' && i+1 < len(query):
+			i++
+			b.WriteByte(query[i])
+		case c == quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", 0, errorf(start, "unterminated string")
+}
+
+// scanWord returns the end of the word starting at query[start]. Words hold
+// field names, keywords, codes (MOB-12), IDs (CmfPerson:uuid), numbers,
+// dates (2026-01-31) and durations (7d).
+func scanWord(query string, start int) int {
+	i := start
+	for i < len(query) {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		if !isWordRune(r) && !(i > start && strings.ContainsRune("-:.", r)) {
+			break
+		}
+		i += size
+	}
+
+	return i
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,471 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package eql
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raoptimus/evateamclient.go/bql"
+)
+
+// query is the syntax tree of a query.
+type query struct {
+	where   expr // nil without conditions
+	orderBy []orderKey
+	limit   *uint64
+	offset  *uint64
+}
+
+type orderKey struct {
+	field string
+	desc  bool
+	pos   int
+}
+
+type expr interface {
+	position() int
+}
+
+type groupExpr struct {
+	or    bool
+	items []expr
+}
+
+type notExpr struct {
+	expr expr
+	pos  int
+}
+
+type condExpr struct {
+	field  string
+	op     bql.Op
+	values []value
+	list   bool // IN (...) / NOT IN (...)
+	pos    int
+}
+
+func (g groupExpr) position() int { return g.items[0].position() }
+func (n notExpr) position() int   { return n.pos }
+func (c condExpr) position() int  { return c.pos }
+
+type valueKind int
+
+const (
+	valText valueKind = iota
+	valNumber
+	valBool
+	valNull
+	valPerson
+	valDate
+)
+
+type value struct {
+	kind valueKind
+	text string // text, login, or "now" / "today" of a date
+	num  any    // int64 or float64
+	b    bool
+	// shift is added to the base of a date.
+	shift time.Duration
+	pos   int
+}
+
+// reserved words cannot be bare values.
+var reserved = []string{"and", "or", "not", "in", "is", "like", "ilike", "contains", "order", "by", "limit", "offset"}
+
+var (
+	numberRe   = regexp.MustCompile(`^\d+(\.\d+)?$`)
+	durationRe = regexp.MustCompile(`^(\d+)([mhdw])$`)
+)
+
+var durationUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+var comparisonOps = map[string]bql.Op{
+	"=":  bql.Eq,
+	"==": bql.Eq,
+	"!=": bql.NotEq,
+	"<>": bql.NotEq,
+	">":  bql.Gt,
+	">=": bql.GtOrEq,
+	"<":  bql.Lt,
+	"<=": bql.LtOrEq,
+}
+
+// keywordOps are operators written as words; NOT before them negates.
+var keywordOps = map[string][2]bql.Op{
+	"like":     {bql.Like, bql.NotLike},
+	"ilike":    {bql.ILike, bql.NotILike},
+	"contains": {bql.Contains, bql.NotContains},
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+// parse parses a query:
+//
+//	query   = [or] ["ORDER" "BY" key {"," key}] ["LIMIT" n] ["OFFSET" n]
+//	or      = and {"OR" and}
+//	and     = unary {"AND" unary}
+//	unary   = "NOT" unary | "(" or ")" | cond
+//	cond    = field ("=" | "!=" | "<" | ...) value
+//	        | field ["NOT"] ("LIKE" | "ILIKE" | "CONTAINS") value
+//	        | field ["NOT"] "IN" "(" value {"," value} ")"
+//	        | field "IS" ["NOT"] "NULL"
+//	key     = ["-"] field ["ASC" | "DESC"]
+func parse(src string) (*query, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	q := &query{}
+	if !p.peek().is("order") && !p.peek().is("limit") && !p.peek().is("offset") && p.peek().kind != tokEOF {
+		if q.where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek().is("order") {
+		p.next()
+		if !p.next().is("by") {
+			return nil, p.unexpected(p.prev(), "BY")
+		}
+		if q.orderBy, err = p.parseOrder(); err != nil {
+			return nil, err
+		}
+	}
+	for p.peek().is("limit") || p.peek().is("offset") {
+		kw := p.next()
+		n, err := p.parseCount()
+		if err != nil {
+			return nil, err
+		}
+		if kw.is("limit") {
+			q.limit = &n
+		} else {
+			q.offset = &n
+		}
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.unexpected(t, "AND, OR, ORDER BY, LIMIT or end of query")
+	}
+
+	return q, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.i] }
+func (p *parser) prev() token { return p.tokens[p.i-1] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) unexpected(t token, want string) *Error {
+	return errorf(t.pos, "expected %s, got %s", want, t)
+}
+
+func (p *parser) parseOr() (expr, error) {
+	return p.parseGroup(true, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	return p.parseGroup(false, p.parseUnary)
+}
+
+func (p *parser) parseGroup(or bool, operand func() (expr, error)) (expr, error) {
+	keyword := "and"
+	if or {
+		keyword = "or"
+	}
+
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	items := []expr{first}
+	for p.peek().is(keyword) {
+		p.next()
+		item, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if len(items) == 1 {
+		return first, nil
+	}
+
+	return groupExpr{or: or, items: items}, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	switch t := p.peek(); {
+	case t.is("not"):
+		p.next()
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: e, pos: t.pos}, nil
+	case t.kind == tokLParen:
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.unexpected(closing, "')'")
+		}
+		return e, nil
+	default:
+		return p.parseCond()
+	}
+}
+
+func (p *parser) parseCond() (expr, error) {
+	field := p.next()
+	if field.kind != tokWord || isReserved(field.text) {
+		return nil, p.unexpected(field, "field name")
+	}
+	cond := condExpr{field: field.text, pos: field.pos}
+
+	t := p.next()
+	if t.kind == tokOp {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		cond.op, cond.values = comparisonOps[t.text], []value{v}
+		return cond, nil
+	}
+
+	if t.is("is") {
+		cond.op = bql.Eq
+		if p.peek().is("not") {
+			p.next()
+			cond.op = bql.NotEq
+		}
+		if null := p.next(); !null.is("null") {
+			return nil, p.unexpected(null, "NULL")
+		}
+		cond.values = []value{{kind: valNull, pos: p.prev().pos}}
+		return cond, nil
+	}
+
+	negated := t.is("not")
+	if negated {
+		t = p.next()
+	}
+	switch {
+	case t.is("in"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		cond.op, cond.values, cond.list = bql.In, values, true
+		if negated {
+			cond.op = bql.NotIn
+		}
+		return cond, nil
+	case t.kind == tokWord:
+		ops, ok := keywordOps[strings.ToLower(t.text)]
+		if !ok {
+			break
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		cond.op, cond.values = ops[0], []value{v}
+		if negated {
+			cond.op = ops[1]
+		}
+		return cond, nil
+	}
+
+	if negated {
+		return nil, p.unexpected(t, "IN, LIKE, ILIKE or CONTAINS after NOT")
+	}
+	return nil, p.unexpected(t, "operator")
+}
+
+func (p *parser) parseList() ([]value, error) {
+	if open := p.next(); open.kind != tokLParen {
+		return nil, p.unexpected(open, "'('")
+	}
+
+	var values []value
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		switch t := p.next(); t.kind {
+		case tokComma:
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, p.unexpected(t, "',' or ')'")
+		}
+	}
+}
+
+func (p *parser) parseValue() (value, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return value{kind: valText, text: t.text, pos: t.pos}, nil
+	case tokPerson:
+		return value{kind: valPerson, text: t.text, pos: t.pos}, nil
+	case tokPlus, tokMinus:
+		return p.parseSigned(t)
+	case tokWord:
+	default:
+		return value{}, p.unexpected(t, "value")
+	}
+
+	word := strings.ToLower(t.text)
+	switch {
+	case isReserved(word):
+		return value{}, p.unexpected(t, "value")
+	case word == "true" || word == "false":
+		return value{kind: valBool, b: word == "true", pos: t.pos}, nil
+	case word == "null":
+		return value{kind: valNull, pos: t.pos}, nil
+	case (word == "now" || word == "today") && p.peek().kind == tokLParen:
+		return p.parseDate(t, word)
+	case numberRe.MatchString(word):
+		return value{kind: valNumber, num: parseNumber(word), pos: t.pos}, nil
+	default:
+		return value{kind: valText, text: t.text, pos: t.pos}, nil
+	}
+}
+
+// parseSigned parses a signed number (-1) or a shift from now (-7d).
+func (p *parser) parseSigned(sign token) (value, error) {
+	t := p.next()
+	if t.kind == tokWord {
+		if numberRe.MatchString(t.text) {
+			num := parseNumber(sign.text + t.text)
+			return value{kind: valNumber, num: num, pos: sign.pos}, nil
+		}
+		if shift, ok := parseDuration(t.text); ok {
+			if sign.kind == tokMinus {
+				shift = -shift
+			}
+			return value{kind: valDate, text: "now", shift: shift, pos: sign.pos}, nil
+		}
+	}
+
+	return value{}, p.unexpected(t, "number or duration like 7d")
+}
+
+// parseDate parses now() or today() with an optional shift like +7d.
+func (p *parser) parseDate(fn token, base string) (value, error) {
+	p.next()
+	if closing := p.next(); closing.kind != tokRParen {
+		return value{}, p.unexpected(closing, "')'")
+	}
+
+	v := value{kind: valDate, text: base, pos: fn.pos}
+	if sign := p.peek(); sign.kind == tokPlus || sign.kind == tokMinus {
+		p.next()
+		t := p.next()
+		shift, ok := parseDuration(t.text)
+		if t.kind != tokWord || !ok {
+			return value{}, p.unexpected(t, "duration like 7d")
+		}
+		if sign.kind == tokMinus {
+			shift = -shift
+		}
+		v.shift = shift
+	}
+
+	return v, nil
+}
+
+func (p *parser) parseOrder() ([]orderKey, error) {
+	var keys []orderKey
+	for {
+		key := orderKey{pos: p.peek().pos}
+		if p.peek().kind == tokMinus {
+			p.next()
+			key.desc = true
+		}
+		field := p.next()
+		if field.kind != tokWord || isReserved(field.text) {
+			return nil, p.unexpected(field, "field name")
+		}
+		key.field = field.text
+		switch {
+		case p.peek().is("desc"):
+			p.next()
+			key.desc = true
+		case p.peek().is("asc"):
+			p.next()
+		}
+		keys = append(keys, key)
+
+		if p.peek().kind != tokComma {
+			return keys, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseCount() (uint64, error) {
+	t := p.next()
+	n, err := strconv.ParseUint(t.text, 10, 64)
+	if t.kind != tokWord || err != nil {
+		return 0, p.unexpected(t, "a non-negative integer")
+	}
+
+	return n, nil
+}
+
+func parseNumber(s string) any {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func parseDuration(s string) (time.Duration, bool) {
+	m := durationRe.FindStringSubmatch(strings.ToLower(s))
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(n) * durationUnits[m[2]], true
+}
+
+func isReserved(word string) bool {
+	return slices.Contains(reserved, strings.ToLower(word))
+}
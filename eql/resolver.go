@@ -0,0 +1,61 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package eql
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go"
+)
+
+// ClientResolver resolves references with EVA API lookups by login or code.
+type ClientResolver struct {
+	client *evateamclient.Client
+}
+
+// NewClientResolver creates a Resolver backed by the client.
+func NewClientResolver(client *evateamclient.Client) *ClientResolver {
+	return &ClientResolver{client: client}
+}
+
+// PersonID returns the ID of the person with the login.
+func (r *ClientResolver) PersonID(ctx context.Context, login string) (string, error) {
+	return r.lookup(ctx, evateamclient.EntityPerson, evateamclient.PersonFieldLogin, login)
+}
+
+// ProjectID returns the ID of the project with the code.
+func (r *ClientResolver) ProjectID(ctx context.Context, code string) (string, error) {
+	return r.lookup(ctx, evateamclient.EntityProject, evateamclient.ProjectFieldCode, code)
+}
+
+// ListID returns the ID of the list (sprint, release) with the code.
+func (r *ClientResolver) ListID(ctx context.Context, code string) (string, error) {
+	return r.lookup(ctx, evateamclient.EntityList, evateamclient.ListFieldCode, code)
+}
+
+func (r *ClientResolver) lookup(ctx context.Context, entity, field, value string) (string, error) {
+	qb := evateamclient.NewQueryBuilder().
+		Select("id").
+		From(entity).
+		Where(sq.Eq{field: value})
+
+	row, _, err := evateamclient.Get[struct {
+		ID string `json:"id"`
+	}](ctx, r.client, qb)
+	if err != nil {
+		return "", err
+	}
+	if row == nil || row.ID == "" {
+		return "", errors.Wrapf(evateamclient.ErrNotFound, "%s with %s %q", entity, field, value)
+	}
+
+	return row.ID, nil
+}
@@ -0,0 +1,63 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package eql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/raoptimus/evateamclient.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile_WithClient(t *testing.T) {
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Method string `json:"method"`
+			Kwargs struct {
+				Filter []any `json:"filter"`
+			} `json:"kwargs"`
+		}
+		_ = json.Unmarshal(body, &req)
+		methods = append(methods, req.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.Kwargs.Filter[2] {
+		case "ivanov":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":{"id":"CmfPerson:1"}}`))
+		case "MOB":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":{"id":"CmfProject:2"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":null}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := evateamclient.NewClient(&evateamclient.Config{BaseURL: srv.URL, APIToken: "test"})
+	require.NoError(t, err)
+
+	qb, err := Compile(context.Background(), client, evateamclient.EntityTask, `project = MOB AND responsible = @ivanov`)
+	require.NoError(t, err)
+	kwargs, err := qb.ToKwargs()
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		[]any{"project_id", "==", "CmfProject:2"},
+		[]any{"responsible_id", "==", "CmfPerson:1"},
+	}, kwargs["filter"])
+	assert.Equal(t, []string{"CmfProject.get", "CmfPerson.get"}, methods)
+
+	_, err = Compile(context.Background(), client, evateamclient.EntityTask, `sprint = SPR-404`)
+	require.ErrorIs(t, err, evateamclient.ErrNotFound)
+}
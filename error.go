@@ -8,7 +8,12 @@
 
 package evateamclient
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
 
 var (
 	ErrOptionIsRequired    = errors.New("option is required")
@@ -16,6 +21,28 @@ var (
 	ErrRPCMethodIsRequired = errors.New("RPCRequest.Method is required")
 )
 
+// Error kinds of a failed API call. An *APIError matches at most one of them
+// via errors.Is:
+//
+//	if errors.Is(err, evateamclient.ErrNotFound) { ... }
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrMethodNotFound    = errors.New("method not found") // the server lacks the RPC method, not the entity
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrForbidden         = errors.New("forbidden")
+	ErrValidation        = errors.New("validation failed")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrServerUnavailable = errors.New("server unavailable")
+)
+
+// JSON-RPC 2.0 reserved error codes.
+const (
+	rpcCodeParseError     = -32700
+	rpcCodeInvalidRequest = -32600
+	rpcCodeMethodNotFound = -32601
+	rpcCodeInvalidParams  = -32602
+)
+
 // RPCError represents JSON-RPC error response
 type RPCError struct {
 	Code    int    `json:"code"`
@@ -30,3 +57,124 @@ func (e *RPCError) Error() string {
 type rpcErrorResponse struct {
 	Error *RPCError `json:"error,omitempty"`
 }
+
+// APIError describes a failed API call: either a non-2xx HTTP response or a
+// JSON-RPC error returned in a 200 OK body.
+//
+// Use errors.Is with the ErrNotFound, ErrUnauthorized, ... kinds to classify
+// it, or errors.As to inspect the details:
+//
+//	var apiErr *evateamclient.APIError
+//	if errors.As(err, &apiErr) {
+//	  log.Println(apiErr.Method, apiErr.StatusCode, apiErr.RPCCode)
+//	}
+type APIError struct {
+	// Method is the RPC method, e.g. "CmfTask.get".
+	Method string
+	// CallID is the JSON-RPC call ID of the failed request.
+	CallID string
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// RPC is the JSON-RPC error, nil for HTTP failures.
+	RPC *RPCError
+	// Body is the raw response body.
+	Body []byte
+
+	kind error
+}
+
+func newHTTPError(method, callID string, statusCode int, body []byte) *APIError {
+	return &APIError{
+		Method:     method,
+		CallID:     callID,
+		StatusCode: statusCode,
+		Body:       body,
+		kind:       httpStatusKind(statusCode),
+	}
+}
+
+func newRPCError(method, callID string, statusCode int, rpcErr *RPCError, body []byte) *APIError {
+	return &APIError{
+		Method:     method,
+		CallID:     callID,
+		StatusCode: statusCode,
+		RPC:        rpcErr,
+		Body:       body,
+		kind:       rpcErrorKind(statusCode, rpcErr),
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.RPC != nil {
+		return fmt.Sprintf("RPC error %d: %s", e.RPC.Code, e.RPC.Message)
+	}
+
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, string(e.Body))
+}
+
+// RPCCode returns the JSON-RPC error code, or 0 for HTTP failures.
+func (e *APIError) RPCCode() int {
+	if e.RPC == nil {
+		return 0
+	}
+
+	return e.RPC.Code
+}
+
+// Kind returns the error kind (ErrNotFound, ErrUnauthorized, ...) or nil when
+// the failure could not be classified.
+func (e *APIError) Kind() error {
+	return e.kind
+}
+
+// Unwrap exposes the error kind and the underlying *RPCError to errors.Is/As.
+func (e *APIError) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.kind != nil {
+		errs = append(errs, e.kind)
+	}
+	if e.RPC != nil {
+		errs = append(errs, e.RPC)
+	}
+
+	return errs
+}
+
+func httpStatusKind(statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ErrServerUnavailable
+	default:
+		return nil
+	}
+}
+
+// rpcErrorKind classifies a JSON-RPC error by its code and, failing that,
+// by the HTTP status of the response. The message is never inspected: an
+// error that neither identifies stays unclassified (nil kind).
+func rpcErrorKind(statusCode int, rpcErr *RPCError) error {
+	switch rpcErr.Code {
+	case rpcCodeParseError, rpcCodeInvalidRequest, rpcCodeInvalidParams:
+		return ErrValidation
+	case rpcCodeMethodNotFound:
+		return ErrMethodNotFound
+	}
+
+	// Some EVA handlers report HTTP-style codes (403, 404, ...) in the
+	// JSON-RPC error.
+	if kind := httpStatusKind(rpcErr.Code); kind != nil {
+		return kind
+	}
+
+	return httpStatusKind(statusCode)
+}
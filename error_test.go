@@ -0,0 +1,104 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DoRequest_HTTPError_ReturnsClassifiedAPIError(t *testing.T) {
+	tests := []struct {
+		status int
+		kind   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadGateway, ErrServerUnavailable},
+		{http.StatusServiceUnavailable, ErrServerUnavailable},
+		{http.StatusInternalServerError, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			client, mockHTTP := newTestClient(t)
+			mockHTTP.response = mockResponse(tt.status, "failure body")
+
+			_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, "CmfTask.get", apiErr.Method)
+			assert.NotEmpty(t, apiErr.CallID)
+			assert.Equal(t, []byte("failure body"), apiErr.Body)
+			assert.Zero(t, apiErr.RPCCode())
+			assert.Equal(t, tt.kind, apiErr.Kind())
+			if tt.kind != nil {
+				assert.ErrorIs(t, err, tt.kind)
+			}
+			assert.Contains(t, err.Error(), "failure body")
+		})
+	}
+}
+
+func TestClient_DoRequest_RPCError_ReturnsClassifiedAPIError(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		message string
+		kind    error
+	}{
+		{"not found message is not classified", -32000, "Task not found", nil},
+		{"http-style code", 404, "Task not found", ErrNotFound},
+		{"invalid params", -32602, "bad kwargs", ErrValidation},
+		{"method not found", -32601, "no such method", ErrMethodNotFound},
+		{"permission message is not classified", -32000, "Permission denied for object", nil},
+		{"misleading wording", -32000, "required field missing in not found handler", nil},
+		{"unclassified", -32000, "something went wrong", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newTestClient(t)
+			mockHTTP.response = mockResponse(http.StatusOK,
+				`{"jsonrpc":"2.2","error":{"code":`+strconv.Itoa(tt.code)+`,"message":"`+tt.message+`"}}`)
+
+			_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.code, apiErr.RPCCode())
+			assert.Equal(t, http.StatusOK, apiErr.StatusCode)
+			assert.Equal(t, tt.kind, apiErr.Kind())
+
+			var rpcErr *RPCError
+			require.True(t, errors.As(err, &rpcErr), "underlying *RPCError stays reachable")
+			assert.Equal(t, tt.message, rpcErr.Message)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestClient_LogicTypeByCode_Missing_ReturnsErrNotFound(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":[]}`)
+
+	_, err := client.LogicTypeByCode(testCtx, "task.unknown")
+
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,266 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"cmp"
+	"context"
+	encjson "encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// groupCountKey is the key of the object count in a server-side group row.
+const groupCountKey = "count"
+
+// GroupBucket is the number of objects sharing the values of the grouped
+// fields.
+type GroupBucket struct {
+	// Values maps each grouped field to its value: an ID for references,
+	// nil for objects without a value.
+	Values map[string]any
+	Count  int
+}
+
+// WithServerGroupBy lets GroupCount count on the server with <Entity>.count
+// and a group_by kwarg. group_by is not part of the published API schema, so
+// enable it only for a server known to support it; without it GroupCount
+// always counts on the client.
+//
+// Example:
+//
+//	client, err := evateamclient.NewClient(cfg, evateamclient.WithServerGroupBy())
+func WithServerGroupBy() Option {
+	return func(c *Client) {
+		c.serverGroupBy = true
+	}
+}
+
+// GroupBy sets the fields GroupCount groups by. The list methods ignore it.
+// Example: client.GroupCount(ctx, qb.GroupBy("cache_status_type"))
+func (qb *QueryBuilder) GroupBy(fields ...string) *QueryBuilder {
+	qb.groupBy = append(qb.groupBy, fields...)
+	return qb
+}
+
+// GroupCount counts the objects matched by qb per combination of values of
+// the groupBy fields (appended to those of QueryBuilder.GroupBy), largest
+// buckets first.
+//
+// The objects are fetched with the paginated iterator and counted on the
+// client, where an object with several values of a field (e.g. executors) is
+// counted in the bucket of each value. With WithServerGroupBy the server
+// counts instead, with <Entity>.count and group_by, unless the schema
+// attached with QueryBuilder.WithSchema does not allow grouping a field. A
+// server that rejects or ignores group_by falls back to the client count,
+// and the client stops sending group_by to it.
+//
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityTask).
+//	  Where(sq.Eq{"project_id": projectID})
+//	buckets, err := client.GroupCount(ctx, qb, "cache_status_type", "responsible_id")
+//	for _, b := range buckets {
+//	  fmt.Println(b.Values["cache_status_type"], b.Values["responsible_id"], b.Count)
+//	}
+func (c *Client) GroupCount(ctx context.Context, qb *QueryBuilder, groupBy ...string) ([]GroupBucket, error) {
+	fields := slices.Concat(qb.groupBy, groupBy)
+	if len(fields) == 0 {
+		return nil, errors.New("group count: no group by fields")
+	}
+	if qb.entity() == "" {
+		return nil, errors.New("group count: table name not found in query, use From()")
+	}
+
+	if c.serverGroupBy && !c.groupByUnsupported.Load() && (qb.schema == nil || qb.schema.canGroup(fields)) {
+		buckets, ok, err := c.serverGroupCount(ctx, qb, fields)
+		if err != nil || ok {
+			return buckets, err
+		}
+		c.groupByUnsupported.Store(true)
+	}
+
+	return c.clientGroupCount(ctx, qb, fields)
+}
+
+// canGroup reports whether the server can group by all fields.
+func (s *Schema) canGroup(fields []string) bool {
+	for _, name := range fields {
+		if field, ok := s.Field(name); !ok || !field.TEXKOMGroupByAllow {
+			return false
+		}
+	}
+
+	return true
+}
+
+// serverGroupCount counts via <Entity>.count with group_by. ok is false
+// when the server rejected or ignored group_by.
+func (c *Client) serverGroupCount(
+	ctx context.Context,
+	qb *QueryBuilder,
+	fields []string,
+) (buckets []GroupBucket, ok bool, err error) {
+	kwargs, err := qb.withoutWindow().ToKwargs()
+	if err != nil {
+		return nil, false, err
+	}
+	delete(kwargs, "fields")
+	delete(kwargs, "order_by")
+	kwargs["group_by"] = fields
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  qb.entity() + ".count",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	raw, _, err := doCall[encjson.RawMessage](ctx, c, reqBody)
+	if err != nil {
+		if errors.Is(err, ErrValidation) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	// A server ignoring group_by returns the total count.
+	var rows []map[string]any
+	if json.Unmarshal(raw, &rows) != nil {
+		return nil, false, nil
+	}
+
+	counter := newGroupCounter(fields)
+	for _, row := range rows {
+		count, ok := row[groupCountKey].(float64)
+		if !ok {
+			return nil, false, fmt.Errorf("group count: row without count: %v", row)
+		}
+		values := make([]any, len(fields))
+		for i, field := range fields {
+			values[i] = groupValue(row[field])
+		}
+		counter.add(values, int(count))
+	}
+
+	return counter.buckets(), true, nil
+}
+
+// clientGroupCount fetches the grouped fields of all objects matched by qb
+// and counts them.
+func (c *Client) clientGroupCount(ctx context.Context, qb *QueryBuilder, fields []string) ([]GroupBucket, error) {
+	cp, err := qb.withStableOrder()
+	if err != nil {
+		return nil, err
+	}
+	cp.selectBuilder = cp.selectBuilder.RemoveColumns().Columns(slices.Concat([]string{"id"}, fields)...)
+
+	counter := newGroupCounter(fields)
+	for obj, err := range Iter[map[string]any](ctx, c, cp) {
+		if err != nil {
+			return nil, errors.WithMessage(err, "group count")
+		}
+		counter.addObject(obj)
+	}
+
+	return counter.buckets(), nil
+}
+
+// groupCounter accumulates bucket counts keyed by their values.
+type groupCounter struct {
+	fields  []string
+	counts  map[string]*GroupBucket
+	ordered []*GroupBucket
+}
+
+func newGroupCounter(fields []string) *groupCounter {
+	return &groupCounter{fields: fields, counts: map[string]*GroupBucket{}}
+}
+
+// addObject counts an object in the buckets of the cartesian product of its
+// field values.
+func (g *groupCounter) addObject(obj map[string]any) {
+	combos := [][]any{{}}
+	for _, field := range g.fields {
+		values := []any{groupValue(obj[field])}
+		if items, ok := obj[field].([]any); ok && len(items) > 0 {
+			values = make([]any, len(items))
+			for i, item := range items {
+				values[i] = groupValue(item)
+			}
+		}
+
+		next := make([][]any, 0, len(combos)*len(values))
+		for _, combo := range combos {
+			for _, v := range values {
+				next = append(next, append(slices.Clone(combo), v))
+			}
+		}
+		combos = next
+	}
+
+	for _, combo := range combos {
+		g.add(combo, 1)
+	}
+}
+
+func (g *groupCounter) add(values []any, count int) {
+	key, _ := json.Marshal(values)
+	b, ok := g.counts[string(key)]
+	if !ok {
+		b = &GroupBucket{Values: make(map[string]any, len(g.fields))}
+		for i, field := range g.fields {
+			b.Values[field] = values[i]
+		}
+		g.counts[string(key)] = b
+		g.ordered = append(g.ordered, b)
+	}
+	b.Count += count
+}
+
+// buckets returns the buckets by count descending, then by values.
+func (g *groupCounter) buckets() []GroupBucket {
+	result := make([]GroupBucket, len(g.ordered))
+	for i, b := range g.ordered {
+		result[i] = *b
+	}
+	slices.SortStableFunc(result, func(a, b GroupBucket) int {
+		if n := cmp.Compare(b.Count, a.Count); n != 0 {
+			return n
+		}
+		for _, field := range g.fields {
+			if n := strings.Compare(fmt.Sprint(a.Values[field]), fmt.Sprint(b.Values[field])); n != 0 {
+				return n
+			}
+		}
+		return 0
+	})
+
+	return result
+}
+
+// groupValue reduces a referenced object to its ID and an empty list to nil.
+func groupValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if id, ok := v["id"]; ok {
+			return id
+		}
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+	}
+
+	return v
+}
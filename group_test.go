@@ -0,0 +1,192 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/imroc/req/v3"
+	"github.com/raoptimus/evateamclient.go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const groupFallbackPage = `{"jsonrpc": "2.2", "result": [
+	{"id": "CmfTask:1", "cache_status_type": "OPEN", "executors": [{"id": "CmfPerson:1"}, {"id": "CmfPerson:2"}]},
+	{"id": "CmfTask:2", "cache_status_type": "OPEN", "executors": [{"id": "CmfPerson:1"}]},
+	{"id": "CmfTask:3", "cache_status_type": "CLOSED", "executors": []}
+]}`
+
+var groupFallbackBuckets = []GroupBucket{
+	{Values: map[string]any{"cache_status_type": "OPEN", "executors": "CmfPerson:1"}, Count: 2},
+	{Values: map[string]any{"cache_status_type": "CLOSED", "executors": nil}, Count: 1},
+	{Values: map[string]any{"cache_status_type": "OPEN", "executors": "CmfPerson:2"}, Count: 1},
+}
+
+func TestClient_GroupCount_ClientByDefault(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, groupFallbackPage)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfTask.list")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.NotContains(t, string(body), `"group_by"`)
+	}
+
+	buckets, err := client.GroupCount(testCtx, NewQueryBuilder().From(EntityTask),
+		TaskFieldCacheStatusType, TaskFieldExecutors)
+
+	require.NoError(t, err)
+	assert.Equal(t, groupFallbackBuckets, buckets)
+	assert.Equal(t, 1, mockHTTP.calls)
+}
+
+func TestClient_GroupCount_Server(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	WithServerGroupBy()(client)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": [
+		{"cache_status_type": "OPEN", "responsible_id": "CmfPerson:1", "count": 3},
+		{"cache_status_type": "CLOSED", "responsible_id": null, "count": 1},
+		{"cache_status_type": "OPEN", "responsible_id": {"id": "CmfPerson:2"}, "count": 5}
+	]}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfTask.count")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"group_by":["cache_status_type","responsible_id"]`) &&
+			assert.Contains(t, string(body), `"filter":["project_id","==","CmfProject:1"]`) &&
+			assert.NotContains(t, string(body), `"slice"`) &&
+			assert.NotContains(t, string(body), `"fields"`)
+	}
+
+	qb := NewQueryBuilder().
+		Select("id").
+		From(EntityTask).
+		Where(sq.Eq{TaskFieldProjectID: "CmfProject:1"}).
+		Limit(10).
+		GroupBy(TaskFieldCacheStatusType)
+	buckets, err := client.GroupCount(testCtx, qb, TaskFieldResponsibleID)
+
+	require.NoError(t, err)
+	assert.Equal(t, []GroupBucket{
+		{Values: map[string]any{"cache_status_type": "OPEN", "responsible_id": "CmfPerson:2"}, Count: 5},
+		{Values: map[string]any{"cache_status_type": "OPEN", "responsible_id": "CmfPerson:1"}, Count: 3},
+		{Values: map[string]any{"cache_status_type": "CLOSED", "responsible_id": nil}, Count: 1},
+	}, buckets)
+	assert.Equal(t, 1, mockHTTP.calls)
+}
+
+func TestClient_GroupCount_FallsBackToClient(t *testing.T) {
+	tests := []struct {
+		name   string
+		server string
+	}{
+		{"group_by ignored", `{"jsonrpc": "2.2", "result": 42}`},
+		{"group_by rejected", `{"jsonrpc": "2.2", "error": {"code": -32602, "message": "unknown kwarg group_by"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newTestClientWithSequentialMock(t)
+			WithServerGroupBy()(client)
+			mockHTTP.responses = []*req.Response{
+				mockResponse(http.StatusOK, tt.server),
+				mockResponse(http.StatusOK, groupFallbackPage),
+				mockResponse(http.StatusOK, groupFallbackPage),
+			}
+			var methods []string
+			mockHTTP.urlCheck = func(url string) bool {
+				methods = append(methods, url[strings.Index(url, "m=")+2:])
+				return true
+			}
+
+			qb := NewQueryBuilder().From(EntityTask)
+			buckets, err := client.GroupCount(testCtx, qb, TaskFieldCacheStatusType, TaskFieldExecutors)
+
+			require.NoError(t, err)
+			assert.Equal(t, groupFallbackBuckets, buckets)
+			assert.Equal(t, []string{"CmfTask.count", "CmfTask.list"}, methods)
+
+			// The client remembers that the server cannot group.
+			_, err = client.GroupCount(testCtx, qb, TaskFieldCacheStatusType, TaskFieldExecutors)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"CmfTask.count", "CmfTask.list", "CmfTask.list"}, methods)
+		})
+	}
+}
+
+func TestClient_GroupCount_SchemaWithoutGrouping(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	WithServerGroupBy()(client)
+	mockHTTP.response = mockResponse(http.StatusOK, groupFallbackPage)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfTask.list")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"fields":["id","cache_status_type","executors"]`)
+	}
+	schema := NewSchema(EntityTask, &models.Meta{Project: models.ProjectMeta{Fields: map[string]models.FieldMeta{
+		"cache_status_type": {APIAllow: true, TEXKOMGroupByAllow: true},
+		"executors":         {APIAllow: true},
+	}}})
+
+	qb := NewQueryBuilder().Select("name").From(EntityTask).WithSchema(schema)
+	buckets, err := client.GroupCount(testCtx, qb, TaskFieldCacheStatusType, TaskFieldExecutors)
+
+	require.NoError(t, err)
+	assert.Equal(t, groupFallbackBuckets, buckets)
+	assert.Equal(t, 1, mockHTTP.calls)
+}
+
+func TestClient_GroupCount_ServerError(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	WithServerGroupBy()(client)
+	mockHTTP.response = mockResponse(http.StatusForbidden, `forbidden`)
+
+	_, err := client.GroupCount(testCtx, NewQueryBuilder().From(EntityTask), TaskFieldCacheStatusType)
+
+	require.ErrorIs(t, err, ErrForbidden)
+	assert.Equal(t, 1, mockHTTP.calls)
+}
+
+func TestClient_GroupCount_InvalidQuery(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+
+	_, err := client.GroupCount(testCtx, NewQueryBuilder().From(EntityTask))
+	assert.ErrorContains(t, err, "no group by fields")
+
+	_, err = client.GroupCount(testCtx, NewQueryBuilder(), TaskFieldCacheStatusType)
+	assert.ErrorContains(t, err, "use From()")
+
+	assert.Zero(t, mockHTTP.calls)
+}
+
+func TestClient_SprintStats_GroupsByStatus(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, groupFallbackPage)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfTask.list")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"filter":["lists","IN",["SPR-001"]]`)
+	}
+
+	stats, err := client.SprintStats(testCtx, "SPR-001")
+
+	require.NoError(t, err)
+	assert.Equal(t, &models.SprintStats{
+		SprintID:      "SPR-001",
+		TotalTasks:    3,
+		TasksByStatus: map[string]int{"OPEN": 2, "CLOSED": 1},
+	}, stats)
+	assert.Equal(t, 1, mockHTTP.calls, "no group_by request by default")
+}
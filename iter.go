@@ -0,0 +1,465 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"iter"
+
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+const defaultPageSize = 100
+
+// IterOption configures an iterator.
+type IterOption func(*iterConfig)
+
+type iterConfig struct {
+	pageSize uint64
+	maxItems uint64
+	parallel int
+	onCursor func(*Cursor)
+}
+
+// PageSize sets the number of items fetched per request (100 by default).
+func PageSize(n int) IterOption {
+	return func(cfg *iterConfig) {
+		if n > 0 {
+			cfg.pageSize = uint64(n)
+		}
+	}
+}
+
+// MaxItems stops the iteration after n items (0 means no cap).
+func MaxItems(n int) IterOption {
+	return func(cfg *iterConfig) {
+		if n > 0 {
+			cfg.maxItems = uint64(n)
+		}
+	}
+}
+
+// Parallel fetches up to n pages concurrently, like QueryBuilder.Parallel.
+//
+// The iterator counts the matches first, splits them into PageSize slice
+// windows and fetches the windows with bounded concurrency, still yielding
+// items in order. The order is pinned (cmf_created_at, id unless the query
+// sets one; id is added as tie-breaker) so that rows inserted mid-flight do
+// not shift the windows, and items are deduplicated by ID.
+func Parallel(n int) IterOption {
+	return func(cfg *iterConfig) {
+		cfg.parallel = n
+	}
+}
+
+// OnCursor calls fn in keyset mode (QueryBuilder.Keyset) after all items of
+// a page were yielded, with the cursor to resume after them. Store it to let
+// a long-running job continue with QueryBuilder.After after a restart.
+func OnCursor(fn func(*Cursor)) IterOption {
+	return func(cfg *iterConfig) {
+		cfg.onCursor = fn
+	}
+}
+
+// pager fetches pages of one entity.
+type pager[T any] struct {
+	list  func(ctx context.Context, qb *QueryBuilder) ([]T, *models.Meta, error)
+	count func(ctx context.Context, qb *QueryBuilder) (int, error)
+	// id returns the ID items are deduplicated by in parallel mode; nil
+	// disables deduplication.
+	id     func(item *T) string
+	keyset func(ctx context.Context, qb *QueryBuilder, after *Cursor, size int) ([]T, *Cursor, error)
+}
+
+// window is a [start, end) slice of the matches.
+type window struct {
+	start, end uint64
+}
+
+type windowResult[T any] struct {
+	items []T
+	err   error
+}
+
+// paginate iterates over all items matched by qb, fetching them page by page
+// with the EVA slice kwarg, or after a cursor in keyset mode (see
+// QueryBuilder.Keyset), which takes precedence over Parallel. The Offset of
+// qb is the first item, its Limit caps the number of items like MaxItems.
+// qb itself is not modified.
+//
+// Pages are fetched lazily: breaking out of the loop stops the iteration
+// without further requests. An error is yielded once and ends the iteration.
+func paginate[T any](ctx context.Context, qb *QueryBuilder, p pager[T], opts ...IterOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		cfg := iterConfig{pageSize: defaultPageSize, parallel: qb.parallel}
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+
+		offset, limit, err := qb.window()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		if limit > 0 && (cfg.maxItems == 0 || limit < cfg.maxItems) {
+			cfg.maxItems = limit
+		}
+
+		switch {
+		case qb.keyset != nil:
+			paginateKeyset(ctx, qb, p, cfg, yield)
+			return
+		case cfg.parallel > 1:
+			paginateParallel(ctx, qb, p, cfg, offset, yield)
+			return
+		}
+
+		var yielded uint64
+		for {
+			size := cfg.pageSize
+			if cfg.maxItems > 0 {
+				size = min(size, cfg.maxItems-yielded)
+			}
+
+			page, _, err := p.list(ctx, qb.clone().Offset(offset).Limit(size))
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for i := range page {
+				if !yield(page[i], nil) {
+					return
+				}
+				yielded++
+			}
+
+			if uint64(len(page)) < size || (cfg.maxItems > 0 && yielded >= cfg.maxItems) {
+				return
+			}
+			offset += size
+		}
+	}
+}
+
+// paginateKeyset fetches pages after the cursor of the previous page.
+func paginateKeyset[T any](ctx context.Context, qb *QueryBuilder, p pager[T], cfg iterConfig, yield func(T, error) bool) {
+	var zero T
+
+	after := qb.after
+	var yielded uint64
+	for {
+		size := cfg.pageSize
+		if cfg.maxItems > 0 {
+			size = min(size, cfg.maxItems-yielded)
+		}
+
+		page, next, err := p.keyset(ctx, qb, after, int(size))
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for i := range page {
+			if !yield(page[i], nil) {
+				return
+			}
+			yielded++
+		}
+
+		if next != nil && cfg.onCursor != nil {
+			cfg.onCursor(next)
+		}
+		if next == nil || (cfg.maxItems > 0 && yielded >= cfg.maxItems) {
+			return
+		}
+		after = next
+	}
+}
+
+// paginateParallel fetches the windows of the matches with cfg.parallel
+// workers and yields them in order. At most cfg.parallel windows are in
+// flight or buffered at a time.
+func paginateParallel[T any](
+	ctx context.Context,
+	qb *QueryBuilder,
+	p pager[T],
+	cfg iterConfig,
+	offset uint64,
+	yield func(T, error) bool,
+) {
+	var zero T
+
+	total, err := p.count(ctx, qb.withoutWindow())
+	if err != nil {
+		yield(zero, err)
+		return
+	}
+	end := uint64(max(total, 0))
+	if cfg.maxItems > 0 {
+		end = min(end, offset+cfg.maxItems)
+	}
+
+	var windows []window
+	for start := offset; start < end; start += cfg.pageSize {
+		windows = append(windows, window{start: start, end: min(start+cfg.pageSize, end)})
+	}
+	if len(windows) == 0 {
+		return
+	}
+
+	ordered, err := qb.withStableOrder()
+	if err != nil {
+		yield(zero, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan windowResult[T], len(windows))
+	for i := range results {
+		results[i] = make(chan windowResult[T], 1)
+	}
+	slots := make(chan struct{}, cfg.parallel)
+
+	go func() {
+		for i, w := range windows {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				items, _, err := p.list(ctx, ordered.clone().Offset(w.start).Limit(w.end-w.start))
+				results[i] <- windowResult[T]{items: items, err: err}
+			}()
+		}
+	}()
+
+	seen := make(map[string]struct{})
+	for i := range windows {
+		var res windowResult[T]
+		select {
+		case res = <-results[i]:
+		case <-ctx.Done():
+			yield(zero, ctx.Err())
+			return
+		}
+		<-slots
+
+		if res.err != nil {
+			yield(zero, res.err)
+			return
+		}
+		for j := range res.items {
+			if p.id != nil {
+				id := p.id(&res.items[j])
+				if _, dup := seen[id]; dup {
+					continue
+				}
+				seen[id] = struct{}{}
+			}
+			if !yield(res.items[j], nil) {
+				return
+			}
+		}
+	}
+}
+
+// Iter iterates over all objects of the entity of qb, using <Entity>.list
+// page by page. Set OrderBy for stable pages. Items are not deduplicated in
+// Parallel mode since their ID is unknown.
+//
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  Select("id", "name").
+//	  From("CmfCompany").
+//	  OrderBy("id")
+//	for company, err := range evateamclient.Iter[MyCompany](ctx, client, qb, evateamclient.PageSize(200)) {
+//	  if err != nil {
+//	    return err
+//	  }
+//	  fmt.Println(company.Name)
+//	}
+func Iter[T any](ctx context.Context, c *Client, qb *QueryBuilder, opts ...IterOption) iter.Seq2[T, error] {
+	return paginate(ctx, qb, pager[T]{
+		list: func(ctx context.Context, qb *QueryBuilder) ([]T, *models.Meta, error) {
+			return List[T](ctx, c, qb)
+		},
+		count:  c.countFunc,
+		keyset: keysetList[T](c, nil),
+	}, opts...)
+}
+
+// countFunc counts via the generic <Entity>.count of qb, for entities
+// without a typed Count method.
+func (c *Client) countFunc(ctx context.Context, qb *QueryBuilder) (int, error) {
+	return Count(ctx, c, qb)
+}
+
+// entityCountFunc counts entity via the generic <Entity>.count, for list
+// methods that do not need From in qb.
+func (c *Client) entityCountFunc(entity string) func(ctx context.Context, qb *QueryBuilder) (int, error) {
+	return func(ctx context.Context, qb *QueryBuilder) (int, error) {
+		return Count(ctx, c, qb.From(entity))
+	}
+}
+
+// IterProjects iterates over all projects matched by qb (see ProjectsList).
+func (c *Client) IterProjects(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.Project, error] {
+	return paginate(ctx, qb, pager[models.Project]{
+		keyset: keysetList[models.Project](c, DefaultProjectListFields),
+		list:   c.ProjectsList,
+		count:  c.ProjectCount,
+		id:     func(p *models.Project) string { return p.ID },
+	}, opts...)
+}
+
+// IterTasks iterates over all tasks matched by qb (see TasksList).
+//
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityTask).
+//	  Where(sq.Eq{"project_id": "CmfProject:uuid"}).
+//	  OrderBy("id")
+//	for task, err := range client.IterTasks(ctx, qb, evateamclient.MaxItems(1000)) {
+//	  if err != nil {
+//	    return err
+//	  }
+//	  if task.Code == "PROJ-42" {
+//	    break // no further pages are requested
+//	  }
+//	}
+func (c *Client) IterTasks(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.TaskBrowse, error] {
+	return paginate(ctx, qb, pager[models.TaskBrowse]{
+		keyset: keysetList[models.TaskBrowse](c, DefaultTaskListFields),
+		list:   c.TasksList,
+		count:  c.TaskCount,
+		id:     func(t *models.TaskBrowse) string { return t.ID },
+	}, opts...)
+}
+
+// IterTimeLogs iterates over all time logs matched by qb (see TimeLogsList).
+//
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityTimeLog).
+//	  Where(sq.Eq{"parent.project_id": "CmfProject:uuid"}).
+//	  Parallel(8)
+//	for log, err := range client.IterTimeLogs(ctx, qb, evateamclient.PageSize(500)) {
+//	  ...
+//	}
+func (c *Client) IterTimeLogs(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.TimeLog, error] {
+	return paginate(ctx, qb, pager[models.TimeLog]{
+		keyset: keysetList[models.TimeLog](c, DefaultTimeLogListFields),
+		list:   c.TimeLogsList,
+		count:  c.TimeLogCount,
+		id:     func(l *models.TimeLog) string { return l.ID },
+	}, opts...)
+}
+
+// IterComments iterates over all comments matched by qb (see CommentsList).
+func (c *Client) IterComments(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.Comment, error] {
+	return paginate(ctx, qb, pager[models.Comment]{
+		keyset: keysetList[models.Comment](c, DefaultCommentListFields),
+		list:   c.CommentsList,
+		count:  c.CommentCount,
+		id:     func(m *models.Comment) string { return m.ID },
+	}, opts...)
+}
+
+// IterDocuments iterates over all documents matched by qb (see DocumentsList).
+func (c *Client) IterDocuments(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.Document, error] {
+	return paginate(ctx, qb, pager[models.Document]{
+		keyset: keysetList[models.Document](c, DefaultDocumentListFields),
+		list:   c.DocumentsList,
+		count:  c.DocumentCount,
+		id:     func(d *models.Document) string { return d.ID },
+	}, opts...)
+}
+
+// IterLists iterates over all lists (sprints, releases) matched by qb (see ListsList).
+func (c *Client) IterLists(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.List, error] {
+	return paginate(ctx, qb, pager[models.List]{
+		keyset: keysetList[models.List](c, DefaultListListFields),
+		list:   c.ListsList,
+		count:  c.ListCount,
+		id:     func(l *models.List) string { return l.ID },
+	}, opts...)
+}
+
+// IterPersons iterates over all persons matched by qb (see PersonsList).
+func (c *Client) IterPersons(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.Person, error] {
+	return paginate(ctx, qb, pager[models.Person]{
+		keyset: keysetList[models.Person](c, DefaultPersonListFields),
+		list:   c.PersonsList,
+		count:  c.PersonCount,
+		id:     func(p *models.Person) string { return p.ID },
+	}, opts...)
+}
+
+// IterStatusHistory iterates over all status changes matched by qb (see StatusHistoryList).
+func (c *Client) IterStatusHistory(
+	ctx context.Context,
+	qb *QueryBuilder,
+	opts ...IterOption,
+) iter.Seq2[models.StatusHistory, error] {
+	return paginate(ctx, qb, pager[models.StatusHistory]{
+		keyset: keysetList[models.StatusHistory](c, DefaultStatusHistoryListFields),
+		list:   c.StatusHistoryList,
+		count:  c.StatusHistoryCount,
+		id:     func(h *models.StatusHistory) string { return h.ID },
+	}, opts...)
+}
+
+// IterTaskLinks iterates over all task links matched by qb (see TaskLinksListQuery).
+func (c *Client) IterTaskLinks(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.TaskLink, error] {
+	return paginate(ctx, qb, pager[models.TaskLink]{
+		keyset: keysetList[models.TaskLink](c, DefaultTaskLinkListFields),
+		list:   c.TaskLinksListQuery,
+		count:  c.TaskLinkCount,
+		id:     func(l *models.TaskLink) string { return l.ID },
+	}, opts...)
+}
+
+// IterTags iterates over all tags matched by qb (see TagList).
+func (c *Client) IterTags(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.Tag, error] {
+	return paginate(ctx, qb, pager[models.Tag]{
+		keyset: keysetList[models.Tag](c, DefaultTagFields),
+		list:   c.TagList,
+		count:  c.entityCountFunc(EntityTag),
+		id:     func(t *models.Tag) string { return t.ID },
+	}, opts...)
+}
+
+// IterLogicTypes iterates over all logic types matched by qb (see LogicTypeList).
+func (c *Client) IterLogicTypes(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.LogicType, error] {
+	return paginate(ctx, qb, pager[models.LogicType]{
+		keyset: keysetList[models.LogicType](c, DefaultLogicTypeFields),
+		list:   c.LogicTypeList,
+		count:  c.entityCountFunc(EntityLogicType),
+		id:     func(t *models.LogicType) string { return t.ID },
+	}, opts...)
+}
+
+// IterAudit iterates over all audit records matched by qb (see AuditList).
+func (c *Client) IterAudit(ctx context.Context, qb *QueryBuilder, opts ...IterOption) iter.Seq2[models.Audit, error] {
+	return paginate(ctx, qb, pager[models.Audit]{
+		keyset: keysetList[models.Audit](c, DefaultAuditFields),
+		list:   c.AuditList,
+		count:  c.AuditCount,
+		id:     func(a *models.Audit) string { return a.ID },
+	}, opts...)
+}
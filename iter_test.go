@@ -0,0 +1,310 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	encjson "encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagingHTTPClient serves total tasks, honoring the slice kwarg. It is safe
+// for concurrent use.
+type pagingHTTPClient struct {
+	mu      sync.Mutex
+	total   int
+	slices  [][2]int
+	orderBy [][]string
+	counts  int
+	failAt  int  // 1-based list request number answered with 500, 0 = never
+	overlap bool // every page also returns the item before its slice
+}
+
+func (p *pagingHTTPClient) Post(_ context.Context, body []byte, _ string) (*req.Response, error) {
+	var parsed struct {
+		Method string `json:"method"`
+		Kwargs struct {
+			Slice   [2]int   `json:"slice"`
+			OrderBy []string `json:"order_by"`
+		} `json:"kwargs"`
+	}
+	if err := encjson.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if strings.HasSuffix(parsed.Method, ".count") {
+		p.counts++
+		return mockResponse(http.StatusOK, fmt.Sprintf(`{"jsonrpc":"2.2","result":%d}`, p.total)), nil
+	}
+
+	p.slices = append(p.slices, parsed.Kwargs.Slice)
+	p.orderBy = append(p.orderBy, parsed.Kwargs.OrderBy)
+	if p.failAt == len(p.slices) {
+		return mockResponse(http.StatusInternalServerError, "boom"), nil
+	}
+
+	start := parsed.Kwargs.Slice[0]
+	if p.overlap && start > 0 {
+		start--
+	}
+	items := make([]map[string]any, 0)
+	for i := start; i < min(parsed.Kwargs.Slice[1], p.total); i++ {
+		items = append(items, map[string]any{"id": fmt.Sprintf("CmfTask:%d", i), "code": fmt.Sprintf("T-%d", i)})
+	}
+	result, err := encjson.Marshal(map[string]any{"jsonrpc": "2.2", "result": items})
+	if err != nil {
+		return nil, err
+	}
+
+	return mockResponse(http.StatusOK, string(result)), nil
+}
+
+func newPagingTestClient(t *testing.T, total int) (*Client, *pagingHTTPClient) {
+	t.Helper()
+
+	client, _ := newTestClient(t)
+	server := &pagingHTTPClient{total: total}
+	client.httpClient = server
+
+	return client, server
+}
+
+func taskQuery() *QueryBuilder {
+	return NewQueryBuilder().From(EntityTask).Where(sq.Eq{"project_id": "CmfProject:1"}).OrderBy("id")
+}
+
+func TestClient_IterTasks_AllPages(t *testing.T) {
+	client, server := newPagingTestClient(t, 25)
+
+	var codes []string
+	for task, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10)) {
+		require.NoError(t, err)
+		codes = append(codes, task.Code)
+	}
+
+	assert.Len(t, codes, 25)
+	assert.Equal(t, "T-24", codes[24])
+	assert.Equal(t, [][2]int{{0, 10}, {10, 20}, {20, 30}}, server.slices)
+}
+
+func TestClient_IterTasks_ExactMultipleOfPageSize(t *testing.T) {
+	client, server := newPagingTestClient(t, 20)
+
+	n := 0
+	for _, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10)) {
+		require.NoError(t, err)
+		n++
+	}
+
+	assert.Equal(t, 20, n)
+	assert.Len(t, server.slices, 3, "an empty page ends the iteration")
+}
+
+func TestClient_IterTasks_MaxItems(t *testing.T) {
+	client, server := newPagingTestClient(t, 100)
+
+	n := 0
+	for _, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10), MaxItems(15)) {
+		require.NoError(t, err)
+		n++
+	}
+
+	assert.Equal(t, 15, n)
+	assert.Equal(t, [][2]int{{0, 10}, {10, 15}}, server.slices)
+}
+
+func TestClient_IterTasks_QueryOffsetAndLimit(t *testing.T) {
+	client, server := newPagingTestClient(t, 100)
+	qb := taskQuery().Offset(30).Limit(12)
+
+	n := 0
+	for _, err := range client.IterTasks(testCtx, qb, PageSize(5)) {
+		require.NoError(t, err)
+		n++
+	}
+
+	assert.Equal(t, 12, n)
+	assert.Equal(t, [][2]int{{30, 35}, {35, 40}, {40, 42}}, server.slices)
+
+	kwargs, err := qb.ToKwargs()
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{30, 42}, kwargs["slice"], "the caller's query is not modified")
+}
+
+func TestClient_IterTasks_BreakStopsFetching(t *testing.T) {
+	client, server := newPagingTestClient(t, 100)
+
+	for task, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10)) {
+		require.NoError(t, err)
+		if task.Code == "T-12" {
+			break
+		}
+	}
+
+	assert.Len(t, server.slices, 2)
+}
+
+func TestClient_IterTasks_ErrorEndsIteration(t *testing.T) {
+	client, server := newPagingTestClient(t, 100)
+	server.failAt = 2
+
+	n := 0
+	var iterErr error
+	for _, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10)) {
+		if err != nil {
+			iterErr = err
+			continue
+		}
+		n++
+	}
+
+	require.Error(t, iterErr)
+	assert.Equal(t, 10, n)
+	assert.Len(t, server.slices, 2)
+}
+
+func TestIter_Generic(t *testing.T) {
+	client, server := newPagingTestClient(t, 3)
+
+	var ids []string
+	for company, err := range Iter[testCompany](testCtx, client, NewQueryBuilder().From("CmfCompany"), PageSize(2)) {
+		require.NoError(t, err)
+		ids = append(ids, company.ID)
+	}
+
+	assert.Equal(t, []string{"CmfTask:0", "CmfTask:1", "CmfTask:2"}, ids)
+	assert.Len(t, server.slices, 2)
+}
+
+func TestIter_InvalidQuery(t *testing.T) {
+	client, server := newPagingTestClient(t, 3)
+
+	var errs []error
+	for _, err := range Iter[testCompany](testCtx, client, NewQueryBuilder().Where(errors.New("bad"))) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	require.Error(t, errs[0])
+	assert.Empty(t, server.slices)
+}
+
+func TestClient_IterTasks_Parallel(t *testing.T) {
+	client, server := newPagingTestClient(t, 95)
+
+	var codes []string
+	for task, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10), Parallel(4)) {
+		require.NoError(t, err)
+		codes = append(codes, task.Code)
+	}
+
+	require.Len(t, codes, 95)
+	for i, code := range codes {
+		assert.Equal(t, fmt.Sprintf("T-%d", i), code, "items are yielded in order")
+	}
+	assert.Equal(t, 1, server.counts)
+	assert.Len(t, server.slices, 10)
+	slices.SortFunc(server.slices, func(a, b [2]int) int { return a[0] - b[0] })
+	assert.Equal(t, [2]int{90, 95}, server.slices[9])
+}
+
+func TestClient_IterTags_Parallel_WithoutFrom(t *testing.T) {
+	client, server := newPagingTestClient(t, 25)
+
+	n := 0
+	for _, err := range client.IterTags(testCtx, NewQueryBuilder(), PageSize(10), Parallel(2)) {
+		require.NoError(t, err)
+		n++
+	}
+
+	assert.Equal(t, 25, n)
+	assert.Equal(t, 1, server.counts)
+}
+
+func TestClient_IterTasks_Parallel_PinsOrder(t *testing.T) {
+	client, server := newPagingTestClient(t, 5)
+
+	for _, err := range client.IterTasks(testCtx, NewQueryBuilder().From(EntityTask), Parallel(2)) {
+		require.NoError(t, err)
+	}
+	for _, err := range client.IterTasks(testCtx, NewQueryBuilder().From(EntityTask).OrderBy("-priority"), Parallel(2)) {
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, [][]string{{"cmf_created_at", "id"}, {"-priority", "id"}}, server.orderBy)
+}
+
+func TestClient_IterTasks_Parallel_DeduplicatesByID(t *testing.T) {
+	client, server := newPagingTestClient(t, 30)
+	server.overlap = true
+
+	var codes []string
+	for task, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10), Parallel(3)) {
+		require.NoError(t, err)
+		codes = append(codes, task.Code)
+	}
+
+	assert.Len(t, codes, 30)
+}
+
+func TestClient_IterTasks_Parallel_QueryBuilderOptionAndMaxItems(t *testing.T) {
+	client, server := newPagingTestClient(t, 100)
+
+	n := 0
+	for _, err := range client.IterTasks(testCtx, taskQuery().Parallel(3), PageSize(10), MaxItems(25)) {
+		require.NoError(t, err)
+		n++
+	}
+
+	assert.Equal(t, 25, n)
+	assert.Equal(t, 1, server.counts)
+	slices.SortFunc(server.slices, func(a, b [2]int) int { return a[0] - b[0] })
+	assert.Equal(t, [][2]int{{0, 10}, {10, 20}, {20, 25}}, server.slices)
+}
+
+func TestClient_IterTasks_Parallel_BreakStopsFetching(t *testing.T) {
+	client, server := newPagingTestClient(t, 1000)
+
+	for _, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10), Parallel(2)) {
+		require.NoError(t, err)
+		break
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.LessOrEqual(t, len(server.slices), 3, "at most the windows in flight are fetched")
+}
+
+func TestClient_IterTasks_Parallel_Error(t *testing.T) {
+	client, server := newPagingTestClient(t, 50)
+	server.failAt = 1
+
+	var iterErr error
+	for _, err := range client.IterTasks(testCtx, taskQuery(), PageSize(10), Parallel(2)) {
+		if err != nil {
+			iterErr = err
+		}
+	}
+
+	require.Error(t, iterErr)
+}
@@ -0,0 +1,319 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	encjson "encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultKeysetKeys is the key tuple of keyset pagination unless
+// QueryBuilder.Keyset sets another one. It must be unique per row, and its
+// fields must be immutable and never null: a row whose key changes while
+// paging moves across the cursor and is skipped or returned twice, and a
+// null key matches neither the "==" nor the ">" condition of a page.
+var DefaultKeysetKeys = []string{"cmf_created_at", "id"}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position after the last row of a keyset page. It is opaque:
+// store it with String and restore it with ParseCursor.
+type Cursor struct {
+	keys   []string
+	values []any
+}
+
+type cursorToken struct {
+	Keys   []string `json:"k"`
+	Values []any    `json:"v"`
+}
+
+// String returns the serialised cursor, safe for URLs and JSON.
+func (c *Cursor) String() string {
+	raw, err := json.Marshal(cursorToken{Keys: c.keys, Values: c.values})
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseCursor restores a cursor serialised with Cursor.String.
+func ParseCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.WithStack(ErrInvalidCursor)
+	}
+
+	dec := encjson.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var t cursorToken
+	if err := dec.Decode(&t); err != nil || len(t.Keys) == 0 || len(t.Keys) != len(t.Values) {
+		return nil, errors.WithStack(ErrInvalidCursor)
+	}
+
+	return &Cursor{keys: t.Keys, values: t.Values}, nil
+}
+
+// ListKeyset fetches up to size rows of a list method (e.g. "CmfTask.list")
+// ordered by the key tuple, starting after the cursor (nil for the first
+// page). It returns the raw rows and the cursor of the next page, nil after
+// the last page. keys defaults to DefaultKeysetKeys; prefix a key with "-"
+// for descending order. Like the defaults, the keys must be unique per row,
+// immutable and never null; a null key in the last row of a page fails.
+//
+// Unlike slice pagination, rows created or deleted while paging never shift
+// the pages. The "slice" and "order_by" kwargs are replaced; the keys are
+// added to "fields" when it is set.
+//
+// Example:
+//
+//	var cursor *evateamclient.Cursor
+//	for {
+//	  rows, next, err := client.ListKeyset(ctx, "CmfTask.list", kwargs, nil, cursor, 500)
+//	  ...
+//	  if next == nil {
+//	    break
+//	  }
+//	  saveProgress(next.String())
+//	  cursor = next
+//	}
+func (c *Client) ListKeyset(
+	ctx context.Context,
+	method string,
+	kwargs map[string]any,
+	keys []string,
+	after *Cursor,
+	size int,
+) ([]encjson.RawMessage, *Cursor, error) {
+	if len(keys) == 0 {
+		keys = DefaultKeysetKeys
+	}
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	if after != nil && !slices.Equal(after.keys, keys) {
+		return nil, nil, errors.Wrapf(ErrInvalidCursor, "cursor keys %v do not match %v", after.keys, keys)
+	}
+
+	base := maps.Clone(kwargs)
+	if base == nil {
+		base = make(map[string]any)
+	}
+	delete(base, "slice")
+	base["order_by"] = keys
+	if fields, ok := base["fields"].([]string); ok {
+		base["fields"] = withKeyFields(fields, keys)
+	}
+	filters := normalizeFilters(base["filter"])
+
+	var rows []encjson.RawMessage
+	for _, cond := range keysetConditions(keys, after) {
+		branch := maps.Clone(base)
+		switch all := append(slices.Clone(filters), cond...); len(all) {
+		case 0:
+			delete(branch, "filter")
+		case 1:
+			branch["filter"] = all[0]
+		default:
+			branch["filter"] = all
+		}
+		branch["slice"] = []int{0, size - len(rows)}
+
+		page, err := c.listRaw(ctx, method, branch)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, page...)
+		if len(rows) >= size {
+			break
+		}
+	}
+
+	if len(rows) < size {
+		return rows, nil, nil
+	}
+	next, err := cursorFromRow(rows[len(rows)-1], keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rows, next, nil
+}
+
+// listRaw sends a list method and returns its rows undecoded.
+func (c *Client) listRaw(ctx context.Context, method string, kwargs map[string]any) ([]encjson.RawMessage, error) {
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  method,
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp callResponse[[]encjson.RawMessage]
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, errors.WithMessagef(err, "failed to call %s", method)
+	}
+
+	return resp.Result, nil
+}
+
+// keysetConditions splits "keys after cursor" into AND-only filters whose
+// results are disjoint and, fetched in order, sorted by the keys. For keys
+// (a, b) after (va, vb): [a == va, b > vb], then [a > va].
+func keysetConditions(keys []string, after *Cursor) [][]any {
+	if after == nil {
+		return [][]any{nil}
+	}
+
+	conds := make([][]any, 0, len(keys))
+	for i := len(keys) - 1; i >= 0; i-- {
+		cond := make([]any, 0, i+1)
+		for j := range i {
+			cond = append(cond, []any{strings.TrimPrefix(keys[j], "-"), "==", after.values[j]})
+		}
+		field, op := strings.TrimPrefix(keys[i], "-"), ">"
+		if strings.HasPrefix(keys[i], "-") {
+			op = "<"
+		}
+		conds = append(conds, append(cond, []any{field, op, after.values[i]}))
+	}
+
+	return conds
+}
+
+// normalizeFilters returns the AND operands of a filter kwarg, which is a
+// single condition, a list of them, or an OR / NOT expression (see bql).
+func normalizeFilters(filter any) []any {
+	switch f := filter.(type) {
+	case nil:
+		return nil
+	case [][]any:
+		conds := make([]any, len(f))
+		for i := range f {
+			conds[i] = f[i]
+		}
+		return conds
+	case []any:
+		if slices.ContainsFunc(f, func(item any) bool { _, ok := item.(string); return ok }) {
+			return []any{f}
+		}
+		return slices.Clone(f)
+	default:
+		return []any{f}
+	}
+}
+
+func withKeyFields(fields, keys []string) []string {
+	fields = slices.Clone(fields)
+	for _, key := range keys {
+		if field := strings.TrimPrefix(key, "-"); !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+func cursorFromRow(row encjson.RawMessage, keys []string) (*Cursor, error) {
+	dec := encjson.NewDecoder(bytes.NewReader(row))
+	dec.UseNumber()
+	var values map[string]any
+	if err := dec.Decode(&values); err != nil {
+		return nil, errors.WithMessage(err, "decode cursor row")
+	}
+
+	cursor := &Cursor{keys: keys, values: make([]any, len(keys))}
+	for i, key := range keys {
+		value, ok := values[strings.TrimPrefix(key, "-")]
+		if !ok {
+			return nil, errors.Errorf("cursor key %q is missing in the result", key)
+		}
+		if value == nil {
+			return nil, errors.Errorf("cursor key %q is null in the result", key)
+		}
+		cursor.values[i] = value
+	}
+
+	return cursor, nil
+}
+
+// ListPage fetches one keyset page of the entity of qb: Limit rows (100 by
+// default) ordered by the Keyset keys, after the After cursor. The returned
+// cursor resumes after the last row; it is nil after the last page.
+//
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  Select("id", "code", "name").
+//	  From(evateamclient.EntityTask).
+//	  Where(sq.Eq{"project_id": "CmfProject:uuid"}).
+//	  Limit(200)
+//	tasks, next, err := evateamclient.ListPage[models.TaskBrowse](ctx, client, qb)
+//	// later, possibly in another process:
+//	cursor, err := evateamclient.ParseCursor(next.String())
+//	tasks, next, err = evateamclient.ListPage[models.TaskBrowse](ctx, client, qb.After(cursor))
+func ListPage[T any](ctx context.Context, c *Client, qb *QueryBuilder) ([]T, *Cursor, error) {
+	_, limit, err := qb.window()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return keysetList[T](c, nil)(ctx, qb, qb.after, int(limit))
+}
+
+// keysetList returns a keyset page fetcher of the entity of qb; defaultFields
+// are requested when qb selects none.
+func keysetList[T any](
+	c *Client,
+	defaultFields []string,
+) func(ctx context.Context, qb *QueryBuilder, after *Cursor, size int) ([]T, *Cursor, error) {
+	return func(ctx context.Context, qb *QueryBuilder, after *Cursor, size int) ([]T, *Cursor, error) {
+		kwargs, err := qb.ToKwargs()
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := kwargs["fields"]; !ok && len(defaultFields) > 0 {
+			kwargs["fields"] = defaultFields
+		}
+		method, err := qb.ToMethod(false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		rows, next, err := c.ListKeyset(ctx, method, kwargs, qb.keysetKeys(), after, size)
+		if err != nil {
+			return nil, nil, err
+		}
+		items, err := decodeRows[T](rows, method)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return items, next, nil
+	}
+}
+
+func decodeRows[T any](rows []encjson.RawMessage, method string) ([]T, error) {
+	items := make([]T, len(rows))
+	for i := range rows {
+		if err := json.Unmarshal(rows[i], &items[i]); err != nil {
+			return nil, errors.WithMessagef(err, "unmarshal %s result", method)
+		}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,310 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"cmp"
+	"context"
+	encjson "encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keysetRow struct {
+	ID      string `json:"id"`
+	Code    string `json:"code"`
+	Created string `json:"cmf_created_at"`
+}
+
+// keysetHTTPClient serves rows applying the filter, order_by (ascending
+// cmf_created_at, id) and slice kwargs like the EVA server.
+type keysetHTTPClient struct {
+	mu       sync.Mutex
+	rows     []keysetRow
+	requests []map[string]any
+	// beforeList runs before every list request, e.g. to insert rows.
+	beforeList func(k *keysetHTTPClient)
+}
+
+func (k *keysetHTTPClient) Post(_ context.Context, body []byte, _ string) (*req.Response, error) {
+	var parsed struct {
+		Kwargs map[string]any `json:"kwargs"`
+	}
+	if err := encjson.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.requests = append(k.requests, parsed.Kwargs)
+	if k.beforeList != nil {
+		k.beforeList(k)
+	}
+
+	rows := slices.Clone(k.rows)
+	slices.SortFunc(rows, func(a, b keysetRow) int {
+		return cmp.Or(cmp.Compare(a.Created, b.Created), cmp.Compare(a.ID, b.ID))
+	})
+	rows = slices.DeleteFunc(rows, func(r keysetRow) bool { return !matchesFilter(r, parsed.Kwargs["filter"]) })
+	if s, ok := parsed.Kwargs["slice"].([]any); ok {
+		start, end := int(s[0].(float64)), int(s[1].(float64))
+		rows = rows[min(start, len(rows)):min(end, len(rows))]
+	}
+
+	result, err := encjson.Marshal(map[string]any{"jsonrpc": "2.2", "result": rows})
+	if err != nil {
+		return nil, err
+	}
+
+	return mockResponse(http.StatusOK, string(result)), nil
+}
+
+func matchesFilter(r keysetRow, filter any) bool {
+	conds, _ := filter.([]any)
+	if len(conds) == 0 {
+		return true
+	}
+	if _, single := conds[0].(string); single {
+		conds = []any{conds}
+	}
+
+	values := map[string]string{"id": r.ID, "code": r.Code, "cmf_created_at": r.Created}
+	for _, c := range conds {
+		cond := c.([]any)
+		got, want := values[cond[0].(string)], fmt.Sprint(cond[2])
+		var ok bool
+		switch cond[1] {
+		case "==":
+			ok = got == want
+		case ">":
+			ok = got > want
+		case "<":
+			ok = got < want
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newKeysetTestClient(t *testing.T, rows []keysetRow) (*Client, *keysetHTTPClient) {
+	t.Helper()
+
+	client, _ := newTestClient(t)
+	server := &keysetHTTPClient{rows: rows}
+	client.httpClient = server
+
+	return client, server
+}
+
+// keysetRows returns n rows; every three rows share a creation time.
+func keysetRows(n int) []keysetRow {
+	rows := make([]keysetRow, n)
+	for i := range rows {
+		rows[i] = keysetRow{
+			ID:      fmt.Sprintf("CmfTask:%03d", i),
+			Code:    fmt.Sprintf("T-%d", i),
+			Created: fmt.Sprintf("2026-01-01T00:%02d:00Z", i/3),
+		}
+	}
+
+	return rows
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := &Cursor{keys: DefaultKeysetKeys, values: []any{"2026-01-01T00:00:00Z", "CmfTask:1"}}
+
+	parsed, err := ParseCursor(cursor.String())
+
+	require.NoError(t, err)
+	assert.Equal(t, cursor.keys, parsed.keys)
+	assert.Equal(t, cursor.values, parsed.values)
+}
+
+func TestParseCursor_Invalid(t *testing.T) {
+	for _, token := range []string{"", "!!!", "e30", "eyJrIjpbImlkIl0sInYiOltdfQ"} {
+		_, err := ParseCursor(token)
+		require.ErrorIs(t, err, ErrInvalidCursor, token)
+	}
+}
+
+func TestKeysetConditions(t *testing.T) {
+	after := &Cursor{values: []any{"2026-01-01", "CmfTask:1"}}
+
+	assert.Equal(t, [][]any{nil}, keysetConditions([]string{"cmf_created_at", "id"}, nil))
+	assert.Equal(t, [][]any{
+		{[]any{"cmf_created_at", "==", "2026-01-01"}, []any{"id", ">", "CmfTask:1"}},
+		{[]any{"cmf_created_at", ">", "2026-01-01"}},
+	}, keysetConditions([]string{"cmf_created_at", "id"}, after))
+	assert.Equal(t, [][]any{
+		{[]any{"cmf_created_at", "==", "2026-01-01"}, []any{"id", "<", "CmfTask:1"}},
+		{[]any{"cmf_created_at", "<", "2026-01-01"}},
+	}, keysetConditions([]string{"-cmf_created_at", "-id"}, after))
+}
+
+func TestClient_ListKeyset_PagesAcrossTies(t *testing.T) {
+	client, _ := newKeysetTestClient(t, keysetRows(10))
+
+	var codes []string
+	var cursor *Cursor
+	for range 10 {
+		rows, next, err := client.ListKeyset(testCtx, "CmfTask.list", nil, nil, cursor, 4)
+		require.NoError(t, err)
+		for _, raw := range rows {
+			var r keysetRow
+			require.NoError(t, encjson.Unmarshal(raw, &r))
+			codes = append(codes, r.Code)
+		}
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+
+	assert.Equal(t, []string{"T-0", "T-1", "T-2", "T-3", "T-4", "T-5", "T-6", "T-7", "T-8", "T-9"}, codes)
+}
+
+func TestClient_ListKeyset_AddsKeysToFieldsAndKeepsFilter(t *testing.T) {
+	client, server := newKeysetTestClient(t, keysetRows(3))
+
+	_, _, err := client.ListKeyset(testCtx, "CmfTask.list", map[string]any{
+		"fields": []string{"code"},
+		"filter": []any{"code", "==", "T-1"},
+		"slice":  []int{50, 60},
+	}, nil, &Cursor{keys: DefaultKeysetKeys, values: []any{"2026-01-01T00:00:00Z", "CmfTask:000"}}, 10)
+
+	require.NoError(t, err)
+	require.Len(t, server.requests, 2)
+	first := server.requests[0]
+	assert.Equal(t, []any{"code", "cmf_created_at", "id"}, first["fields"])
+	assert.Equal(t, []any{"cmf_created_at", "id"}, first["order_by"])
+	assert.Equal(t, []any{float64(0), float64(10)}, first["slice"])
+	assert.Equal(t, []any{
+		[]any{"code", "==", "T-1"},
+		[]any{"cmf_created_at", "==", "2026-01-01T00:00:00Z"},
+		[]any{"id", ">", "CmfTask:000"},
+	}, first["filter"])
+}
+
+func TestClient_ListKeyset_CursorKeysMismatch(t *testing.T) {
+	client, server := newKeysetTestClient(t, nil)
+
+	_, _, err := client.ListKeyset(testCtx, "CmfTask.list", nil, []string{"id"},
+		&Cursor{keys: DefaultKeysetKeys, values: []any{"x", "y"}}, 10)
+
+	require.ErrorIs(t, err, ErrInvalidCursor)
+	assert.Empty(t, server.requests)
+}
+
+func TestClient_ListKeyset_NullKeyFails(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK,
+		`{"jsonrpc":"2.2","result":[{"id":"CmfTask:1","cmf_created_at":null}]}`)
+
+	_, _, err := client.ListKeyset(testCtx, "CmfTask.list", nil, nil, nil, 1)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "null")
+}
+
+func TestClient_IterTasks_Keyset_StableUnderInserts(t *testing.T) {
+	client, server := newKeysetTestClient(t, keysetRows(9))
+	inserted := 0
+	server.beforeList = func(k *keysetHTTPClient) {
+		// Every request, a task is modified "now" and moves to the end.
+		inserted++
+		k.rows = append(k.rows, keysetRow{
+			ID:      fmt.Sprintf("CmfTask:new%d", inserted),
+			Code:    fmt.Sprintf("NEW-%d", inserted),
+			Created: fmt.Sprintf("2026-01-02T00:00:%02dZ", inserted),
+		})
+	}
+
+	seen := make(map[string]int)
+	for task, err := range client.IterTasks(testCtx, NewQueryBuilder().From(EntityTask).Keyset(), PageSize(4)) {
+		require.NoError(t, err)
+		seen[task.Code]++
+	}
+
+	for code, count := range seen {
+		assert.Equal(t, 1, count, code)
+	}
+	for i := range 9 {
+		assert.Contains(t, seen, fmt.Sprintf("T-%d", i))
+	}
+}
+
+func TestClient_IterTasks_Keyset_OnCursorResume(t *testing.T) {
+	client, _ := newKeysetTestClient(t, keysetRows(10))
+
+	var saved string
+	var first []string
+	for task, err := range client.IterTasks(testCtx, NewQueryBuilder().From(EntityTask).Keyset(), PageSize(3),
+		OnCursor(func(c *Cursor) { saved = c.String() })) {
+		require.NoError(t, err)
+		first = append(first, task.Code)
+		if len(first) == 7 {
+			break
+		}
+	}
+	require.NotEmpty(t, saved)
+
+	cursor, err := ParseCursor(saved)
+	require.NoError(t, err)
+	var rest []string
+	for task, err := range client.IterTasks(testCtx, NewQueryBuilder().From(EntityTask).After(cursor), PageSize(3)) {
+		require.NoError(t, err)
+		rest = append(rest, task.Code)
+	}
+
+	assert.Equal(t, []string{"T-6", "T-7", "T-8", "T-9"}, rest)
+}
+
+func TestListPage_Resume(t *testing.T) {
+	client, server := newKeysetTestClient(t, keysetRows(5))
+	qb := func() *QueryBuilder {
+		return NewQueryBuilder().Select("id", "code").From(EntityTask).Where(sq.Gt{"code": "T"}).Limit(3)
+	}
+
+	page, next, err := ListPage[keysetRow](testCtx, client, qb())
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	require.NotNil(t, next)
+
+	cursor, err := ParseCursor(next.String())
+	require.NoError(t, err)
+	page, next, err = ListPage[keysetRow](testCtx, client, qb().After(cursor))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"T-3", "T-4"}, []string{page[0].Code, page[1].Code})
+	assert.Nil(t, next, "a short page is the last one")
+	assert.Equal(t, []any{"id", "code", "cmf_created_at"}, server.requests[0]["fields"])
+}
+
+func TestNormalizeFilters(t *testing.T) {
+	cond := []any{"code", "==", "T-1"}
+	or := []any{cond, "or", []any{"code", "==", "T-2"}}
+
+	assert.Nil(t, normalizeFilters(nil))
+	assert.Equal(t, []any{cond}, normalizeFilters(cond))
+	assert.Equal(t, []any{cond, cond}, normalizeFilters([]any{cond, cond}))
+	assert.Equal(t, []any{or}, normalizeFilters(or), "an OR expression is one operand")
+	assert.Equal(t, []any{[]any{"not", cond}}, normalizeFilters([]any{"not", cond}))
+}
@@ -10,6 +10,7 @@ package evateamclient
 
 import (
 	"context"
+	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/pkg/errors"
@@ -106,7 +107,7 @@ func (c *Client) LogicTypeByCode(
 	}
 
 	if len(items) == 0 {
-		return nil, errors.Errorf("logic type with code %q not found", code)
+		return nil, errors.WithStack(fmt.Errorf("logic type with code %q %w", code, ErrNotFound))
 	}
 
 	return &items[0], nil
@@ -0,0 +1,687 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// EVA stores rich text (task descriptions, documents, notepads) as HTML.
+// MarkdownToHTML and HTMLToMarkdown convert it from and to a Markdown subset:
+//
+//   - ATX headings (# .. ######), paragraphs, --- rules
+//   - "- " and "1. " lists (nested by indentation), "> " quotes
+//   - ``` fenced code blocks
+//   - **bold**, *italic*, ~~strike~~, `code`, [links](url), ![images](src)
+//
+// Line breaks inside a paragraph are kept (<br>), "_" is literal text and
+// "\" escapes punctuation. Link and image URLs other than http, https,
+// mailto and relative ones are dropped in both directions, keeping the text. Markdown written in this subset round-trips:
+// HTMLToMarkdown(MarkdownToHTML(md)) gives md back in canonical form, and
+// so does MarkdownToHTML(HTMLToMarkdown(h)) for h produced by MarkdownToHTML.
+
+var (
+	mdHeading     = regexp.MustCompile(`^(#{1,6})(?:\s+(.*?))?\s*$`)
+	mdRule        = regexp.MustCompile(`^(?:-\s*){3,}$|^(?:\*\s*){3,}$`)
+	mdListMarker  = regexp.MustCompile(`^( {0,3})([-+*]|\d{1,9}\.)(?: +|$)`)
+	mdBlockEscape = regexp.MustCompile(`^(?:#{1,6}(?:\s|$)|>|[-+](?:\s|$)|-{3,}|` + "```" + `)`)
+	mdOrderedText = regexp.MustCompile(`^(\d{1,9})\.(\s|$)`)
+	mdSpaces      = regexp.MustCompile(`[ \t\r\n\f]+`)
+)
+
+// MarkdownToHTML converts Markdown to the HTML EVA stores in text fields.
+func MarkdownToHTML(md string) string {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+
+	var b strings.Builder
+	mdRenderBlocks(&b, strings.Split(md, "\n"), false)
+
+	return b.String()
+}
+
+// mdRenderBlocks renders block-level Markdown. In tight mode (list items
+// without blank lines) paragraphs are not wrapped in <p>.
+func mdRenderBlocks(b *strings.Builder, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case strings.HasPrefix(trimmed, "```"):
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			i++
+			start := i
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+				i++
+			}
+			b.WriteString("<pre><code")
+			if lang != "" {
+				b.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+			}
+			b.WriteString(">")
+			b.WriteString(html.EscapeString(strings.Join(lines[start:i], "\n")))
+			b.WriteString("</code></pre>")
+			i++ // closing fence
+
+		case mdHeading.MatchString(trimmed):
+			m := mdHeading.FindStringSubmatch(trimmed)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">")
+			mdRenderInline(b, m[2])
+			b.WriteString("</h" + level + ">")
+			i++
+
+		case mdRule.MatchString(trimmed):
+			b.WriteString("<hr>")
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(t, ">") {
+					break
+				}
+				t = strings.TrimPrefix(t, ">")
+				quoted = append(quoted, strings.TrimPrefix(t, " "))
+			}
+			b.WriteString("<blockquote>")
+			mdRenderBlocks(b, quoted, false)
+			b.WriteString("</blockquote>")
+
+		case mdListMarker.MatchString(line):
+			i = mdRenderList(b, lines, i)
+
+		default:
+			var para []string
+			for ; i < len(lines); i++ {
+				if len(para) > 0 && mdStartsBlock(lines[i]) {
+					break
+				}
+				para = append(para, strings.TrimSpace(lines[i]))
+			}
+			if !tight {
+				b.WriteString("<p>")
+			}
+			for j, l := range para {
+				if j > 0 {
+					b.WriteString("<br>")
+				}
+				mdRenderInline(b, l)
+			}
+			if !tight {
+				b.WriteString("</p>")
+			}
+		}
+	}
+}
+
+// mdStartsBlock reports whether line ends a paragraph.
+func mdStartsBlock(line string) bool {
+	t := strings.TrimSpace(line)
+
+	return t == "" ||
+		strings.HasPrefix(t, "```") ||
+		strings.HasPrefix(t, ">") ||
+		mdHeading.MatchString(t) ||
+		mdRule.MatchString(t) ||
+		mdListMarker.MatchString(line)
+}
+
+// mdRenderList renders the list starting at lines[i] and returns the index
+// of the first line after it.
+func mdRenderList(b *strings.Builder, lines []string, i int) int {
+	ordered := mdIsOrdered(mdListMarker.FindStringSubmatch(lines[i])[2])
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+
+	b.WriteString("<" + tag + ">")
+	for i < len(lines) {
+		m := mdListMarker.FindStringSubmatch(lines[i])
+		if m == nil || mdIsOrdered(m[2]) != ordered {
+			break
+		}
+
+		contentIndent := len(m[0])
+		item := []string{lines[i][len(m[0]):]}
+		loose := false
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line continues the item only if it is followed by
+				// an indented line.
+				j := i + 1
+				for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+					j++
+				}
+				if j == len(lines) || mdIndent(lines[j]) < min(contentIndent, 2) {
+					break
+				}
+				loose = true
+				item = append(item, "")
+				continue
+			}
+			indent := mdIndent(line)
+			if indent < min(contentIndent, 2) {
+				break
+			}
+			item = append(item, line[min(indent, contentIndent):])
+		}
+
+		b.WriteString("<li>")
+		mdRenderBlocks(b, item, !loose)
+		b.WriteString("</li>")
+
+		// Skip the blank lines between the items of a list.
+		j := i
+		for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+			j++
+		}
+		if j < len(lines) {
+			if m := mdListMarker.FindStringSubmatch(lines[j]); m != nil && mdIsOrdered(m[2]) == ordered {
+				i = j
+			}
+		}
+	}
+	b.WriteString("</" + tag + ">")
+
+	return i
+}
+
+func mdIsOrdered(marker string) bool {
+	return strings.HasSuffix(marker, ".")
+}
+
+func mdIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// mdRenderInline renders inline Markdown: escapes, code spans, emphasis,
+// links and images.
+func mdRenderInline(b *strings.Builder, s string) {
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && mdIsPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case s[i] == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(s[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(s[i:], "**"):
+			if end := mdFindClose(s, i+2, "**"); end > i+2 {
+				b.WriteString("<strong>")
+				mdRenderInline(b, s[i+2:end])
+				b.WriteString("</strong>")
+				i = end + 2
+				continue
+			}
+
+		case strings.HasPrefix(s[i:], "~~"):
+			if end := mdFindClose(s, i+2, "~~"); end > i+2 {
+				b.WriteString("<s>")
+				mdRenderInline(b, s[i+2:end])
+				b.WriteString("</s>")
+				i = end + 2
+				continue
+			}
+
+		case s[i] == '*':
+			if end := mdFindClose(s, i+1, "*"); end > i+1 {
+				b.WriteString("<em>")
+				mdRenderInline(b, s[i+1:end])
+				b.WriteString("</em>")
+				i = end + 1
+				continue
+			}
+
+		case strings.HasPrefix(s[i:], "!["):
+			if text, src, n, ok := mdParseLink(s[i+1:]); ok {
+				if mdSafeURL(src) {
+					b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(text) + `">`)
+				} else {
+					b.WriteString(html.EscapeString(text))
+				}
+				i += n + 1
+				continue
+			}
+
+		case s[i] == '[':
+			if text, href, n, ok := mdParseLink(s[i:]); ok {
+				if !mdSafeURL(href) {
+					mdRenderInline(b, text)
+					i += n
+					continue
+				}
+				b.WriteString(`<a href="` + html.EscapeString(href) + `">`)
+				mdRenderInline(b, text)
+				b.WriteString("</a>")
+				i += n
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+}
+
+// mdFindClose returns the index of the delimiter closing the span opened
+// before from, skipping escapes and code spans, or -1.
+func mdFindClose(s string, from int, delim string) int {
+	for j := from; j < len(s); j++ {
+		switch {
+		case s[j] == '\\':
+			j++
+		case s[j] == '`':
+			end := strings.IndexByte(s[j+1:], '`')
+			if end < 0 {
+				return -1
+			}
+			j += end + 1
+		case delim == "*" && strings.HasPrefix(s[j:], "**"):
+			if end := mdFindClose(s, j+2, "**"); end > 0 {
+				j = end + 1
+				continue
+			}
+			return -1
+		case strings.HasPrefix(s[j:], delim):
+			return j
+		}
+	}
+
+	return -1
+}
+
+// mdParseLink parses "[text](url)" at the start of s and returns its length.
+// Parentheses in url must be balanced, as in ".../wiki/Go_(language)".
+func mdParseLink(s string) (text, href string, n int, ok bool) {
+	depth := 0
+	for j := 0; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if !strings.HasPrefix(s[j+1:], "(") {
+				return "", "", 0, false
+			}
+			end := mdFindParen(s[j+2:])
+			if end < 0 {
+				return "", "", 0, false
+			}
+			return s[1:j], strings.TrimSpace(s[j+2 : j+2+end]), j + 3 + end, true
+		}
+	}
+
+	return "", "", 0, false
+}
+
+// mdFindParen returns the index of the ")" closing the link destination s
+// starts, or -1.
+func mdFindParen(s string) int {
+	depth := 0
+	for j := 0; j < len(s); j++ {
+		switch s[j] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return j
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
+
+// mdSafeURL reports whether a link or image URL may be put into href or
+// src: http, https, mailto or relative. Anything else, javascript: and
+// data: in the first place, would run in EVA's web UI.
+func mdSafeURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+
+	return false
+}
+
+func mdIsPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// HTMLToMarkdown converts the HTML of an EVA text field to Markdown.
+// Elements outside the supported subset keep their text content.
+func HTMLToMarkdown(s string) string {
+	nodes, err := xhtml.ParseFragment(strings.NewReader(s), &xhtml.Node{
+		Type:     xhtml.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		// The tokenizer only fails on reader errors.
+		return s
+	}
+
+	root := &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+
+	return strings.Join(htmlBlocks(root), "\n\n")
+}
+
+// htmlBlocks converts the children of n to Markdown blocks.
+func htmlBlocks(n *xhtml.Node) []string {
+	var (
+		blocks []string
+		para   strings.Builder
+	)
+	flush := func() {
+		if p := htmlParagraph(para.String()); p != "" {
+			blocks = append(blocks, p)
+		}
+		para.Reset()
+	}
+
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		if ch.Type == xhtml.ElementNode && htmlIsBlock(ch.DataAtom) {
+			flush()
+			if block := htmlBlock(ch); block != "" {
+				blocks = append(blocks, block)
+			}
+			continue
+		}
+		htmlInline(&para, ch)
+	}
+	flush()
+
+	return blocks
+}
+
+// htmlParagraph trims the lines of inline Markdown and escapes the ones
+// that would otherwise start a block.
+func htmlParagraph(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		switch {
+		case mdBlockEscape.MatchString(line):
+			line = `\` + line
+		case mdOrderedText.MatchString(line):
+			line = mdOrderedText.ReplaceAllString(line, `$1\.$2`)
+		}
+		out = append(out, line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func htmlIsBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Li, atom.Pre, atom.Blockquote, atom.Hr,
+		atom.Table, atom.Thead, atom.Tbody, atom.Tfoot, atom.Tr:
+		return true
+	default:
+		return false
+	}
+}
+
+// htmlBlock converts a block element to Markdown.
+func htmlBlock(n *xhtml.Node) string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		var b strings.Builder
+		htmlInlineChildren(&b, n)
+		text := strings.Join(strings.Fields(b.String()), " ")
+		if text == "" {
+			return ""
+		}
+		return strings.Repeat("#", int(n.Data[1]-'0')) + " " + text
+
+	case atom.Hr:
+		return "---"
+
+	case atom.Pre:
+		lang := ""
+		if code := n.FirstChild; code != nil && code.DataAtom == atom.Code && code.NextSibling == nil {
+			for _, class := range strings.Fields(htmlAttr(code, "class")) {
+				if l, ok := strings.CutPrefix(class, "language-"); ok {
+					lang = l
+				}
+			}
+		}
+		return "```" + lang + "\n" + strings.TrimSuffix(htmlText(n), "\n") + "\n```"
+
+	case atom.Blockquote:
+		inner := strings.Join(htmlBlocks(n), "\n\n")
+		if inner == "" {
+			return ""
+		}
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return strings.Join(lines, "\n")
+
+	case atom.Ul, atom.Ol:
+		var items []string
+		for li := n.FirstChild; li != nil; li = li.NextSibling {
+			if li.Type != xhtml.ElementNode || li.DataAtom != atom.Li {
+				continue
+			}
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = strconv.Itoa(len(items)+1) + ". "
+			}
+			items = append(items, marker+mdIndentLines(htmlListItem(li), len(marker)))
+		}
+		return strings.Join(items, "\n")
+
+	default:
+		return strings.Join(htmlBlocks(n), "\n\n")
+	}
+}
+
+// htmlListItem converts the content of a <li>. Items with paragraphs are
+// loose (blank lines between their blocks), the rest are tight.
+func htmlListItem(li *xhtml.Node) string {
+	sep := "\n"
+	for ch := li.FirstChild; ch != nil; ch = ch.NextSibling {
+		if ch.DataAtom == atom.P {
+			sep = "\n\n"
+			break
+		}
+	}
+
+	return strings.Join(htmlBlocks(li), sep)
+}
+
+// mdIndentLines indents all lines of s but the first one.
+func mdIndentLines(s string, indent int) string {
+	lines := strings.Split(s, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = strings.Repeat(" ", indent) + lines[i]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// htmlInline writes n as inline Markdown to b.
+func htmlInline(b *strings.Builder, n *xhtml.Node) {
+	switch n.Type {
+	case xhtml.TextNode:
+		text := mdSpaces.ReplaceAllString(n.Data, " ")
+		if strings.HasPrefix(text, " ") && htmlEndsWithSpace(b) {
+			text = text[1:]
+		}
+		b.WriteString(mdEscape(text))
+		return
+	case xhtml.ElementNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		b.WriteString("\n")
+	case atom.Strong, atom.B:
+		htmlWrapInline(b, n, "**")
+	case atom.Em, atom.I:
+		htmlWrapInline(b, n, "*")
+	case atom.S, atom.Del, atom.Strike:
+		htmlWrapInline(b, n, "~~")
+	case atom.Code:
+		if text := htmlText(n); text != "" {
+			b.WriteString("`" + text + "`")
+		}
+	case atom.A:
+		var inner strings.Builder
+		htmlInlineChildren(&inner, n)
+		href := htmlAttr(n, "href")
+		if href == "" || !mdSafeURL(href) || strings.TrimSpace(inner.String()) == "" {
+			b.WriteString(inner.String())
+			return
+		}
+		b.WriteString("[" + strings.TrimSpace(inner.String()) + "](" + href + ")")
+	case atom.Img:
+		if src := htmlAttr(n, "src"); src != "" && mdSafeURL(src) {
+			b.WriteString("![" + htmlAttr(n, "alt") + "](" + src + ")")
+		}
+	case atom.Script, atom.Style:
+	default:
+		if htmlIsBlock(n.DataAtom) {
+			// A block nested in inline content: keep it on its own lines.
+			b.WriteString("\n")
+			htmlInlineChildren(b, n)
+			b.WriteString("\n")
+			return
+		}
+		htmlInlineChildren(b, n)
+	}
+}
+
+func htmlInlineChildren(b *strings.Builder, n *xhtml.Node) {
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		htmlInline(b, ch)
+	}
+}
+
+// htmlWrapInline writes the content of n between delim, keeping surrounding
+// spaces outside of the delimiters.
+func htmlWrapInline(b *strings.Builder, n *xhtml.Node, delim string) {
+	var inner strings.Builder
+	htmlInlineChildren(&inner, n)
+	s := inner.String()
+
+	core := strings.TrimSpace(s)
+	if core == "" {
+		b.WriteString(s)
+		return
+	}
+	if strings.HasPrefix(s, " ") && !htmlEndsWithSpace(b) {
+		b.WriteString(" ")
+	}
+	b.WriteString(delim + core + delim)
+	if strings.HasSuffix(s, " ") {
+		b.WriteString(" ")
+	}
+}
+
+func htmlEndsWithSpace(b *strings.Builder) bool {
+	s := b.String()
+	return s == "" || strings.HasSuffix(s, " ") || strings.HasSuffix(s, "\n")
+}
+
+// htmlText returns the raw text content of n.
+func htmlText(n *xhtml.Node) string {
+	if n.Type == xhtml.TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		if ch.DataAtom == atom.Br {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(htmlText(ch))
+	}
+
+	return b.String()
+}
+
+func htmlAttr(n *xhtml.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// mdEscape escapes the characters of text that would otherwise be read as
+// Markdown syntax.
+func mdEscape(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '*' || c == '`':
+			b.WriteByte('\\')
+		case c == '\\' && i+1 < len(text) && mdIsPunct(text[i+1]):
+			b.WriteByte('\\')
+		case c == '~' && i+1 < len(text) && text[i+1] == '~':
+			b.WriteByte('\\')
+		case c == '[' && strings.Contains(text[i:], "]("):
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,90 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{"paragraphs", "first\nline\n\nsecond", "<p>first<br>line</p><p>second</p>"},
+		{"heading", "## Plan *today*", "<h2>Plan <em>today</em></h2>"},
+		{"inline", "**b** ~~s~~ `a<b` [eva](https://eva.team) ![logo](/logo.png)",
+			`<p><strong>b</strong> <s>s</s> <code>a&lt;b</code> <a href="https://eva.team">eva</a> <img src="/logo.png" alt="logo"></p>`},
+		{"escapes", `2 \* 3 and snake_case`, "<p>2 * 3 and snake_case</p>"},
+		{"nested list", "- a\n  - b\n- c", "<ul><li>a<ul><li>b</li></ul></li><li>c</li></ul>"},
+		{"ordered loose item", "1. a\n\n   more\n2. b", "<ol><li><p>a</p><p>more</p></li><li>b</li></ol>"},
+		{"quote", "> quoted\n> text", "<blockquote><p>quoted<br>text</p></blockquote>"},
+		{"code block", "```go\nif a < b {\n}\n```", `<pre><code class="language-go">if a &lt; b {` + "\n" + `}</code></pre>`},
+		{"rule", "a\n\n---\n\nb", "<p>a</p><hr><p>b</p>"},
+		{"parentheses in url", "[Go](https://en.wikipedia.org/wiki/Go_(language)) (see)",
+			`<p><a href="https://en.wikipedia.org/wiki/Go_(language)">Go</a> (see)</p>`},
+		{"javascript link", "[link](javascript:alert(1))", "<p>link</p>"},
+		{"javascript link mixed case", "[link]( JavaScript:alert(1))", "<p>link</p>"},
+		{"data image", "![x](data:text/html;base64,PHNjcmlwdD4=)", "<p>x</p>"},
+		{"control char in scheme", "[link](java\tscript:alert(1))", "<p>link</p>"},
+		{"mailto and relative", "[me](mailto:a@b.c) [doc](/docs/x?a=1)",
+			`<p><a href="mailto:a@b.c">me</a> <a href="/docs/x?a=1">doc</a></p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MarkdownToHTML(tt.md))
+		})
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"editor html", "<p>Hello <b> world</b>!</p>\n<div>second&nbsp;line<br/>  third</div>",
+			"Hello **world**!\n\nsecond line\nthird"},
+		{"lists", "<ul><li><p>one</p></li><li>two<ol><li>x</li></ol></li></ul>", "- one\n- two\n  1. x"},
+		{"link and code", `<p>see <a href="https://eva.team">EVA <i>docs</i></a> and <code>a*b</code></p>`,
+			"see [EVA *docs*](https://eva.team) and `a*b`"},
+		{"block syntax in text", "<p># not a heading</p><p>1. not a list</p><p>- nor this</p>",
+			"\\# not a heading\n\n1\\. not a list\n\n\\- nor this"},
+		{"markdown chars in text", "<p>2 * 3 ~~ [x](y)</p>", `2 \* 3 \~~ \[x](y)`},
+		{"unsafe urls keep text", `<p><a href="javascript:alert(1)">link</a> <img src="data:image/png;base64,AA" alt="x"></p>`,
+			"link"},
+		{"unknown tags keep text", "<table><tr><td>a</td><td>b</td></tr></table><script>x()</script>", "ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HTMLToMarkdown(tt.html))
+		})
+	}
+}
+
+func TestMarkdown_RoundTrip(t *testing.T) {
+	md := "# Daily note\n\n" +
+		"Met with **Anna** about `eva_task_list`\nand *snake_case* naming.\n\n" +
+		"- [ ] review [PR](https://example.com/pr/1)\n- [x] deploy\n  - staging\n  - prod\n\n" +
+		"1. first\n\n   with details\n2. second\n\n" +
+		"> quote\n>\n> - in a list\n\n" +
+		"```sh\necho \"*raw*\"\n```\n\n" +
+		"---\n\n" +
+		"2 \\* 3 \\~~ ~~gone~~\n1\\. not a list"
+
+	html := MarkdownToHTML(md)
+
+	assert.Equal(t, md, HTMLToMarkdown(html))
+	assert.Equal(t, html, MarkdownToHTML(HTMLToMarkdown(html)))
+}
@@ -17,11 +17,23 @@ import (
 
 type Metrics interface {
 	RecordRequestDuration(status int, method, host, function string, duration float64)
+	// RecordRetry is called before every retry of a failed request; status is
+	// the HTTP status of the failed attempt, or 0 for a transport error.
+	RecordRetry(status int, method, host, function string)
+	// RecordRateLimitWait is called before every rate-limited request attempt
+	// with the time spent waiting for the client-side rate limiter.
+	RecordRateLimitWait(method, host, function string, wait float64)
+	// RecordCircuitState is called whenever the circuit breaker of a host
+	// changes state.
+	RecordCircuitState(host string, state CircuitState)
 }
 
 // PrometheusMetrics holds Prometheus metrics for the eva.team client
 type PrometheusMetrics struct {
 	RequestDuration prometheus.HistogramVec
+	Retries         prometheus.CounterVec
+	RateLimitWait   prometheus.HistogramVec
+	CircuitState    prometheus.GaugeVec
 }
 
 func NewPrometheusMetrics() *PrometheusMetrics {
@@ -34,19 +46,43 @@ func NewPrometheusMetrics() *PrometheusMetrics {
 			},
 			[]string{"status", "method", "host", "function"},
 		),
+		Retries: *prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "eva_client_request_retries_total",
+				Help: "Total number of retried eva.team API requests",
+			},
+			[]string{"status", "method", "host", "function"},
+		),
+		RateLimitWait: *prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "eva_client_rate_limit_wait_seconds",
+				Help:    "Time eva.team API requests waited for the client-side rate limiter in seconds",
+				Buckets: []float64{0, 0.01, 0.05, 0.1, 0.5, 1, 2, 5},
+			},
+			[]string{"method", "host", "function"},
+		),
+		CircuitState: *prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "eva_client_circuit_state",
+				Help: "Circuit breaker state of the eva.team API host: 0 closed, 1 open, 2 half-open",
+			},
+			[]string{"host"},
+		),
 	}
 }
 
 // Register registers metrics with Prometheus registry
 func (m *PrometheusMetrics) Register(registerer prometheus.Registerer) error {
-	if err := registerer.Register(&m.RequestDuration); err != nil {
-		// If already registered, that's fine
-		var alreadyRegisteredError prometheus.AlreadyRegisteredError
-		if errors.As(err, &alreadyRegisteredError) {
-			return nil
-		}
+	for _, c := range m.collectors() {
+		if err := registerer.Register(c); err != nil {
+			// If already registered, that's fine
+			var alreadyRegisteredError prometheus.AlreadyRegisteredError
+			if errors.As(err, &alreadyRegisteredError) {
+				continue
+			}
 
-		return err
+			return err
+		}
 	}
 
 	return nil
@@ -54,10 +90,34 @@ func (m *PrometheusMetrics) Register(registerer prometheus.Registerer) error {
 
 // Unregister unregisters metrics from Prometheus registry
 func (m *PrometheusMetrics) Unregister(registerer prometheus.Registerer) bool {
-	return registerer.Unregister(&m.RequestDuration)
+	ok := true
+	for _, c := range m.collectors() {
+		ok = registerer.Unregister(c) && ok
+	}
+
+	return ok
+}
+
+func (m *PrometheusMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{&m.RequestDuration, &m.Retries, &m.RateLimitWait, &m.CircuitState}
 }
 
 // RecordRequestDuration writes duration request with labels
 func (m *PrometheusMetrics) RecordRequestDuration(status int, method, host, function string, duration float64) {
 	m.RequestDuration.WithLabelValues(strconv.Itoa(status), method, host, function).Observe(duration)
 }
+
+// RecordRetry counts a retried request with labels
+func (m *PrometheusMetrics) RecordRetry(status int, method, host, function string) {
+	m.Retries.WithLabelValues(strconv.Itoa(status), method, host, function).Inc()
+}
+
+// RecordRateLimitWait observes the rate limiter wait time with labels
+func (m *PrometheusMetrics) RecordRateLimitWait(method, host, function string, wait float64) {
+	m.RateLimitWait.WithLabelValues(method, host, function).Observe(wait)
+}
+
+// RecordCircuitState sets the circuit breaker state gauge of a host
+func (m *PrometheusMetrics) RecordCircuitState(host string, state CircuitState) {
+	m.CircuitState.WithLabelValues(host).Set(float64(state))
+}
@@ -171,3 +171,75 @@ func TestPrometheusMetrics_RecordRequestDuration_DifferentLabels_CreatesDistinct
 }
 
 
+
+func TestPrometheusMetrics_RecordRetry_IncrementsCounter(t *testing.T) {
+	m := NewPrometheusMetrics()
+	reg := prometheus.NewRegistry()
+	err := m.Register(reg)
+	require.NoError(t, err)
+
+	m.RecordRetry(503, "CmfTask.list", "api.eva.team", "TasksList")
+	m.RecordRetry(503, "CmfTask.list", "api.eva.team", "TasksList")
+
+	gathered, err := reg.Gather()
+	require.NoError(t, err)
+
+	var found bool
+	for _, mf := range gathered {
+		if mf.GetName() != "eva_client_request_retries_total" {
+			continue
+		}
+		found = true
+		require.Len(t, mf.GetMetric(), 1)
+		assert.InDelta(t, 2, mf.GetMetric()[0].GetCounter().GetValue(), 0)
+	}
+	assert.True(t, found, "retries counter should be registered")
+}
+
+func TestPrometheusMetrics_RecordRateLimitWait_ObservesHistogram(t *testing.T) {
+	m := NewPrometheusMetrics()
+	reg := prometheus.NewRegistry()
+	err := m.Register(reg)
+	require.NoError(t, err)
+
+	m.RecordRateLimitWait("CmfTask.create", "api.eva.team", "TaskCreate", 0.25)
+
+	gathered, err := reg.Gather()
+	require.NoError(t, err)
+
+	var found bool
+	for _, mf := range gathered {
+		if mf.GetName() != "eva_client_rate_limit_wait_seconds" {
+			continue
+		}
+		found = true
+		require.Len(t, mf.GetMetric(), 1)
+		assert.Equal(t, uint64(1), mf.GetMetric()[0].GetHistogram().GetSampleCount())
+		assert.InDelta(t, 0.25, mf.GetMetric()[0].GetHistogram().GetSampleSum(), 0.0001)
+	}
+	assert.True(t, found, "rate limit wait histogram should be registered")
+}
+
+func TestPrometheusMetrics_RecordCircuitState_SetsGauge(t *testing.T) {
+	m := NewPrometheusMetrics()
+	reg := prometheus.NewRegistry()
+	err := m.Register(reg)
+	require.NoError(t, err)
+
+	m.RecordCircuitState("api.eva.team", CircuitOpen)
+	m.RecordCircuitState("api.eva.team", CircuitHalfOpen)
+
+	gathered, err := reg.Gather()
+	require.NoError(t, err)
+
+	var found bool
+	for _, mf := range gathered {
+		if mf.GetName() != "eva_client_circuit_state" {
+			continue
+		}
+		found = true
+		require.Len(t, mf.GetMetric(), 1)
+		assert.InDelta(t, float64(CircuitHalfOpen), mf.GetMetric()[0].GetGauge().GetValue(), 0)
+	}
+	assert.True(t, found, "circuit state gauge should be registered")
+}
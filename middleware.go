@@ -0,0 +1,149 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RPCResponse is the raw HTTP response of an RPC call. Non-2xx responses are
+// passed through the middleware chain as is and turned into an *APIError by
+// the client afterwards.
+type RPCResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// RoundTripFunc sends an RPC request (a single call or a batch) and returns
+// the raw response.
+type RoundTripFunc func(ctx context.Context, req *RPCRequest) (*RPCResponse, error)
+
+// Middleware wraps a RoundTripFunc to intercept every RPC call: it may mutate
+// the request (including RPCRequest.Header), inspect or replace the response,
+// call next several times or not at all.
+//
+// Example:
+//
+//	tenant := func(next evateamclient.RoundTripFunc) evateamclient.RoundTripFunc {
+//	  return func(ctx context.Context, req *evateamclient.RPCRequest) (*evateamclient.RPCResponse, error) {
+//	    if req.Header == nil {
+//	      req.Header = http.Header{}
+//	    }
+//	    req.Header.Set("X-Tenant", "acme")
+//	    return next(ctx, req)
+//	  }
+//	}
+//	client, err := evateamclient.NewClient(cfg, evateamclient.WithMiddleware(tenant))
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware appends middlewares to the chain. The first middleware is the
+// outermost one. User middlewares run before the built-in tracing, metrics and
+// logging middlewares, so those observe the request as it is finally sent;
+// retries happen below the whole chain.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, mw...)
+	}
+}
+
+type callerKey struct{}
+
+// withCaller stores the name of the client method issuing the request.
+func withCaller(ctx context.Context, fname string) context.Context {
+	return context.WithValue(ctx, callerKey{}, fname)
+}
+
+func callerFromContext(ctx context.Context) string {
+	fname, _ := ctx.Value(callerKey{}).(string)
+	return fname
+}
+
+type requestHeaderKey struct{}
+
+func withRequestHeader(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, requestHeaderKey{}, header)
+}
+
+func requestHeaderFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(requestHeaderKey{}).(http.Header)
+	return header
+}
+
+// handler composes the middleware chain around the HTTP transport.
+func (c *Client) handler() RoundTripFunc {
+	h := c.transport
+	if c.logger != nil && c.debug {
+		h = c.loggingMiddleware()(h)
+	}
+	if c.metrics != nil {
+		h = c.metricsMiddleware()(h)
+	}
+	if c.tracer != nil {
+		h = c.tracingMiddleware()(h)
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		h = c.middlewares[i](h)
+	}
+
+	return h
+}
+
+// metricsMiddleware records the duration of every RPC call.
+func (c *Client) metricsMiddleware() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
+			startTime := time.Now()
+			resp, err := next(ctx, req)
+
+			var statusCode int
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			c.metrics.RecordRequestDuration(statusCode, req.Method, c.baseURL.Host, callerFromContext(ctx), time.Since(startTime).Seconds())
+
+			return resp, err
+		}
+	}
+}
+
+// loggingMiddleware logs every RPC call with its request and response body.
+func (c *Client) loggingMiddleware() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
+			startTime := time.Now()
+			resp, err := next(ctx, req)
+
+			var (
+				statusCode    int
+				respBodyBytes []byte
+			)
+			if resp != nil {
+				statusCode = resp.StatusCode
+				respBodyBytes = resp.Body
+			}
+			reqBodyBytes, _ := json.Marshal(req)
+
+			c.logDebug(ctx, "Request",
+				"method", req.Method,
+				"url", c.requestURL(req),
+				"func", callerFromContext(ctx),
+				"requestBody", string(reqBodyBytes),
+				"responseBody", string(respBodyBytes),
+				"responseStatus", statusCode,
+				"duration", time.Since(startTime).String(),
+				"error", err,
+			)
+
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,211 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// durationRecorder captures RecordRequestDuration calls.
+type durationRecorder struct {
+	statuses  []int
+	methods   []string
+	functions []string
+}
+
+func (r *durationRecorder) RecordRequestDuration(status int, method, _, function string, _ float64) {
+	r.statuses = append(r.statuses, status)
+	r.methods = append(r.methods, method)
+	r.functions = append(r.functions, function)
+}
+
+func (r *durationRecorder) RecordRateLimitWait(string, string, string, float64) {}
+
+func (r *durationRecorder) RecordCircuitState(string, CircuitState) {}
+
+func (r *durationRecorder) RecordRetry(int, string, string, string) {}
+
+// debugRecorder captures debug log messages.
+type debugRecorder struct {
+	mu   sync.Mutex
+	msgs []string
+	args [][]any
+}
+
+func (l *debugRecorder) Debug(_ context.Context, msg string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, msg)
+	l.args = append(l.args, args)
+}
+func (l *debugRecorder) Info(context.Context, string, ...any)  {}
+func (l *debugRecorder) Warn(context.Context, string, ...any)  {}
+func (l *debugRecorder) Error(context.Context, string, ...any) {}
+
+func recordingMiddleware(name string, trace *[]string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
+			*trace = append(*trace, name+">")
+			resp, err := next(ctx, req)
+			*trace = append(*trace, "<"+name)
+			return resp, err
+		}
+	}
+}
+
+func TestWithMiddleware_RunsInOrder(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, taskGetOKBody)
+
+	var trace []string
+	WithMiddleware(recordingMiddleware("a", &trace), recordingMiddleware("b", &trace))(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a>", "b>", "<b", "<a"}, trace)
+}
+
+func TestWithMiddleware_MutatesRequest(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, taskGetOKBody)
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return strings.Contains(string(body), `"callid":"fixed"`)
+	}
+
+	WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
+			req.CallID = "fixed"
+			return next(ctx, req)
+		}
+	})(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.NoError(t, err)
+}
+
+func TestWithMiddleware_ShortCircuit(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+
+	WithMiddleware(func(RoundTripFunc) RoundTripFunc {
+		return func(context.Context, *RPCRequest) (*RPCResponse, error) {
+			return &RPCResponse{StatusCode: http.StatusServiceUnavailable, Body: []byte("injected")}, nil
+		}
+	})(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+	assert.Equal(t, 0, mockHTTP.calls)
+}
+
+func TestWithMiddleware_SeesRawErrorResponse(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusUnauthorized, "expired")
+
+	var seen *RPCResponse
+	WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
+			resp, err := next(ctx, req)
+			seen = resp
+			return resp, err
+		}
+	})(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.ErrorIs(t, err, ErrUnauthorized)
+	require.NotNil(t, seen)
+	assert.Equal(t, http.StatusUnauthorized, seen.StatusCode)
+	assert.Equal(t, "expired", string(seen.Body))
+}
+
+func TestWithMiddleware_HeaderIsSent(t *testing.T) {
+	var tenant string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = r.Header.Get("X-Tenant")
+		_, _ = w.Write([]byte(taskGetOKBody))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(&Config{BaseURL: srv.URL, APIToken: "test-token"},
+		WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
+				req.Header = http.Header{"X-Tenant": {"acme"}}
+				return next(ctx, req)
+			}
+		}),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Task(testCtx, "TASK-1", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+}
+
+func TestClient_MetricsMiddleware_RecordsDuration(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusNotFound, "missing")
+	metrics := &durationRecorder{}
+	WithMetrics(metrics)(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.Error(t, err)
+	assert.Equal(t, []int{http.StatusNotFound}, metrics.statuses)
+	assert.Equal(t, []string{"CmfTask.get"}, metrics.methods)
+	assert.Contains(t, metrics.functions[0], "Task")
+}
+
+func TestClient_MetricsMiddleware_TaskUpdateKeepsFunctionLabel(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfTask:123","epic_id":""}}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":"CmfTask:123"}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfTask:123"}}`),
+	}
+	metrics := &durationRecorder{}
+	WithMetrics(metrics)(client)
+
+	_, err := client.TaskUpdate(testCtx, "CmfTask:123", map[string]any{"name": "x"})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"CmfTask.get", "CmfTask.update", "CmfTask.get"}, metrics.methods)
+	assert.True(t, strings.HasSuffix(metrics.functions[1], ".TaskUpdate"), metrics.functions[1])
+}
+
+func TestClient_LoggingMiddleware_LogsOnlyInDebug(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, taskGetOKBody)
+	logger := &debugRecorder{}
+	WithLogger(logger)(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+	assert.Empty(t, logger.msgs)
+
+	WithDebug(true)(client)
+	_, _, err = client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Request"}, logger.msgs)
+	assert.Contains(t, logger.args[0], "CmfTask.get")
+	assert.Contains(t, logger.args[0], taskGetOKBody)
+}
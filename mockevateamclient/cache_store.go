@@ -0,0 +1,200 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mockevateamclient
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// CacheStore is an autogenerated mock type for the CacheStore type
+type CacheStore struct {
+	mock.Mock
+}
+
+type CacheStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *CacheStore) EXPECT() *CacheStore_Expecter {
+	return &CacheStore_Expecter{mock: &_m.Mock}
+}
+
+// DeletePrefix provides a mock function with given fields: ctx, prefix
+func (_m *CacheStore) DeletePrefix(ctx context.Context, prefix string) error {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePrefix")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CacheStore_DeletePrefix_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePrefix'
+type CacheStore_DeletePrefix_Call struct {
+	*mock.Call
+}
+
+// DeletePrefix is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+func (_e *CacheStore_Expecter) DeletePrefix(ctx interface{}, prefix interface{}) *CacheStore_DeletePrefix_Call {
+	return &CacheStore_DeletePrefix_Call{Call: _e.mock.On("DeletePrefix", ctx, prefix)}
+}
+
+func (_c *CacheStore_DeletePrefix_Call) Run(run func(ctx context.Context, prefix string)) *CacheStore_DeletePrefix_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *CacheStore_DeletePrefix_Call) Return(_a0 error) *CacheStore_DeletePrefix_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *CacheStore_DeletePrefix_Call) RunAndReturn(run func(context.Context, string) error) *CacheStore_DeletePrefix_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, key
+func (_m *CacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 []byte
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, bool, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, key)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CacheStore_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type CacheStore_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *CacheStore_Expecter) Get(ctx interface{}, key interface{}) *CacheStore_Get_Call {
+	return &CacheStore_Get_Call{Call: _e.mock.On("Get", ctx, key)}
+}
+
+func (_c *CacheStore_Get_Call) Run(run func(ctx context.Context, key string)) *CacheStore_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *CacheStore_Get_Call) Return(_a0 []byte, _a1 bool, _a2 error) *CacheStore_Get_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *CacheStore_Get_Call) RunAndReturn(run func(context.Context, string) ([]byte, bool, error)) *CacheStore_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function with given fields: ctx, key, value, ttl
+func (_m *CacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, value, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, time.Duration) error); ok {
+		r0 = rf(ctx, key, value, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CacheStore_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type CacheStore_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - value []byte
+//   - ttl time.Duration
+func (_e *CacheStore_Expecter) Set(ctx interface{}, key interface{}, value interface{}, ttl interface{}) *CacheStore_Set_Call {
+	return &CacheStore_Set_Call{Call: _e.mock.On("Set", ctx, key, value, ttl)}
+}
+
+func (_c *CacheStore_Set_Call) Run(run func(ctx context.Context, key string, value []byte, ttl time.Duration)) *CacheStore_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]byte), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *CacheStore_Set_Call) Return(_a0 error) *CacheStore_Set_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *CacheStore_Set_Call) RunAndReturn(run func(context.Context, string, []byte, time.Duration) error) *CacheStore_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewCacheStore creates a new instance of CacheStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCacheStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CacheStore {
+	mock := &CacheStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
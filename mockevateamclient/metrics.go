@@ -2,7 +2,10 @@
 
 package mockevateamclient
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	evateamclient "github.com/raoptimus/evateamclient.go"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Metrics is an autogenerated mock type for the Metrics type
 type Metrics struct {
@@ -17,6 +20,76 @@ func (_m *Metrics) EXPECT() *Metrics_Expecter {
 	return &Metrics_Expecter{mock: &_m.Mock}
 }
 
+// RecordCircuitState provides a mock function with given fields: host, state
+func (_m *Metrics) RecordCircuitState(host string, state evateamclient.CircuitState) {
+	_m.Called(host, state)
+}
+
+// Metrics_RecordCircuitState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordCircuitState'
+type Metrics_RecordCircuitState_Call struct {
+	*mock.Call
+}
+
+// RecordCircuitState is a helper method to define mock.On call
+//   - host string
+//   - state evateamclient.CircuitState
+func (_e *Metrics_Expecter) RecordCircuitState(host interface{}, state interface{}) *Metrics_RecordCircuitState_Call {
+	return &Metrics_RecordCircuitState_Call{Call: _e.mock.On("RecordCircuitState", host, state)}
+}
+
+func (_c *Metrics_RecordCircuitState_Call) Run(run func(host string, state evateamclient.CircuitState)) *Metrics_RecordCircuitState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(evateamclient.CircuitState))
+	})
+	return _c
+}
+
+func (_c *Metrics_RecordCircuitState_Call) Return() *Metrics_RecordCircuitState_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Metrics_RecordCircuitState_Call) RunAndReturn(run func(string, evateamclient.CircuitState)) *Metrics_RecordCircuitState_Call {
+	_c.Run(run)
+	return _c
+}
+
+// RecordRateLimitWait provides a mock function with given fields: method, host, function, wait
+func (_m *Metrics) RecordRateLimitWait(method string, host string, function string, wait float64) {
+	_m.Called(method, host, function, wait)
+}
+
+// Metrics_RecordRateLimitWait_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordRateLimitWait'
+type Metrics_RecordRateLimitWait_Call struct {
+	*mock.Call
+}
+
+// RecordRateLimitWait is a helper method to define mock.On call
+//   - method string
+//   - host string
+//   - function string
+//   - wait float64
+func (_e *Metrics_Expecter) RecordRateLimitWait(method interface{}, host interface{}, function interface{}, wait interface{}) *Metrics_RecordRateLimitWait_Call {
+	return &Metrics_RecordRateLimitWait_Call{Call: _e.mock.On("RecordRateLimitWait", method, host, function, wait)}
+}
+
+func (_c *Metrics_RecordRateLimitWait_Call) Run(run func(method string, host string, function string, wait float64)) *Metrics_RecordRateLimitWait_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(float64))
+	})
+	return _c
+}
+
+func (_c *Metrics_RecordRateLimitWait_Call) Return() *Metrics_RecordRateLimitWait_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Metrics_RecordRateLimitWait_Call) RunAndReturn(run func(string, string, string, float64)) *Metrics_RecordRateLimitWait_Call {
+	_c.Run(run)
+	return _c
+}
+
 // RecordRequestDuration provides a mock function with given fields: status, method, host, function, duration
 func (_m *Metrics) RecordRequestDuration(status int, method string, host string, function string, duration float64) {
 	_m.Called(status, method, host, function, duration)
@@ -54,6 +127,42 @@ func (_c *Metrics_RecordRequestDuration_Call) RunAndReturn(run func(int, string,
 	return _c
 }
 
+// RecordRetry provides a mock function with given fields: status, method, host, function
+func (_m *Metrics) RecordRetry(status int, method string, host string, function string) {
+	_m.Called(status, method, host, function)
+}
+
+// Metrics_RecordRetry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordRetry'
+type Metrics_RecordRetry_Call struct {
+	*mock.Call
+}
+
+// RecordRetry is a helper method to define mock.On call
+//   - status int
+//   - method string
+//   - host string
+//   - function string
+func (_e *Metrics_Expecter) RecordRetry(status interface{}, method interface{}, host interface{}, function interface{}) *Metrics_RecordRetry_Call {
+	return &Metrics_RecordRetry_Call{Call: _e.mock.On("RecordRetry", status, method, host, function)}
+}
+
+func (_c *Metrics_RecordRetry_Call) Run(run func(status int, method string, host string, function string)) *Metrics_RecordRetry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Metrics_RecordRetry_Call) Return() *Metrics_RecordRetry_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Metrics_RecordRetry_Call) RunAndReturn(run func(int, string, string, string)) *Metrics_RecordRetry_Call {
+	_c.Run(run)
+	return _c
+}
+
 // NewMetrics creates a new instance of Metrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMetrics(t interface {
@@ -0,0 +1,96 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mockevateamclient
+
+import (
+	context "context"
+
+	evateamclient "github.com/raoptimus/evateamclient.go"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoundTripFunc is an autogenerated mock type for the RoundTripFunc type
+type RoundTripFunc struct {
+	mock.Mock
+}
+
+type RoundTripFunc_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RoundTripFunc) EXPECT() *RoundTripFunc_Expecter {
+	return &RoundTripFunc_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx, req
+func (_m *RoundTripFunc) Execute(ctx context.Context, req *evateamclient.RPCRequest) (*evateamclient.RPCResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 *evateamclient.RPCResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *evateamclient.RPCRequest) (*evateamclient.RPCResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *evateamclient.RPCRequest) *evateamclient.RPCResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*evateamclient.RPCResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *evateamclient.RPCRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoundTripFunc_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type RoundTripFunc_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *evateamclient.RPCRequest
+func (_e *RoundTripFunc_Expecter) Execute(ctx interface{}, req interface{}) *RoundTripFunc_Execute_Call {
+	return &RoundTripFunc_Execute_Call{Call: _e.mock.On("Execute", ctx, req)}
+}
+
+func (_c *RoundTripFunc_Execute_Call) Run(run func(ctx context.Context, req *evateamclient.RPCRequest)) *RoundTripFunc_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*evateamclient.RPCRequest))
+	})
+	return _c
+}
+
+func (_c *RoundTripFunc_Execute_Call) Return(_a0 *evateamclient.RPCResponse, _a1 error) *RoundTripFunc_Execute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoundTripFunc_Execute_Call) RunAndReturn(run func(context.Context, *evateamclient.RPCRequest) (*evateamclient.RPCResponse, error)) *RoundTripFunc_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRoundTripFunc creates a new instance of RoundTripFunc. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoundTripFunc(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoundTripFunc {
+	mock := &RoundTripFunc{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,39 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package models
+
+import "time"
+
+// Attachment represents a file attached to a task, comment or document.
+type Attachment struct {
+	ID           string    `json:"id"`
+	ClassName    string    `json:"class_name,omitempty"`
+	Code         string    `json:"code,omitempty"`
+	Name         string    `json:"name,omitempty"`      // file name, example: screenshot.png
+	ParentID     string    `json:"parent_id,omitempty"` // example: CmfTask:06506d44-c545-11f0-b6f8-eeb7fce6ef9e
+	URL          string    `json:"url,omitempty"`       // download URL, absolute or relative to the server
+	Size         int64     `json:"size,omitempty"`      // bytes
+	MimeType     string    `json:"mimetype,omitempty"`
+	CmfOwnerID   string    `json:"cmf_owner_id,omitempty"`
+	CmfCreatedAt time.Time `json:"cmf_created_at,omitempty"`
+}
+
+// AttachmentResponse for CmfAttachment.get (single attachment).
+type AttachmentResponse struct {
+	JSONRPC string     `json:"jsonrpc,omitempty"`
+	Result  Attachment `json:"result,omitempty"`
+	Meta    Meta       `json:"meta,omitempty"`
+}
+
+// AttachmentListResponse for CmfAttachment.list.
+type AttachmentListResponse struct {
+	JSONRPC string       `json:"jsonrpc,omitempty"`
+	Result  []Attachment `json:"result,omitempty"`
+	Meta    Meta         `json:"meta,omitempty"`
+}
@@ -0,0 +1,43 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package models
+
+import "time"
+
+// Audit represents a change record from CmfAudit.list: who changed which
+// field of which object, from what to what and when. Action, ProjectID,
+// FieldName, OldValue and NewValue use field names that are not in the OAS
+// and are unverified; they stay empty if the server names them otherwise.
+type Audit struct {
+	ID           string     `json:"id"`
+	ClassName    string     `json:"class_name,omitempty"`
+	Code         string     `json:"code,omitempty"`
+	Action       string     `json:"action,omitempty"`       // create, update, delete
+	ParentID     string     `json:"parent_id,omitempty"`    // changed object, example: CmfTask:uuid
+	ProjectID    string     `json:"project_id,omitempty"`   // project context
+	FieldName    string     `json:"field_name,omitempty"`   // changed field, empty for create/delete
+	OldValue     any        `json:"old_value,omitempty"`    // value before the change
+	NewValue     any        `json:"new_value,omitempty"`    // value after the change
+	CmfOwnerID   string     `json:"cmf_owner_id,omitempty"` // person who made the change
+	CmfCreatedAt *time.Time `json:"cmf_created_at,omitempty"`
+}
+
+// AuditResponse for CmfAudit.get.
+type AuditResponse struct {
+	JSONRPC string `json:"jsonrpc,omitempty"`
+	Result  Audit  `json:"result,omitempty"`
+	Meta    Meta   `json:"meta,omitempty"`
+}
+
+// AuditListResponse for CmfAudit.list.
+type AuditListResponse struct {
+	JSONRPC string  `json:"jsonrpc,omitempty"`
+	Result  []Audit `json:"result,omitempty"`
+	Meta    Meta    `json:"meta,omitempty"`
+}
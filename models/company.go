@@ -0,0 +1,40 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package models
+
+import "time"
+
+// Company represents a company (contractor, customer) in EVA system
+type Company struct {
+	ID            string     `json:"id"`
+	ClassName     string     `json:"class_name,omitempty"`
+	Code          string     `json:"code,omitempty"`
+	Name          string     `json:"name"`
+	INN           string     `json:"inn,omitempty"`  // taxpayer ID, example: 7701234567
+	KPP           string     `json:"kpp,omitempty"`  // tax registration reason code
+	OGRN          string     `json:"ogrn,omitempty"` // primary state registration number
+	CmfOwnerID    string     `json:"cmf_owner_id,omitempty"`
+	CmfCreatedAt  *time.Time `json:"cmf_created_at,omitempty"`
+	CmfModifiedAt *time.Time `json:"cmf_modified_at,omitempty"`
+	CmfDeleted    bool       `json:"cmf_deleted,omitempty"`
+}
+
+// CompanyResponse for CmfCompany.get.
+type CompanyResponse struct {
+	JSONRPC string  `json:"jsonrpc,omitempty"`
+	Result  Company `json:"result,omitempty"`
+	Meta    Meta    `json:"meta,omitempty"`
+}
+
+// CompanyListResponse for CmfCompany.list.
+type CompanyListResponse struct {
+	JSONRPC string    `json:"jsonrpc,omitempty"`
+	Result  []Company `json:"result,omitempty"`
+	Meta    Meta      `json:"meta,omitempty"`
+}
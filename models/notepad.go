@@ -0,0 +1,39 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package models
+
+import "time"
+
+// Notepad represents a personal note in EVA system
+type Notepad struct {
+	ID            string     `json:"id"`
+	ClassName     string     `json:"class_name,omitempty"`
+	Code          string     `json:"code,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	Text          string     `json:"text,omitempty"`      // HTML
+	ParentID      *string    `json:"parent_id,omitempty"` // object the note is attached to, if any
+	CmfOwnerID    string     `json:"cmf_owner_id,omitempty"`
+	CmfCreatedAt  *time.Time `json:"cmf_created_at,omitempty"`
+	CmfModifiedAt *time.Time `json:"cmf_modified_at,omitempty"`
+	CmfDeleted    bool       `json:"cmf_deleted,omitempty"`
+}
+
+// NotepadResponse for CmfNotepad.get.
+type NotepadResponse struct {
+	JSONRPC string  `json:"jsonrpc,omitempty"`
+	Result  Notepad `json:"result,omitempty"`
+	Meta    Meta    `json:"meta,omitempty"`
+}
+
+// NotepadListResponse for CmfNotepad.list.
+type NotepadListResponse struct {
+	JSONRPC string    `json:"jsonrpc,omitempty"`
+	Result  []Notepad `json:"result,omitempty"`
+	Meta    Meta      `json:"meta,omitempty"`
+}
@@ -0,0 +1,372 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	encjson "encoding/json"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+// Notepad field constants for type-safe queries
+const (
+	NotepadFieldID            = "id"
+	NotepadFieldClassName     = "class_name"
+	NotepadFieldCode          = "code"
+	NotepadFieldName          = "name"
+	NotepadFieldText          = "text"      // HTML, see MarkdownToHTML/HTMLToMarkdown
+	NotepadFieldParentID      = "parent_id" // object the note is attached to
+	NotepadFieldParent        = "parent"    // parent for create
+	NotepadFieldCmfOwnerID    = "cmf_owner_id"
+	NotepadFieldCmfCreatedAt  = "cmf_created_at"
+	NotepadFieldCmfModifiedAt = "cmf_modified_at"
+	NotepadFieldCmfDeleted    = "cmf_deleted"
+)
+
+var (
+	// DefaultNotepadFields - standard projection for single notepad queries
+	DefaultNotepadFields = []string{
+		NotepadFieldID,
+		NotepadFieldClassName,
+		NotepadFieldCode,
+		NotepadFieldName,
+		NotepadFieldText,
+		NotepadFieldParentID,
+		NotepadFieldCmfOwnerID,
+		NotepadFieldCmfCreatedAt,
+		NotepadFieldCmfModifiedAt,
+	}
+
+	// DefaultNotepadListFields - optimized for LIST queries (no text)
+	DefaultNotepadListFields = []string{
+		NotepadFieldID,
+		NotepadFieldCode,
+		NotepadFieldName,
+		NotepadFieldParentID,
+		NotepadFieldCmfCreatedAt,
+		NotepadFieldCmfModifiedAt,
+	}
+)
+
+// Notepad retrieves a single notepad by ID or code
+// Example:
+//
+//	note, meta, err := client.Notepad(ctx, "NXX-000001", nil)
+//	md := evateamclient.HTMLToMarkdown(note.Text)
+func (c *Client) Notepad(
+	ctx context.Context,
+	idOrCode string,
+	fields []string,
+) (*models.Notepad, *models.Meta, error) {
+	field := NotepadFieldCode
+	if strings.Contains(idOrCode, ":") {
+		field = NotepadFieldID
+	}
+
+	qb := NewQueryBuilder().
+		Select(fields...).
+		From(EntityNotepad).
+		Where(sq.Eq{field: idOrCode}).
+		Limit(1)
+
+	return c.NotepadQuery(ctx, qb)
+}
+
+// NotepadQuery executes query using QueryBuilder
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  Select("id", "text").
+//	  From(evateamclient.EntityNotepad).
+//	  Where(sq.Eq{"parent_id": "CmfTask:uuid"})
+//	note, meta, err := client.NotepadQuery(ctx, qb)
+func (c *Client) NotepadQuery(ctx context.Context, qb *QueryBuilder) (*models.Notepad, *models.Meta, error) {
+	kwargs, err := qb.ToKwargs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, hasFields := kwargs["fields"]; !hasFields {
+		kwargs["fields"] = DefaultNotepadFields
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfNotepad.get",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp models.NotepadResponse
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get notepad")
+	}
+
+	return &resp.Result, &resp.Meta, nil
+}
+
+// NotepadsList retrieves list using QueryBuilder
+// Example:
+//
+//	qb := evateamclient.NewQueryBuilder().
+//	  From(evateamclient.EntityNotepad).
+//	  OrderBy("-cmf_created_at").
+//	  Limit(10)
+//	notes, meta, err := client.NotepadsList(ctx, qb)
+func (c *Client) NotepadsList(ctx context.Context, qb *QueryBuilder) ([]models.Notepad, *models.Meta, error) {
+	kwargs, err := qb.From(EntityNotepad).ToKwargs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, hasFields := kwargs["fields"]; !hasFields {
+		kwargs["fields"] = DefaultNotepadListFields
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfNotepad.list",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp models.NotepadListResponse
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get notepads")
+	}
+
+	return resp.Result, &resp.Meta, nil
+}
+
+// NotepadCount counts notepads using QueryBuilder
+// Example:
+//
+//	count, err := client.NotepadCount(ctx, evateamclient.NewQueryBuilder())
+func (c *Client) NotepadCount(ctx context.Context, qb *QueryBuilder) (int, error) {
+	count, err := Count(ctx, c, qb.From(EntityNotepad))
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get notepad count")
+	}
+
+	return count, nil
+}
+
+// ErrNotTokenOwner is returned by NotepadAppendDay when the person is not
+// the owner of the API token, who owns the notes the token creates.
+var ErrNotTokenOwner = errors.New("person is not the API token owner")
+
+// DayNotepadQuery returns the query of the notepads of the person not
+// attached to any object and created on the calendar day of day, in its
+// location, the oldest first. The first of them is the note of that day.
+func DayNotepadQuery(personID string, day time.Time) *QueryBuilder {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	to := from.AddDate(0, 0, 1)
+
+	return NewQueryBuilder().
+		From(EntityNotepad).
+		Where(sq.Eq{NotepadFieldParentID: nil}).
+		Where(sq.Eq{NotepadFieldCmfOwnerID: personID}).
+		Where(sq.GtOrEq{NotepadFieldCmfCreatedAt: from.UTC()}).
+		Where(sq.Lt{NotepadFieldCmfCreatedAt: to.UTC()}).
+		OrderBy(NotepadFieldCmfCreatedAt, NotepadFieldID)
+}
+
+// NotepadAppendDay appends markdown to the note of the person for the
+// calendar day of day, creating the note if there is none yet. personID must
+// be the owner of the API token: EVA creates the note on their behalf, and the
+// filter keeps the append away from notes of others the token can see. The
+// API has no way to ask who the token owner is, so the owner of a created
+// note is checked instead: when it is not personID, the note is deleted and
+// ErrNotTokenOwner returned, as the next append would not find it.
+// The call is idempotent: it changes nothing and returns appended=false
+// when the note already contains the lines of markdown, so a retried append
+// does not duplicate the text. It is not atomic, though: two concurrent
+// first appends of a day may each find no note and create one. The later
+// note is then ignored by the following appends.
+// Example:
+//
+//	note, appended, err := client.NotepadAppendDay(ctx, "CmfPerson:uuid", time.Now(), "- [x] deployed v1.2")
+func (c *Client) NotepadAppendDay(
+	ctx context.Context,
+	personID string,
+	day time.Time,
+	markdown string,
+) (note *models.Notepad, appended bool, err error) {
+	if personID == "" {
+		return nil, false, errors.New("personID is required")
+	}
+
+	block := HTMLToMarkdown(MarkdownToHTML(markdown))
+	if block == "" {
+		return nil, false, errors.New("markdown is required")
+	}
+
+	notes, _, err := c.NotepadsList(ctx, DayNotepadQuery(personID, day).Select(DefaultNotepadFields...).Limit(1))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(notes) == 0 {
+		note, err = c.NotepadCreate(ctx, &NotepadCreateParams{Text: MarkdownToHTML(block)})
+		if err != nil {
+			return nil, false, err
+		}
+		if note.CmfOwnerID != personID {
+			err = errors.Wrapf(ErrNotTokenOwner, "note %s is owned by %q, not %s", note.ID, note.CmfOwnerID, personID)
+			if derr := c.NotepadDelete(ctx, note.ID); derr != nil {
+				return nil, false, errors.WithMessagef(err, "failed to delete the note: %v", derr)
+			}
+			return nil, false, err
+		}
+		return note, true, nil
+	}
+
+	note = &notes[0]
+	if strings.Contains("\n"+HTMLToMarkdown(note.Text)+"\n", "\n"+block+"\n") {
+		return note, false, nil
+	}
+
+	// Append HTML rather than re-render the whole note, so that formatting
+	// outside of the Markdown subset survives.
+	note, err = c.NotepadUpdate(ctx, note.ID, map[string]any{
+		NotepadFieldText: note.Text + MarkdownToHTML(block),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return note, true, nil
+}
+
+// CRUD Operations
+
+// NotepadCreateParams contains parameters for creating a new notepad
+type NotepadCreateParams struct {
+	Text     string `json:"text"`             // HTML, see MarkdownToHTML
+	ParentID string `json:"parent,omitempty"` // object to attach the note to
+}
+
+// NotepadCreate creates a new notepad
+// Example:
+//
+//	note, err := client.NotepadCreate(ctx, &evateamclient.NotepadCreateParams{
+//	  Text: evateamclient.MarkdownToHTML("**Standup**\n- done: review"),
+//	})
+func (c *Client) NotepadCreate(
+	ctx context.Context,
+	params *NotepadCreateParams,
+) (*models.Notepad, error) {
+	if params == nil || params.Text == "" {
+		return nil, errors.New("text is required")
+	}
+
+	kwargs := map[string]any{
+		NotepadFieldText: params.Text,
+	}
+	if params.ParentID != "" {
+		kwargs[NotepadFieldParent] = params.ParentID
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfNotepad.create",
+		CallID:  newCallID(),
+		Kwargs:  kwargs,
+	}
+
+	var resp struct {
+		JSONRPC string             `json:"jsonrpc"`
+		Result  encjson.RawMessage `json:"result"`
+	}
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+
+	return parseWriteResult(ctx, resp.Result, "CmfNotepad.create", c.notepadByID, notepadHasEmptyID)
+}
+
+// notepadByID fetches a notepad by ID or code, for the two-phase
+// create/update follow-up `.get` when CmfNotepad.create/update returns a bare
+// string.
+func (c *Client) notepadByID(ctx context.Context, idOrCode string) (*models.Notepad, error) {
+	note, _, err := c.Notepad(ctx, idOrCode, DefaultNotepadFields)
+	return note, err
+}
+
+func notepadHasEmptyID(note *models.Notepad) bool {
+	return note == nil || note.ID == ""
+}
+
+// NotepadUpdate updates an existing notepad. The OAS allows text only.
+// Example:
+//
+//	updates := map[string]any{
+//	  "text": evateamclient.MarkdownToHTML("# Today\n- [x] done"),
+//	}
+//	note, err := client.NotepadUpdate(ctx, "CmfNotepad:uuid", updates)
+func (c *Client) NotepadUpdate(
+	ctx context.Context,
+	notepadID string,
+	updates map[string]any,
+) (*models.Notepad, error) {
+	if notepadID == "" {
+		return nil, errors.New("notepadID is required")
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfNotepad.update",
+		CallID:  newCallID(),
+		Args:    []any{notepadID},
+		Kwargs:  updates,
+	}
+
+	var resp struct {
+		JSONRPC string             `json:"jsonrpc"`
+		Result  encjson.RawMessage `json:"result"`
+	}
+	if err := c.doRequest(ctx, reqBody, &resp); err != nil {
+		return nil, err
+	}
+
+	return parseWriteResult(ctx, resp.Result, "CmfNotepad.update", c.notepadByID, notepadHasEmptyID)
+}
+
+// NotepadDelete deletes a notepad by ID. The OAS omits CmfNotepad.delete;
+// it is the generic CMF delete every model exposes.
+// Example:
+//
+//	err := client.NotepadDelete(ctx, "CmfNotepad:uuid")
+func (c *Client) NotepadDelete(
+	ctx context.Context,
+	notepadID string,
+) error {
+	if notepadID == "" {
+		return errors.New("notepadID is required")
+	}
+
+	reqBody := &RPCRequest{
+		JSONRPC: "2.2",
+		Method:  "CmfNotepad.delete",
+		CallID:  newCallID(),
+		Args:    []any{notepadID},
+	}
+
+	var resp struct {
+		JSONRPC string `json:"jsonrpc"`
+		Result  any    `json:"result"`
+	}
+
+	return c.doRequest(ctx, reqBody, &resp)
+}
@@ -0,0 +1,200 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	encjson "encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notepadRequest is the part of a request body the notepad tests check.
+type notepadRequest struct {
+	Method string         `json:"method"`
+	Args   []any          `json:"args"`
+	Kwargs map[string]any `json:"kwargs"`
+}
+
+// recordNotepadRequests makes mockHTTP record the requests it receives.
+func recordNotepadRequests(t *testing.T, mockHTTP *sequentialMockHTTPClient) *[]notepadRequest {
+	t.Helper()
+
+	var reqs []notepadRequest
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		var r notepadRequest
+		if err := encjson.Unmarshal(body, &r); !assert.NoError(t, err) {
+			return false
+		}
+		reqs = append(reqs, r)
+		return true
+	}
+
+	return &reqs
+}
+
+func TestClient_Notepad_ByID_ReturnsNotepad(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": {
+		"id": "CmfNotepad:1", "code": "NXX-000001", "text": "<p>note</p>", "parent_id": null
+	}}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfNotepad.get")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"filter":["id","==","CmfNotepad:1"]`)
+	}
+
+	note, _, err := client.Notepad(testCtx, "CmfNotepad:1", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "<p>note</p>", note.Text)
+	assert.Nil(t, note.ParentID)
+}
+
+func TestClient_NotepadCreate_SendsTextAndParent(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1","text":"<p>x</p>"}}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfNotepad.create")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		var r notepadRequest
+		if err := encjson.Unmarshal(body, &r); !assert.NoError(t, err) {
+			return false
+		}
+		return assert.Equal(t, map[string]any{"parent": "CmfTask:1", "text": "<p>x</p>"}, r.Kwargs)
+	}
+
+	note, err := client.NotepadCreate(testCtx, &NotepadCreateParams{Text: "<p>x</p>", ParentID: "CmfTask:1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "CmfNotepad:1", note.ID)
+}
+
+func TestClient_NotepadCreate_EmptyText_ReturnsErrorWithoutRequest(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.err = errors.New("must not be called")
+
+	note, err := client.NotepadCreate(testCtx, &NotepadCreateParams{})
+
+	require.Error(t, err)
+	assert.Nil(t, note)
+	assert.Equal(t, 0, mockHTTP.calls, "validation must fail before any HTTP request")
+}
+
+func TestClient_NotepadUpdate_ResultIsIDString_FetchesUpdatedNotepad(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":"CmfNotepad:1"}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1","text":"<p>new</p>"}}`),
+	}
+	reqs := recordNotepadRequests(t, mockHTTP)
+
+	note, err := client.NotepadUpdate(testCtx, "CmfNotepad:1", map[string]any{"text": "<p>new</p>"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "<p>new</p>", note.Text)
+	require.Len(t, *reqs, 2)
+	assert.Equal(t, []any{"CmfNotepad:1"}, (*reqs)[0].Args)
+	assert.Equal(t, "CmfNotepad.get", (*reqs)[1].Method)
+}
+
+func TestDayNotepadQuery(t *testing.T) {
+	day := time.Date(2026, 10, 17, 15, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	kwargs, err := DayNotepadQuery("CmfPerson:1", day).ToKwargs()
+
+	require.NoError(t, err)
+	body, err := json.Marshal(kwargs["filter"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		["parent_id", "==", null],
+		["cmf_owner_id", "==", "CmfPerson:1"],
+		["cmf_created_at", ">=", "2026-10-16T21:00:00Z"],
+		["cmf_created_at", "<", "2026-10-17T21:00:00Z"]
+	]`, string(body))
+	assert.Equal(t, []string{"cmf_created_at", "id"}, kwargs["order_by"])
+}
+
+func TestClient_NotepadAppendDay_NoNote_CreatesIt(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":[]}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1","text":"<ul><li>done</li></ul>","cmf_owner_id":"CmfPerson:1"}}`),
+	}
+	reqs := recordNotepadRequests(t, mockHTTP)
+
+	note, appended, err := client.NotepadAppendDay(testCtx, "CmfPerson:1", time.Now(), "* done")
+
+	require.NoError(t, err)
+	assert.True(t, appended)
+	assert.Equal(t, "CmfNotepad:1", note.ID)
+	require.Len(t, *reqs, 2)
+	assert.Equal(t, "CmfNotepad.list", (*reqs)[0].Method)
+	assert.Equal(t, "CmfNotepad.create", (*reqs)[1].Method)
+	assert.Equal(t, "<ul><li>done</li></ul>", (*reqs)[1].Kwargs["text"])
+}
+
+func TestClient_NotepadAppendDay_AppendsOnce(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":[{"id":"CmfNotepad:1","text":"<p>Plan</p>"}]}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1","text":"<p>Plan</p><p><strong>Done</strong></p>"}}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":[{"id":"CmfNotepad:1","text":"<p>Plan</p>\n<p><b>Done</b></p>"}]}`),
+	}
+	reqs := recordNotepadRequests(t, mockHTTP)
+
+	_, appended, err := client.NotepadAppendDay(testCtx, "CmfPerson:1", time.Now(), "**Done**")
+
+	require.NoError(t, err)
+	assert.True(t, appended)
+	require.Len(t, *reqs, 2)
+	assert.Equal(t, "CmfNotepad.update", (*reqs)[1].Method)
+	assert.Equal(t, "<p>Plan</p><p><strong>Done</strong></p>", (*reqs)[1].Kwargs["text"])
+
+	// The retry finds the text, as edited by the EVA editor, and stops.
+	note, appended, err := client.NotepadAppendDay(testCtx, "CmfPerson:1", time.Now(), "**Done**")
+
+	require.NoError(t, err)
+	assert.False(t, appended)
+	assert.Equal(t, "CmfNotepad:1", note.ID)
+	assert.Len(t, *reqs, 3)
+}
+
+func TestClient_NotepadAppendDay_NotTokenOwner_DeletesCreatedNote(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":[]}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1","cmf_owner_id":"CmfPerson:me"}}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":true}`),
+	}
+	reqs := recordNotepadRequests(t, mockHTTP)
+
+	_, _, err := client.NotepadAppendDay(testCtx, "CmfPerson:1", time.Now(), "done")
+
+	require.ErrorIs(t, err, ErrNotTokenOwner)
+	require.Len(t, *reqs, 3)
+	assert.Equal(t, "CmfNotepad.delete", (*reqs)[2].Method)
+}
+
+func TestClient_NotepadAppendDay_PersonRequired(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	reqs := recordNotepadRequests(t, mockHTTP)
+
+	_, _, err := client.NotepadAppendDay(testCtx, "", time.Now(), "done")
+
+	require.Error(t, err)
+	assert.Empty(t, *reqs)
+}
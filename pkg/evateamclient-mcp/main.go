@@ -20,12 +20,18 @@
 //	--token, -t           API authentication token (env: EVA_API_TOKEN) [required]
 //	--debug, -d           Enable debug logging for API requests (env: EVA_DEBUG)
 //	--timeout             API request timeout (env: EVA_TIMEOUT) [default: 30s]
+//	--rate-limit          API rate limit in requests per second (env: EVA_RATE_LIMIT) [default: unlimited]
+//	--cache               Cache slow-changing entities in memory (env: EVA_CACHE)
 //	--transport           Transport: stdio or http (env: MCP_TRANSPORT) [default: stdio]
 //	--http-addr           HTTP listen address (env: MCP_HTTP_ADDR) [default: 127.0.0.1:8080]
 //	--http-path           HTTP base path for MCP endpoint (env: MCP_HTTP_PATH) [default: /mcp]
 //	--http-stateless      Run HTTP transport in stateless mode (env: MCP_HTTP_STATELESS)
 //	--http-json-response  Return application/json instead of SSE (env: MCP_HTTP_JSON_RESPONSE)
 //
+// Tracing is enabled when OTEL_EXPORTER_OTLP_ENDPOINT (or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT) is set: spans are exported over
+// OTLP/HTTP, with a parent span per tool call and a child span per API call.
+//
 // Usage (stdio, for Claude Desktop / Claude Code CLI):
 //
 //	evateamclient-mcp --api-url="https://eva.example.com" --token="your-api-token"
@@ -69,6 +75,13 @@ type transportConfig struct {
 	HTTPPath         string
 	HTTPStateless    bool
 	HTTPJSONResponse bool
+	// RateLimit is the client-side API rate limit in requests per second.
+	RateLimit float64
+	// Cache enables the in-memory cache of slow-changing entities.
+	Cache bool
+	// FileRoot is the directory the attachment tools may read and write
+	// files in; empty disables their path mode.
+	FileRoot string
 }
 
 func main() {
@@ -110,6 +123,24 @@ func main() {
 				Value:       defaultRequestTimeout,
 				Destination: &cfg.Timeout,
 			},
+			&cli.FloatFlag{
+				Name:        "rate-limit",
+				Usage:       "Client-side API rate limit in requests per second (0 = unlimited)",
+				Sources:     cli.EnvVars("EVA_RATE_LIMIT"),
+				Destination: &tcfg.RateLimit,
+			},
+			&cli.BoolFlag{
+				Name:        "cache",
+				Usage:       "Cache slow-changing entities (logic types, tags, persons, projects, lists) in memory",
+				Sources:     cli.EnvVars("EVA_CACHE"),
+				Destination: &tcfg.Cache,
+			},
+			&cli.StringFlag{
+				Name:        "file-root",
+				Usage:       "Directory attachment upload/download paths are confined to (unset = path mode disabled)",
+				Sources:     cli.EnvVars("EVA_FILE_ROOT"),
+				Destination: &tcfg.FileRoot,
+			},
 			&cli.StringFlag{
 				Name:        "transport",
 				Usage:       "Transport: stdio or http",
@@ -166,8 +197,37 @@ func runServer(ctx context.Context, cfg *evateamclient.Config, tcfg *transportCo
 		Level: loggerLevel,
 	}))
 
+	tp, shutdownTracing, err := setupTracing(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Warn("failed to flush traces", "error", err)
+		}
+	}()
+
 	// Create EVA Team client
-	evaClient, err := evateamclient.NewClient(cfg, evateamclient.WithLogger(slogadapter.New(logger)))
+	// The circuit breaker fails tool calls fast while EVA is down instead of
+	// hanging the assistant for the full request timeout. Parallel tool calls
+	// often resolve the same entities, so identical reads are coalesced.
+	opts := []evateamclient.Option{
+		evateamclient.WithLogger(slogadapter.New(logger)),
+		evateamclient.WithCircuitBreaker(evateamclient.DefaultCircuitBreakerConfig()),
+		evateamclient.WithRequestCoalescing(),
+	}
+	if tp != nil {
+		opts = append(opts, evateamclient.WithTracerProvider(tp))
+	}
+	if tcfg.Cache {
+		opts = append(opts, evateamclient.WithCache(evateamclient.NewMemoryCache(0)))
+	}
+	if tcfg.RateLimit > 0 {
+		opts = append(opts, evateamclient.WithRateLimit(evateamclient.RateLimitConfig{
+			Global: evateamclient.RateLimit{RPS: tcfg.RateLimit},
+		}))
+	}
+	evaClient, err := evateamclient.NewClient(cfg, opts...)
 	if err != nil {
 		return fmt.Errorf("failed to create EVA client: %w", err)
 	}
@@ -188,7 +248,7 @@ func runServer(ctx context.Context, cfg *evateamclient.Config, tcfg *transportCo
 	)
 
 	// Register tools before starting the server
-	registry := tools.NewRegistry(evaClient)
+	registry := tools.NewRegistry(evaClient, tcfg.FileRoot)
 	registry.RegisterAll(server)
 
 	// Setup graceful shutdown
@@ -9,6 +9,7 @@
 package main
 
 import (
+	"context"
 	"testing"
 
 	"github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
@@ -19,8 +20,21 @@ import (
 // tool — which builds a relaxed input schema per tool via reflection — does not
 // panic. Registration never calls the handlers, so a nil client is fine.
 func TestRegisterAll_BuildsRelaxedSchemasForEveryTool(t *testing.T) {
-	registry := tools.NewRegistry(nil)
+	registry := tools.NewRegistry(nil, "")
 	require.NotPanics(t, func() {
 		registry.RegisterAll(newTestMCPServer())
 	})
 }
+
+// TestNewRegistry_NoFileRootRefusesPaths guards that a registry built
+// without a file root never touches the file system for attachment paths.
+func TestNewRegistry_NoFileRootRefusesPaths(t *testing.T) {
+	registry := tools.NewRegistry(nil, "")
+
+	_, err := registry.Attachment.AttachmentUpload(context.Background(), &tools.AttachmentUploadInput{
+		ParentID: "CmfTask:1",
+		Path:     "/etc/passwd",
+	})
+
+	require.ErrorIs(t, err, tools.ErrInvalidInput)
+}
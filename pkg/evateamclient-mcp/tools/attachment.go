@@ -0,0 +1,261 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/raoptimus/evateamclient.go"
+)
+
+// maxInlineDownload caps the content returned as base64 by
+// eva_attachment_download; larger files must be saved to a path.
+const maxInlineDownload = 10 << 20
+
+var (
+	// errInlineTooLarge reports an attachment too large to return inline.
+	errInlineTooLarge = fmt.Errorf("%w: attachment larger than %d bytes, set path to save it", ErrInvalidInput, maxInlineDownload)
+	// errPathDisabled reports a path while no file root is configured.
+	errPathDisabled = fmt.Errorf("%w: path is disabled, the server has no file root", ErrInvalidInput)
+)
+
+// AttachmentTools provides MCP tool handlers for attachment operations.
+type AttachmentTools struct {
+	client *evateamclient.Client
+	// fileRoot is the only directory whose files path may name; "" disables
+	// path mode.
+	fileRoot string
+}
+
+// NewAttachmentTools creates a new AttachmentTools instance. Upload and
+// download paths are confined to fileRoot; with an empty fileRoot only
+// base64 content is accepted.
+func NewAttachmentTools(client *evateamclient.Client, fileRoot string) *AttachmentTools {
+	if fileRoot != "" {
+		if abs, err := filepath.Abs(fileRoot); err == nil {
+			fileRoot = abs
+		}
+	}
+
+	return &AttachmentTools{client: client, fileRoot: fileRoot}
+}
+
+// AttachmentListInput represents input for eva_attachment_list tool.
+type AttachmentListInput struct {
+	ParentID string `json:"parent_id"`
+}
+
+// AttachmentList returns the attachments of a task, comment or document.
+func (a *AttachmentTools) AttachmentList(ctx context.Context, input *AttachmentListInput) (*ListResult, error) {
+	if input.ParentID == "" {
+		return nil, WrapError("attachment_list", ErrInvalidInput)
+	}
+
+	attachments, _, err := a.client.Attachments(ctx, input.ParentID)
+	if err != nil {
+		return nil, WrapError("attachment_list", err)
+	}
+
+	return &ListResult{
+		Items: toAnySlice(attachments),
+	}, nil
+}
+
+// AttachmentUploadInput represents input for eva_attachment_upload tool.
+// Exactly one of ContentBase64 and Path is set.
+type AttachmentUploadInput struct {
+	ParentID      string `json:"parent_id"`
+	Name          string `json:"name,omitempty"`
+	ContentBase64 string `json:"content_base64,omitempty"`
+	Path          string `json:"path,omitempty"`
+}
+
+// AttachmentUpload uploads base64 content or a local file as an attachment.
+// Both are streamed to the server.
+func (a *AttachmentTools) AttachmentUpload(ctx context.Context, input *AttachmentUploadInput) (any, error) {
+	if input.ParentID == "" || (input.ContentBase64 == "") == (input.Path == "") {
+		return nil, WrapError("attachment_upload", ErrInvalidInput)
+	}
+
+	name := input.Name
+	var r io.Reader
+	if input.Path != "" {
+		root, rel, err := a.openPath(input.Path)
+		if err != nil {
+			return nil, WrapError("attachment_upload", err)
+		}
+		defer root.Close()
+		f, err := root.Open(rel)
+		if err != nil {
+			return nil, WrapError("attachment_upload", err)
+		}
+		defer f.Close()
+		r = f
+		if name == "" {
+			name = filepath.Base(input.Path)
+		}
+	} else {
+		if name == "" {
+			return nil, WrapError("attachment_upload", fmt.Errorf("%w: name is required with content_base64", ErrInvalidInput))
+		}
+		r = base64.NewDecoder(base64.StdEncoding, strings.NewReader(input.ContentBase64))
+	}
+
+	attachment, err := a.client.AttachmentCreate(ctx, input.ParentID, name, r)
+	if err != nil {
+		var corrupt base64.CorruptInputError
+		if errors.As(err, &corrupt) {
+			return nil, WrapError("attachment_upload", fmt.Errorf("%w: content_base64: %w", ErrInvalidInput, err))
+		}
+		return nil, WrapError("attachment_upload", err)
+	}
+
+	return attachment, nil
+}
+
+// AttachmentDownloadInput represents input for eva_attachment_download tool.
+type AttachmentDownloadInput struct {
+	ID        string `json:"id"`
+	Path      string `json:"path,omitempty"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+// AttachmentDownloadResult is the downloaded attachment: saved to Path, or
+// inline in ContentBase64 when no path is given.
+type AttachmentDownloadResult struct {
+	ID            string `json:"id"`
+	Size          int64  `json:"size"`
+	Path          string `json:"path,omitempty"`
+	ContentBase64 string `json:"content_base64,omitempty"`
+}
+
+// AttachmentDownload saves an attachment to a local file, or returns its
+// content as base64 when it is at most maxInlineDownload bytes.
+func (a *AttachmentTools) AttachmentDownload(ctx context.Context, input *AttachmentDownloadInput) (*AttachmentDownloadResult, error) {
+	if input.ID == "" {
+		return nil, WrapError("attachment_download", ErrInvalidInput)
+	}
+
+	if input.Path == "" {
+		buf := &limitedBuffer{limit: maxInlineDownload}
+		n, err := a.client.AttachmentDownload(ctx, input.ID, buf)
+		if err != nil {
+			return nil, WrapError("attachment_download", err)
+		}
+
+		return &AttachmentDownloadResult{
+			ID:            input.ID,
+			Size:          n,
+			ContentBase64: base64.StdEncoding.EncodeToString(buf.Bytes()),
+		}, nil
+	}
+
+	root, rel, err := a.openPath(input.Path)
+	if err != nil {
+		return nil, WrapError("attachment_download", err)
+	}
+	defer root.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if input.Overwrite {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := root.OpenFile(rel, flags, 0o644)
+	if err != nil {
+		return nil, WrapError("attachment_download", err)
+	}
+
+	n, err := a.client.AttachmentDownload(ctx, input.ID, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = root.Remove(rel)
+		return nil, WrapError("attachment_download", err)
+	}
+
+	return &AttachmentDownloadResult{
+		ID:   input.ID,
+		Size: n,
+		Path: input.Path,
+	}, nil
+}
+
+// openPath opens the file root and returns path relative to it. path is
+// relative to the root, or absolute inside it; ".." components are
+// rejected, and the returned os.Root refuses symlinks leading out of it.
+func (a *AttachmentTools) openPath(path string) (*os.Root, string, error) {
+	if a.fileRoot == "" {
+		return nil, "", errPathDisabled
+	}
+
+	rel := path
+	if filepath.IsAbs(path) {
+		var err error
+		if rel, err = filepath.Rel(a.fileRoot, path); err != nil {
+			return nil, "", fmt.Errorf("%w: path %s is outside the file root", ErrInvalidInput, path)
+		}
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if part == ".." {
+			return nil, "", fmt.Errorf("%w: path %s is outside the file root", ErrInvalidInput, path)
+		}
+	}
+
+	root, err := os.OpenRoot(a.fileRoot)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return root, rel, nil
+}
+
+// AttachmentDeleteInput represents input for eva_attachment_delete tool.
+type AttachmentDeleteInput struct {
+	ID string `json:"id"`
+}
+
+// AttachmentDelete deletes an attachment.
+func (a *AttachmentTools) AttachmentDelete(ctx context.Context, input *AttachmentDeleteInput) (any, error) {
+	if input.ID == "" {
+		return nil, WrapError("attachment_delete", ErrInvalidInput)
+	}
+
+	if err := a.client.AttachmentDelete(ctx, input.ID); err != nil {
+		return nil, WrapError("attachment_delete", err)
+	}
+
+	return map[string]bool{"success": true}, nil
+}
+
+// limitedBuffer is a buffer failing writes beyond limit bytes. It does not
+// embed bytes.Buffer, whose ReadFrom would bypass the limit in io.Copy.
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buf.Len()+len(p) > b.limit {
+		return 0, errInlineTooLarge
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
@@ -0,0 +1,221 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools_test
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	evateamclient "github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAttachmentServer creates an httptest.Server storing uploaded files in
+// files by name and serving CmfAttachment:1 as the file "report.txt". The
+// tools confine paths to fileRoot. The returned *int counts every HTTP
+// request the server received.
+func newAttachmentServer(t *testing.T, files map[string]string, fileRoot string) (*tools.AttachmentTools, *int) {
+	t.Helper()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Query().Get("m") == "CmfAttachment.create":
+			f, header, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			content, _ := io.ReadAll(f)
+			files[header.Filename] = string(content)
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":{"id":"CmfAttachment:1","name":"` + header.Filename + `"}}`))
+		case r.URL.Query().Get("m") == "CmfAttachment.get":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":{"id":"CmfAttachment:1","url":"/media/report.txt"}}`))
+		case r.URL.Path == "/media/report.txt":
+			_, _ = w.Write([]byte(files["report.txt"]))
+		default:
+			http.Error(w, "unexpected call", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := evateamclient.NewClient(&evateamclient.Config{
+		BaseURL:  srv.URL,
+		APIToken: "test-token",
+	})
+	require.NoError(t, err)
+	return tools.NewAttachmentTools(client, fileRoot), &calls
+}
+
+func TestAttachmentUpload_Base64(t *testing.T) {
+	files := map[string]string{}
+	at, _ := newAttachmentServer(t, files, "")
+
+	result, err := at.AttachmentUpload(context.Background(), &tools.AttachmentUploadInput{
+		ParentID:      "CmfTask:1",
+		Name:          "report.txt",
+		ContentBase64: base64.StdEncoding.EncodeToString([]byte("quarterly numbers")),
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "quarterly numbers", files["report.txt"])
+}
+
+func TestAttachmentUpload_Path(t *testing.T) {
+	files := map[string]string{}
+	root := t.TempDir()
+	at, _ := newAttachmentServer(t, files, root)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "report.txt"), []byte("from disk"), 0o600))
+
+	_, err := at.AttachmentUpload(context.Background(), &tools.AttachmentUploadInput{
+		ParentID: "CmfTask:1",
+		Path:     "report.txt",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "from disk", files["report.txt"])
+}
+
+func TestAttachmentUpload_InvalidInput(t *testing.T) {
+	at, calls := newAttachmentServer(t, map[string]string{}, "")
+	tests := []struct {
+		name  string
+		input *tools.AttachmentUploadInput
+	}{
+		{"no parent", &tools.AttachmentUploadInput{Name: "a", ContentBase64: "YQ=="}},
+		{"no content", &tools.AttachmentUploadInput{ParentID: "CmfTask:1", Name: "a"}},
+		{"content and path", &tools.AttachmentUploadInput{ParentID: "CmfTask:1", Name: "a", ContentBase64: "YQ==", Path: "/tmp/a"}},
+		{"base64 without name", &tools.AttachmentUploadInput{ParentID: "CmfTask:1", ContentBase64: "YQ=="}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := at.AttachmentUpload(context.Background(), tt.input)
+
+			assert.ErrorIs(t, err, tools.ErrInvalidInput)
+		})
+	}
+	assert.Zero(t, *calls)
+}
+
+func TestAttachmentUpload_CorruptBase64(t *testing.T) {
+	at, _ := newAttachmentServer(t, map[string]string{}, "")
+
+	_, err := at.AttachmentUpload(context.Background(), &tools.AttachmentUploadInput{
+		ParentID:      "CmfTask:1",
+		Name:          "report.txt",
+		ContentBase64: "not base64!",
+	})
+
+	assert.ErrorIs(t, err, tools.ErrInvalidInput)
+}
+
+func TestAttachmentDownload_Inline(t *testing.T) {
+	at, _ := newAttachmentServer(t, map[string]string{"report.txt": "quarterly numbers"}, "")
+
+	result, err := at.AttachmentDownload(context.Background(), &tools.AttachmentDownloadInput{ID: "CmfAttachment:1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(17), result.Size)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("quarterly numbers")), result.ContentBase64)
+}
+
+func TestAttachmentDownload_InlineTooLarge(t *testing.T) {
+	at, _ := newAttachmentServer(t, map[string]string{"report.txt": strings.Repeat("x", 10<<20+1)}, "")
+
+	_, err := at.AttachmentDownload(context.Background(), &tools.AttachmentDownloadInput{ID: "CmfAttachment:1"})
+
+	require.ErrorIs(t, err, tools.ErrInvalidInput)
+	assert.ErrorContains(t, err, "set path")
+}
+
+func TestAttachmentDownload_Path(t *testing.T) {
+	root := t.TempDir()
+	at, _ := newAttachmentServer(t, map[string]string{"report.txt": "quarterly numbers"}, root)
+	path := filepath.Join(root, "report.txt")
+
+	result, err := at.AttachmentDownload(context.Background(), &tools.AttachmentDownloadInput{
+		ID:   "CmfAttachment:1",
+		Path: path,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, path, result.Path)
+	assert.Empty(t, result.ContentBase64)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "quarterly numbers", string(content))
+}
+
+func TestAttachmentDownload_KeepsExistingFile(t *testing.T) {
+	root := t.TempDir()
+	at, calls := newAttachmentServer(t, map[string]string{"report.txt": "new"}, root)
+	path := filepath.Join(root, "report.txt")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0o600))
+
+	_, err := at.AttachmentDownload(context.Background(), &tools.AttachmentDownloadInput{
+		ID:   "CmfAttachment:1",
+		Path: path,
+	})
+
+	require.ErrorIs(t, err, os.ErrExist)
+	assert.Zero(t, *calls)
+
+	_, err = at.AttachmentDownload(context.Background(), &tools.AttachmentDownloadInput{
+		ID:        "CmfAttachment:1",
+		Path:      path,
+		Overwrite: true,
+	})
+
+	require.NoError(t, err)
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "new", string(content))
+}
+
+func TestAttachment_PathOutsideFileRoot(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "id_rsa")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0o600))
+	require.NoError(t, os.Symlink(secret, filepath.Join(root, "link")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "dir")))
+
+	files := map[string]string{"report.txt": "new"}
+	at, calls := newAttachmentServer(t, files, root)
+	for _, path := range []string{"../id_rsa", secret, "link", "dir/id_rsa", "sub/../../id_rsa"} {
+		_, err := at.AttachmentUpload(context.Background(), &tools.AttachmentUploadInput{ParentID: "CmfTask:1", Path: path})
+		require.Error(t, err, path)
+
+		_, err = at.AttachmentDownload(context.Background(), &tools.AttachmentDownloadInput{
+			ID: "CmfAttachment:1", Path: path, Overwrite: true,
+		})
+		require.Error(t, err, path)
+	}
+	assert.Empty(t, files["id_rsa"])
+	content, _ := os.ReadFile(secret)
+	assert.Equal(t, "secret", string(content))
+
+	disabled, _ := newAttachmentServer(t, files, "")
+	_, err := disabled.AttachmentUpload(context.Background(), &tools.AttachmentUploadInput{ParentID: "CmfTask:1", Path: "report.txt"})
+	require.ErrorIs(t, err, tools.ErrInvalidInput)
+	_, err = disabled.AttachmentDownload(context.Background(), &tools.AttachmentDownloadInput{ID: "CmfAttachment:1", Path: "report.txt"})
+	require.ErrorIs(t, err, tools.ErrInvalidInput)
+	assert.Zero(t, *calls)
+}
@@ -0,0 +1,160 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/raoptimus/evateamclient.go"
+)
+
+// AuditTools provides read-only MCP tool handlers for the audit log.
+type AuditTools struct {
+	client *evateamclient.Client
+}
+
+// NewAuditTools creates a new AuditTools instance.
+func NewAuditTools(client *evateamclient.Client) *AuditTools {
+	return &AuditTools{client: client}
+}
+
+// AuditFilterInput narrows audit records down to an object, a person and a
+// period. Dates are YYYY-MM-DD (date_to inclusive) or RFC 3339 timestamps
+// (date_to exclusive).
+type AuditFilterInput struct {
+	ObjectID string `json:"object_id,omitempty"`
+	PersonID string `json:"person_id,omitempty"`
+	DateFrom string `json:"date_from,omitempty"`
+	DateTo   string `json:"date_to,omitempty"`
+}
+
+// apply adds the filter conditions to qb.
+func (f *AuditFilterInput) apply(qb *evateamclient.QueryBuilder) error {
+	if f.ObjectID != "" {
+		qb.Where(sq.Eq{evateamclient.AuditFieldParentID: f.ObjectID})
+	}
+	if f.PersonID != "" {
+		qb.Where(sq.Eq{evateamclient.AuditFieldCmfOwnerID: f.PersonID})
+	}
+	if f.DateFrom != "" {
+		from, _, err := parseAuditDate(f.DateFrom)
+		if err != nil {
+			return err
+		}
+		qb.Where(sq.GtOrEq{evateamclient.AuditFieldCmfCreatedAt: from})
+	}
+	if f.DateTo != "" {
+		to, dateOnly, err := parseAuditDate(f.DateTo)
+		if err != nil {
+			return err
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		qb.Where(sq.Lt{evateamclient.AuditFieldCmfCreatedAt: to})
+	}
+
+	return nil
+}
+
+// parseAuditDate parses a YYYY-MM-DD date (dateOnly) or an RFC 3339
+// timestamp, in UTC.
+func parseAuditDate(s string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: date %q is neither YYYY-MM-DD nor RFC 3339", ErrInvalidInput, s)
+	}
+
+	return t.UTC(), false, nil
+}
+
+// AuditListInput represents input for eva_audit_list tool.
+type AuditListInput struct {
+	QueryInput
+	CursorInput
+	AuditFilterInput
+}
+
+// AuditList returns a list of audit records.
+func (a *AuditTools) AuditList(ctx context.Context, input *AuditListInput) (*ListResult, error) {
+	qb, err := BuildQuery(evateamclient.EntityAudit, &input.QueryInput)
+	if err != nil {
+		return nil, WrapError("audit_list", err)
+	}
+	if err := input.AuditFilterInput.apply(qb); err != nil {
+		return nil, WrapError("audit_list", err)
+	}
+
+	if input.Cursor != nil {
+		kwargs, err := qb.ToKwargs()
+		if err != nil {
+			return nil, WrapError("audit_list", err)
+		}
+		if _, ok := kwargs["fields"]; !ok {
+			kwargs["fields"] = evateamclient.DefaultAuditFields
+		}
+		return listByCursor(ctx, a.client, evateamclient.EntityAudit, kwargs, &input.CursorInput, input.Limit, "audit_list")
+	}
+
+	// Default order by creation time descending
+	if len(input.OrderBy) == 0 {
+		qb = qb.OrderBy("-" + evateamclient.AuditFieldCmfCreatedAt)
+	}
+
+	records, _, err := a.client.AuditList(ctx, qb)
+	if err != nil {
+		return nil, WrapError("audit_list", err)
+	}
+
+	return &ListResult{
+		Items:   toAnySlice(records),
+		HasMore: len(records) == input.Limit && input.Limit > 0,
+	}, nil
+}
+
+// AuditGetInput represents input for eva_audit_get tool.
+type AuditGetInput struct {
+	ID     string   `json:"id"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// AuditGet retrieves a single audit record.
+func (a *AuditTools) AuditGet(ctx context.Context, input *AuditGetInput) (any, error) {
+	if input.ID == "" {
+		return nil, WrapError("audit_get", ErrInvalidInput)
+	}
+
+	record, _, err := a.client.Audit(ctx, input.ID, input.Fields)
+	if err != nil {
+		return nil, WrapError("audit_get", err)
+	}
+
+	return record, nil
+}
+
+// AuditCount counts audit records.
+func (a *AuditTools) AuditCount(ctx context.Context, input *AuditFilterInput) (*CountResult, error) {
+	qb := evateamclient.NewQueryBuilder().From(evateamclient.EntityAudit)
+	if err := input.apply(qb); err != nil {
+		return nil, WrapError("audit_count", err)
+	}
+
+	count, err := a.client.AuditCount(ctx, qb)
+	if err != nil {
+		return nil, WrapError("audit_count", err)
+	}
+
+	return &CountResult{Count: count}, nil
+}
@@ -0,0 +1,113 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	evateamclient "github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditServer creates an httptest.Server answering every call with
+// responseJSON and recording the kwargs of the last request.
+func newAuditServer(t *testing.T, responseJSON string) (*tools.AuditTools, *map[string]any) {
+	t.Helper()
+
+	var kwargs map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Kwargs map[string]any `json:"kwargs"`
+		}
+		_ = json.Unmarshal(body, &req)
+		kwargs = req.Kwargs
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responseJSON))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := evateamclient.NewClient(&evateamclient.Config{
+		BaseURL:  srv.URL,
+		APIToken: "test-token",
+	})
+	require.NoError(t, err)
+	return tools.NewAuditTools(client), &kwargs
+}
+
+func TestAuditList_PersonChangesInPeriod(t *testing.T) {
+	at, kwargs := newAuditServer(t, `{"jsonrpc":"2.2","result":[{"id":"CmfAudit:1","field_name":"status"}]}`)
+
+	result, err := at.AuditList(context.Background(), &tools.AuditListInput{
+		AuditFilterInput: tools.AuditFilterInput{
+			PersonID: "CmfPerson:1",
+			DateFrom: "2026-01-01",
+			DateTo:   "2026-03-31",
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, result.Items, 1)
+	assert.Equal(t, []any{
+		[]any{"cmf_owner_id", "==", "CmfPerson:1"},
+		[]any{"cmf_created_at", ">=", "2026-01-01T00:00:00Z"},
+		[]any{"cmf_created_at", "<", "2026-04-01T00:00:00Z"},
+	}, (*kwargs)["filter"])
+	assert.Equal(t, []any{"-cmf_created_at"}, (*kwargs)["order_by"])
+}
+
+func TestAuditList_ObjectHistoryWithCursor(t *testing.T) {
+	at, kwargs := newAuditServer(t, `{"jsonrpc":"2.2","result":[{"id":"CmfAudit:1","cmf_created_at":"2026-01-01T00:00:00Z"}]}`)
+	first := ""
+
+	result, err := at.AuditList(context.Background(), &tools.AuditListInput{
+		QueryInput:       tools.QueryInput{Limit: 1},
+		CursorInput:      tools.CursorInput{Cursor: &first},
+		AuditFilterInput: tools.AuditFilterInput{ObjectID: "CmfTask:1"},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.HasMore)
+	assert.NotEmpty(t, result.NextCursor)
+	assert.Equal(t, []any{"parent_id", "==", "CmfTask:1"}, (*kwargs)["filter"])
+}
+
+func TestAuditList_InvalidDate(t *testing.T) {
+	at, _ := newAuditServer(t, `{"jsonrpc":"2.2","result":[]}`)
+
+	_, err := at.AuditList(context.Background(), &tools.AuditListInput{
+		AuditFilterInput: tools.AuditFilterInput{DateFrom: "01.01.2026"},
+	})
+
+	assert.ErrorIs(t, err, tools.ErrInvalidInput)
+}
+
+func TestAuditCount_TimestampBounds(t *testing.T) {
+	at, kwargs := newAuditServer(t, `{"jsonrpc":"2.2","result":7}`)
+
+	result, err := at.AuditCount(context.Background(), &tools.AuditFilterInput{
+		DateFrom: "2026-01-01T12:00:00+03:00",
+		DateTo:   "2026-01-02T12:00:00Z",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, result.Count)
+	assert.Equal(t, []any{
+		[]any{"cmf_created_at", ">=", "2026-01-01T09:00:00Z"},
+		[]any{"cmf_created_at", "<", "2026-01-02T12:00:00Z"},
+	}, (*kwargs)["filter"])
+}
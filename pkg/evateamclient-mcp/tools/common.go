@@ -11,7 +11,9 @@ package tools
 
 // Filter represents a single filter condition for queries.
 // Format: [field, operator, value]
-// Operators: "==", "!=", ">", ">=", "<", "<=", "LIKE", "contains"
+// Operators: "==", "!=", ">", ">=", "<", "<=", "IN", "NOT IN", "LIKE", "NOT LIKE",
+// "ILIKE", "NOT ILIKE", "contains", "not contains" (m2m fields such as executors,
+// tags, lists), "IS NULL", "IS NOT NULL" (value is ignored)
 type Filter struct {
 	Field    string `json:"field"`
 	Operator string `json:"operator"`
@@ -37,11 +39,20 @@ type QueryInput struct {
 	IncludeArchived bool `json:"include_archived,omitempty"`
 }
 
+// CursorInput switches a list tool to keyset pagination, which does not skip
+// or repeat records changed between calls.
+type CursorInput struct {
+	// "" for the first page, next_cursor of the previous page to resume.
+	// Offset and order_by are ignored.
+	Cursor *string `json:"cursor,omitempty"`
+}
+
 // ListResult wraps list response with metadata.
 type ListResult struct {
-	Items      []any `json:"items"`
-	TotalCount int64 `json:"total_count,omitempty"`
-	HasMore    bool  `json:"has_more,omitempty"`
+	Items      []any  `json:"items"`
+	TotalCount int64  `json:"total_count,omitempty"`
+	HasMore    bool   `json:"has_more,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // CountResult wraps count response.
@@ -0,0 +1,194 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/raoptimus/evateamclient.go"
+)
+
+// CompanyTools provides MCP tool handlers for company (contractor, customer) operations.
+type CompanyTools struct {
+	client *evateamclient.Client
+}
+
+// NewCompanyTools creates a new CompanyTools instance.
+func NewCompanyTools(client *evateamclient.Client) *CompanyTools {
+	return &CompanyTools{client: client}
+}
+
+// CompanyListInput represents input for eva_company_list tool.
+type CompanyListInput struct {
+	QueryInput
+}
+
+// CompanyList returns a list of companies.
+func (c *CompanyTools) CompanyList(ctx context.Context, input *CompanyListInput) (*ListResult, error) {
+	qb, err := BuildQuery(evateamclient.EntityCompany, &input.QueryInput)
+	if err != nil {
+		return nil, WrapError("company_list", err)
+	}
+
+	companies, _, err := c.client.CompaniesList(ctx, qb)
+	if err != nil {
+		return nil, WrapError("company_list", err)
+	}
+
+	return &ListResult{
+		Items:   toAnySlice(companies),
+		HasMore: len(companies) == input.Limit && input.Limit > 0,
+	}, nil
+}
+
+// CompanyGetInput represents input for eva_company_get tool.
+type CompanyGetInput struct {
+	// Company ID (e.g., "CmfCompany:uuid") or code
+	ID string `json:"id"`
+
+	// Fields to return
+	Fields []string `json:"fields,omitempty"`
+}
+
+// CompanyGet retrieves a single company by ID or code.
+func (c *CompanyTools) CompanyGet(ctx context.Context, input *CompanyGetInput) (any, error) {
+	if input.ID == "" {
+		return nil, WrapError("company_get", ErrInvalidInput)
+	}
+
+	company, _, err := c.client.Company(ctx, input.ID, input.Fields)
+	if err != nil {
+		return nil, WrapError("company_get", err)
+	}
+
+	return company, nil
+}
+
+// CompanyCreateInput represents input for eva_company_create tool.
+type CompanyCreateInput struct {
+	Name string `json:"name"`
+	INN  string `json:"inn,omitempty"`
+	KPP  string `json:"kpp,omitempty"`
+	OGRN string `json:"ogrn,omitempty"`
+}
+
+// CompanyCreate creates a new company.
+func (c *CompanyTools) CompanyCreate(ctx context.Context, input *CompanyCreateInput) (any, error) {
+	if input.Name == "" {
+		return nil, WrapError("company_create", ErrInvalidInput)
+	}
+
+	company, err := c.client.CompanyCreate(ctx, &evateamclient.CompanyCreateParams{
+		Name: input.Name,
+		INN:  input.INN,
+		KPP:  input.KPP,
+		OGRN: input.OGRN,
+	})
+	if err != nil {
+		return nil, WrapError("company_create", err)
+	}
+
+	return company, nil
+}
+
+// CompanyUpdateInput represents input for eva_company_update tool.
+type CompanyUpdateInput struct {
+	ID      string         `json:"id"`
+	Updates map[string]any `json:"updates"`
+}
+
+// CompanyUpdate updates an existing company.
+func (c *CompanyTools) CompanyUpdate(ctx context.Context, input CompanyUpdateInput) (any, error) {
+	if input.ID == "" {
+		return nil, WrapError("company_update", ErrInvalidInput)
+	}
+
+	company, err := c.client.CompanyUpdate(ctx, input.ID, input.Updates)
+	if err != nil {
+		return nil, WrapError("company_update", err)
+	}
+
+	return company, nil
+}
+
+// CompanyDeleteInput represents input for eva_company_delete tool.
+type CompanyDeleteInput struct {
+	ID string `json:"id"`
+}
+
+// CompanyDelete deletes a company.
+func (c *CompanyTools) CompanyDelete(ctx context.Context, input CompanyDeleteInput) (any, error) {
+	if input.ID == "" {
+		return nil, WrapError("company_delete", ErrInvalidInput)
+	}
+
+	if err := c.client.CompanyDelete(ctx, input.ID); err != nil {
+		return nil, WrapError("company_delete", err)
+	}
+
+	return map[string]bool{"success": true}, nil
+}
+
+// CompanyCountInput represents input for eva_company_count tool.
+type CompanyCountInput struct {
+	// Tax ID (ИНН) to match exactly
+	INN string `json:"inn,omitempty"`
+}
+
+// CompanyCount counts companies.
+func (c *CompanyTools) CompanyCount(ctx context.Context, input CompanyCountInput) (*CountResult, error) {
+	qb := evateamclient.NewQueryBuilder().From(evateamclient.EntityCompany)
+
+	if input.INN != "" {
+		qb = qb.Where(sq.Eq{evateamclient.CompanyFieldINN: input.INN})
+	}
+
+	count, err := c.client.CompanyCount(ctx, qb)
+	if err != nil {
+		return nil, WrapError("company_count", err)
+	}
+
+	return &CountResult{Count: count}, nil
+}
+
+// CompanyLinkedInput represents input for eva_company_persons and
+// eva_company_projects tools.
+type CompanyLinkedInput struct {
+	CompanyID string   `json:"company_id"`
+	Fields    []string `json:"fields,omitempty"`
+}
+
+// CompanyPersons returns the persons linked to a company.
+func (c *CompanyTools) CompanyPersons(ctx context.Context, input *CompanyLinkedInput) (*ListResult, error) {
+	if input.CompanyID == "" {
+		return nil, WrapError("company_persons", ErrInvalidInput)
+	}
+
+	persons, _, err := c.client.CompanyPersons(ctx, input.CompanyID, input.Fields)
+	if err != nil {
+		return nil, WrapError("company_persons", err)
+	}
+
+	return &ListResult{Items: toAnySlice(persons)}, nil
+}
+
+// CompanyProjects returns the projects linked to a company.
+func (c *CompanyTools) CompanyProjects(ctx context.Context, input *CompanyLinkedInput) (*ListResult, error) {
+	if input.CompanyID == "" {
+		return nil, WrapError("company_projects", ErrInvalidInput)
+	}
+
+	projects, _, err := c.client.CompanyProjects(ctx, input.CompanyID, input.Fields)
+	if err != nil {
+		return nil, WrapError("company_projects", err)
+	}
+
+	return &ListResult{Items: toAnySlice(projects)}, nil
+}
@@ -0,0 +1,94 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	evateamclient "github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCompanyServer creates an httptest.Server answering every call with
+// responseJSON and recording the method and kwargs of the last request.
+func newCompanyServer(t *testing.T, responseJSON string) (*tools.CompanyTools, *string, *map[string]any) {
+	t.Helper()
+
+	var (
+		method string
+		kwargs map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Method string         `json:"method"`
+			Kwargs map[string]any `json:"kwargs"`
+		}
+		_ = json.Unmarshal(body, &req)
+		method, kwargs = req.Method, req.Kwargs
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responseJSON))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := evateamclient.NewClient(&evateamclient.Config{
+		BaseURL:  srv.URL,
+		APIToken: "test-token",
+	})
+	require.NoError(t, err)
+	return tools.NewCompanyTools(client), &method, &kwargs
+}
+
+func TestCompanyCreate_SendsRequisites(t *testing.T) {
+	ct, method, kwargs := newCompanyServer(t, `{"jsonrpc":"2.2","result":{"id":"CmfCompany:1","name":"Acme"}}`)
+
+	result, err := ct.CompanyCreate(context.Background(), &tools.CompanyCreateInput{
+		Name: "Acme",
+		INN:  "7701234567",
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "CmfCompany.create", *method)
+	assert.Equal(t, map[string]any{"name": "Acme", "inn": "7701234567"}, *kwargs)
+}
+
+func TestCompanyCreate_EmptyName(t *testing.T) {
+	ct, _, _ := newCompanyServer(t, `{"jsonrpc":"2.2","result":null}`)
+
+	_, err := ct.CompanyCreate(context.Background(), &tools.CompanyCreateInput{INN: "7701234567"})
+
+	assert.ErrorIs(t, err, tools.ErrInvalidInput)
+}
+
+func TestCompanyPersons_EmptyCompanyID(t *testing.T) {
+	ct, _, _ := newCompanyServer(t, `{"jsonrpc":"2.2","result":[]}`)
+
+	_, err := ct.CompanyPersons(context.Background(), &tools.CompanyLinkedInput{})
+
+	assert.ErrorIs(t, err, tools.ErrInvalidInput)
+}
+
+func TestCompanyProjects_NoRelationInSchema(t *testing.T) {
+	ct, method, _ := newCompanyServer(t, `{"jsonrpc":"2.2","result":[],
+		"meta":{"Project":{"fields":{"name":{"api_allow":true}}}}}`)
+
+	_, err := ct.CompanyProjects(context.Background(), &tools.CompanyLinkedInput{CompanyID: "CmfCompany:1"})
+
+	assert.ErrorIs(t, err, evateamclient.ErrNoRelation)
+	assert.Equal(t, "CmfProject.list", *method)
+}
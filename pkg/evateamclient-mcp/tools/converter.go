@@ -10,12 +10,13 @@ package tools
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
-	sq "github.com/Masterminds/squirrel"
 	"github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/bql"
 )
 
 const defaultPaginationLimit = 100 // Default limit for pagination
@@ -63,29 +64,75 @@ func BuildQuery(entity string, input *QueryInput) (*evateamclient.QueryBuilder,
 	return qb, nil
 }
 
-// filterToPredicate converts a Filter to Squirrel predicate.
-func filterToPredicate(f Filter) (any, error) {
-	switch f.Operator {
-	case "==", "=":
-		return sq.Eq{f.Field: f.Value}, nil
-	case "!=", "<>":
-		return sq.NotEq{f.Field: f.Value}, nil
-	case ">":
-		return sq.Gt{f.Field: f.Value}, nil
-	case ">=":
-		return sq.GtOrEq{f.Field: f.Value}, nil
-	case "<":
-		return sq.Lt{f.Field: f.Value}, nil
-	case "<=":
-		return sq.LtOrEq{f.Field: f.Value}, nil
-	case "LIKE", "like":
-		return sq.Like{f.Field: f.Value}, nil
-	case "contains":
-		// EVA-specific "contains" operator - pass as raw kwargs
-		return nil, fmt.Errorf("'contains' operator requires raw kwargs, use custom filter")
-	default:
-		return nil, fmt.Errorf("unsupported operator: %s", f.Operator)
+// filterOps maps Filter operators, case-insensitively, to BQL operators.
+var filterOps = map[string]bql.Op{
+	"==":           bql.Eq,
+	"=":            bql.Eq,
+	"!=":           bql.NotEq,
+	"<>":           bql.NotEq,
+	">":            bql.Gt,
+	">=":           bql.GtOrEq,
+	"<":            bql.Lt,
+	"<=":           bql.LtOrEq,
+	"in":           bql.In,
+	"not in":       bql.NotIn,
+	"like":         bql.Like,
+	"not like":     bql.NotLike,
+	"ilike":        bql.ILike,
+	"not ilike":    bql.NotILike,
+	"contains":     bql.Contains,
+	"not contains": bql.NotContains,
+	"is null":      bql.Eq,
+	"is not null":  bql.NotEq,
+}
+
+// filterToPredicate converts a Filter to a bql condition.
+func filterToPredicate(f Filter) (bql.Cond, error) {
+	operator := strings.ToLower(strings.TrimSpace(f.Operator))
+	op, ok := filterOps[operator]
+	if !ok {
+		return bql.Cond{}, fmt.Errorf("%w: unsupported operator: %s", ErrInvalidInput, f.Operator)
+	}
+
+	value := f.Value
+	if strings.HasPrefix(operator, "is ") {
+		value = nil
+	}
+
+	return bql.Cond{Field: f.Field, Op: op, Value: value}, nil
+}
+
+// listByCursor fetches one keyset page of entity for a CursorInput.
+func listByCursor(
+	ctx context.Context,
+	client *evateamclient.Client,
+	entity string,
+	kwargs map[string]any,
+	input *CursorInput,
+	limit int,
+	operation string,
+) (*ListResult, error) {
+	var after *evateamclient.Cursor
+	if *input.Cursor != "" {
+		cursor, err := evateamclient.ParseCursor(*input.Cursor)
+		if err != nil {
+			return nil, WrapError(operation, fmt.Errorf("%w: %w", ErrInvalidInput, err))
+		}
+		after = cursor
+	}
+
+	rows, next, err := client.ListKeyset(ctx, entity+".list", kwargs, nil, after, limit)
+	if err != nil {
+		return nil, WrapError(operation, err)
 	}
+
+	result := &ListResult{Items: toAnySlice(rows)}
+	if next != nil {
+		result.HasMore = true
+		result.NextCursor = next.String()
+	}
+
+	return result, nil
 }
 
 // BuildKwargs converts QueryInput to raw kwargs map for EVA-specific operations.
@@ -105,7 +152,13 @@ func BuildKwargs(input *QueryInput) map[string]any {
 	if len(input.Filters) > 0 {
 		filters := make([][]any, 0, len(input.Filters))
 		for _, f := range input.Filters {
-			filters = append(filters, []any{f.Field, f.Operator, f.Value})
+			// Unknown operators are passed through for the server to judge.
+			cond, err := filterToPredicate(f)
+			if err != nil {
+				filters = append(filters, []any{f.Field, f.Operator, f.Value})
+				continue
+			}
+			filters = append(filters, []any{cond.Field, string(cond.Op), cond.Value})
 		}
 		if len(filters) == 1 {
 			kwargs["filter"] = filters[0]
@@ -181,3 +181,47 @@ func TestBuildKwargs_WithIncludeArchived(t *testing.T) {
 
 	assert.Equal(t, true, kwargs["include_archived"])
 }
+
+func TestBuildQuery_AllOperators(t *testing.T) {
+	tests := []struct {
+		operator string
+		value    any
+		expected []any
+	}{
+		{"=", "OPEN", []any{"status", "==", "OPEN"}},
+		{"<>", "OPEN", []any{"status", "!=", "OPEN"}},
+		{"IN", []any{"OPEN", "CLOSED"}, []any{"status", "IN", []any{"OPEN", "CLOSED"}}},
+		{"NOT IN", []any{"OPEN"}, []any{"status", "NOT IN", []any{"OPEN"}}},
+		{"not like", "%x%", []any{"status", "NOT LIKE", "%x%"}},
+		{"ILIKE", "%x%", []any{"status", "ILIKE", "%x%"}},
+		{"NOT ILIKE", "%x%", []any{"status", "NOT ILIKE", "%x%"}},
+		{"contains", "CmfPerson:1", []any{"status", "contains", "CmfPerson:1"}},
+		{"not contains", "CmfPerson:1", []any{"status", "not contains", "CmfPerson:1"}},
+		{"IS NULL", "ignored", []any{"status", "==", nil}},
+		{"is not null", nil, []any{"status", "!=", nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator, func(t *testing.T) {
+			input := &tools.QueryInput{
+				Filters: []tools.Filter{{Field: "status", Operator: tt.operator, Value: tt.value}},
+			}
+
+			qb, err := tools.BuildQuery(evateamclient.EntityTask, input)
+			require.NoError(t, err)
+			kwargs, err := qb.ToKwargs()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, kwargs["filter"])
+
+			assert.Equal(t, tt.expected, tools.BuildKwargs(input)["filter"])
+		})
+	}
+}
+
+func TestBuildQuery_UnsupportedOperator(t *testing.T) {
+	_, err := tools.BuildQuery(evateamclient.EntityTask, &tools.QueryInput{
+		Filters: []tools.Filter{{Field: "status", Operator: "~=", Value: "OPEN"}},
+	})
+
+	require.ErrorIs(t, err, tools.ErrInvalidInput)
+}
@@ -11,39 +11,36 @@ package tools
 import (
 	"errors"
 	"fmt"
-	"strings"
+
+	"github.com/raoptimus/evateamclient.go"
 )
 
 // Common error types for MCP tools.
+// API error kinds are the client's own, so errors.Is matches both.
 var (
-	ErrNotFound       = errors.New("resource not found")
-	ErrInvalidInput   = errors.New("invalid input")
-	ErrUnauthorized   = errors.New("unauthorized")
-	ErrForbidden      = errors.New("forbidden")
-	ErrInternalServer = errors.New("internal server error")
+	ErrNotFound          = evateamclient.ErrNotFound
+	ErrMethodNotFound    = evateamclient.ErrMethodNotFound
+	ErrInvalidInput      = errors.New("invalid input")
+	ErrUnauthorized      = evateamclient.ErrUnauthorized
+	ErrForbidden         = evateamclient.ErrForbidden
+	ErrRateLimited       = evateamclient.ErrRateLimited
+	ErrServerUnavailable = evateamclient.ErrServerUnavailable
+	ErrCircuitOpen       = evateamclient.ErrCircuitOpen
+	ErrInternalServer    = errors.New("internal server error")
 )
 
 // WrapError wraps an error with context for MCP response.
+// Client validation errors are additionally marked as ErrInvalidInput.
 func WrapError(operation string, err error) error {
 	if err == nil {
 		return nil
 	}
 
-	errMsg := err.Error()
-
-	// Check for common EVA RPC error patterns
-	switch {
-	case strings.Contains(errMsg, "not found"):
-		return fmt.Errorf("%s: %w", operation, ErrNotFound)
-	case strings.Contains(errMsg, "401") || strings.Contains(errMsg, "Unauthorized"):
-		return fmt.Errorf("%s: %w", operation, ErrUnauthorized)
-	case strings.Contains(errMsg, "403") || strings.Contains(errMsg, "Forbidden"):
-		return fmt.Errorf("%s: %w", operation, ErrForbidden)
-	case strings.Contains(errMsg, "validation") || strings.Contains(errMsg, "invalid"):
-		return fmt.Errorf("%s: %w: %s", operation, ErrInvalidInput, errMsg)
-	default:
-		return fmt.Errorf("%s: %w", operation, err)
+	if errors.Is(err, evateamclient.ErrValidation) && !errors.Is(err, ErrInvalidInput) {
+		return fmt.Errorf("%s: %w: %w", operation, ErrInvalidInput, err)
 	}
+
+	return fmt.Errorf("%s: %w", operation, err)
 }
 
 // FormatToolError formats error for MCP tool response.
@@ -55,10 +52,18 @@ func FormatToolError(err error) string {
 	switch {
 	case errors.Is(err, ErrNotFound):
 		return "Resource not found. Please check the ID or code and try again."
+	case errors.Is(err, ErrMethodNotFound):
+		return fmt.Sprintf("EVA Team API does not support this method, check the server version: %v", err)
 	case errors.Is(err, ErrUnauthorized):
 		return "Authentication failed. Please check EVA_API_TOKEN."
 	case errors.Is(err, ErrForbidden):
 		return "Access denied. You don't have permission for this operation."
+	case errors.Is(err, ErrRateLimited):
+		return "EVA Team API rate limit exceeded. Please retry later."
+	case errors.Is(err, ErrCircuitOpen):
+		return "EVA Team API is down (circuit breaker open), requests are paused. Please retry later."
+	case errors.Is(err, ErrServerUnavailable):
+		return "EVA Team API is temporarily unavailable. Please retry later."
 	case errors.Is(err, ErrInvalidInput):
 		return fmt.Sprintf("Invalid input: %v", err)
 	default:
@@ -9,13 +9,41 @@
 package tools_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	evateamclient "github.com/raoptimus/evateamclient.go"
 	"github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// clientError returns the error a real client produces for a response with
+// the given HTTP status and body.
+func clientError(t *testing.T, status int, body string) error {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := evateamclient.NewClient(&evateamclient.Config{
+		BaseURL:  srv.URL,
+		APIToken: "test-token",
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.Task(context.Background(), "TASK-1", nil)
+	require.Error(t, err)
+	return err
+}
+
 func TestWrapError_NilError(t *testing.T) {
 	err := tools.WrapError("test", nil)
 
@@ -23,7 +51,7 @@ func TestWrapError_NilError(t *testing.T) {
 }
 
 func TestWrapError_NotFoundError(t *testing.T) {
-	originalErr := errors.New("resource not found")
+	originalErr := clientError(t, http.StatusNotFound, "Not Found")
 
 	err := tools.WrapError("test_operation", originalErr)
 
@@ -31,8 +59,17 @@ func TestWrapError_NotFoundError(t *testing.T) {
 	assert.True(t, errors.Is(err, tools.ErrNotFound))
 }
 
+func TestWrapError_RPCErrorMessage_NotClassifiedByWording(t *testing.T) {
+	originalErr := clientError(t, http.StatusOK, `{"jsonrpc":"2.2","error":{"code":-32000,"message":"Task not found"}}`)
+
+	err := tools.WrapError("test_operation", originalErr)
+
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, tools.ErrNotFound))
+}
+
 func TestWrapError_UnauthorizedError(t *testing.T) {
-	originalErr := errors.New("401 Unauthorized")
+	originalErr := clientError(t, http.StatusUnauthorized, "Unauthorized")
 
 	err := tools.WrapError("test_operation", originalErr)
 
@@ -41,7 +78,7 @@ func TestWrapError_UnauthorizedError(t *testing.T) {
 }
 
 func TestWrapError_ForbiddenError(t *testing.T) {
-	originalErr := errors.New("403 Forbidden")
+	originalErr := clientError(t, http.StatusForbidden, "Forbidden")
 
 	err := tools.WrapError("test_operation", originalErr)
 
@@ -50,12 +87,32 @@ func TestWrapError_ForbiddenError(t *testing.T) {
 }
 
 func TestWrapError_ValidationError(t *testing.T) {
-	originalErr := errors.New("validation failed: invalid input")
+	originalErr := clientError(t, http.StatusOK, `{"jsonrpc":"2.2","error":{"code":-32602,"message":"bad kwargs"}}`)
 
 	err := tools.WrapError("test_operation", originalErr)
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, tools.ErrInvalidInput))
+	assert.True(t, errors.Is(err, evateamclient.ErrValidation))
+}
+
+func TestWrapError_KeepsAPIErrorDetails(t *testing.T) {
+	originalErr := clientError(t, http.StatusServiceUnavailable, "maintenance")
+
+	err := tools.WrapError("test_operation", originalErr)
+
+	var apiErr *evateamclient.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, "CmfTask.get", apiErr.Method)
+	assert.True(t, errors.Is(err, tools.ErrServerUnavailable))
+}
+
+func TestWrapError_UnclassifiedMessage_NotMatchedBySubstring(t *testing.T) {
+	err := tools.WrapError("test_operation", errors.New("401 Unauthorized: resource not found"))
+
+	assert.False(t, errors.Is(err, tools.ErrNotFound))
+	assert.False(t, errors.Is(err, tools.ErrUnauthorized))
 }
 
 func TestWrapError_GenericError(t *testing.T) {
@@ -75,15 +132,26 @@ func TestFormatToolError_NilError(t *testing.T) {
 }
 
 func TestFormatToolError_NotFoundError(t *testing.T) {
-	err := tools.WrapError("test", errors.New("not found"))
+	err := tools.WrapError("test", tools.ErrNotFound)
 
 	result := tools.FormatToolError(err)
 
 	assert.Contains(t, result, "not found")
 }
 
+func TestFormatToolError_MethodNotFoundError(t *testing.T) {
+	err := tools.WrapError("test", clientError(t, http.StatusOK,
+		`{"jsonrpc":"2.2","error":{"code":-32601,"message":"Method not found"}}`))
+
+	result := tools.FormatToolError(err)
+
+	assert.False(t, errors.Is(err, tools.ErrNotFound))
+	assert.NotContains(t, result, "Resource not found")
+	assert.Contains(t, result, "does not support this method")
+}
+
 func TestFormatToolError_UnauthorizedError(t *testing.T) {
-	err := tools.WrapError("test", errors.New("401 Unauthorized"))
+	err := tools.WrapError("test", clientError(t, http.StatusUnauthorized, "Unauthorized"))
 
 	result := tools.FormatToolError(err)
 
@@ -91,15 +159,42 @@ func TestFormatToolError_UnauthorizedError(t *testing.T) {
 }
 
 func TestFormatToolError_ForbiddenError(t *testing.T) {
-	err := tools.WrapError("test", errors.New("403 Forbidden"))
+	err := tools.WrapError("test", clientError(t, http.StatusForbidden, "Forbidden"))
 
 	result := tools.FormatToolError(err)
 
 	assert.Contains(t, result, "Access denied")
 }
 
+func TestFormatToolError_RateLimitedError(t *testing.T) {
+	err := tools.WrapError("test", clientError(t, http.StatusTooManyRequests, "slow down"))
+
+	result := tools.FormatToolError(err)
+
+	assert.Contains(t, result, "rate limit")
+}
+
+func TestFormatToolError_CircuitOpenError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := evateamclient.NewClient(
+		&evateamclient.Config{BaseURL: srv.URL, APIToken: "test-token"},
+		evateamclient.WithCircuitBreaker(evateamclient.CircuitBreakerConfig{FailureThreshold: 1}),
+	)
+	require.NoError(t, err)
+	_, _, _ = client.Task(context.Background(), "TASK-1", nil)
+	_, _, err = client.Task(context.Background(), "TASK-1", nil)
+
+	result := tools.FormatToolError(tools.WrapError("test", err))
+
+	assert.Contains(t, result, "circuit breaker open")
+}
+
 func TestFormatToolError_InvalidInputError(t *testing.T) {
-	err := tools.WrapError("test", errors.New("validation error"))
+	err := tools.WrapError("test", fmt.Errorf("bad value: %w", evateamclient.ErrValidation))
 
 	result := tools.FormatToolError(err)
 
@@ -0,0 +1,266 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+// NotepadTools provides MCP tool handlers for personal notepads.
+type NotepadTools struct {
+	client *evateamclient.Client
+}
+
+// NewNotepadTools creates a new NotepadTools instance.
+func NewNotepadTools(client *evateamclient.Client) *NotepadTools {
+	return &NotepadTools{client: client}
+}
+
+// NotepadView is a notepad with its text also rendered as Markdown.
+type NotepadView struct {
+	*models.Notepad
+	Markdown string `json:"markdown,omitempty"`
+}
+
+func newNotepadView(note *models.Notepad) *NotepadView {
+	return &NotepadView{Notepad: note, Markdown: evateamclient.HTMLToMarkdown(note.Text)}
+}
+
+// notepadHTML converts text of the given format ("markdown" by default or
+// "html") to the HTML stored by EVA.
+func notepadHTML(text, format string) (string, error) {
+	switch format {
+	case "", "markdown":
+		return evateamclient.MarkdownToHTML(text), nil
+	case "html":
+		return text, nil
+	default:
+		return "", fmt.Errorf("%w: format %q is neither markdown nor html", ErrInvalidInput, format)
+	}
+}
+
+// NotepadListInput represents input for eva_notepad_list tool.
+type NotepadListInput struct {
+	QueryInput
+
+	// Filter by the object the notes are attached to
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// NotepadList returns a list of notepads.
+func (n *NotepadTools) NotepadList(ctx context.Context, input *NotepadListInput) (*ListResult, error) {
+	qb, err := BuildQuery(evateamclient.EntityNotepad, &input.QueryInput)
+	if err != nil {
+		return nil, WrapError("notepad_list", err)
+	}
+
+	if input.ParentID != "" {
+		qb = qb.Where(sq.Eq{evateamclient.NotepadFieldParentID: input.ParentID})
+	}
+
+	// Default order by creation time descending
+	if len(input.OrderBy) == 0 {
+		qb = qb.OrderBy("-" + evateamclient.NotepadFieldCmfCreatedAt)
+	}
+
+	notes, _, err := n.client.NotepadsList(ctx, qb)
+	if err != nil {
+		return nil, WrapError("notepad_list", err)
+	}
+
+	return &ListResult{
+		Items:   toAnySlice(notes),
+		HasMore: len(notes) == input.Limit && input.Limit > 0,
+	}, nil
+}
+
+// NotepadGetInput represents input for eva_notepad_get tool.
+type NotepadGetInput struct {
+	// Notepad ID (e.g., "CmfNotepad:uuid") or code
+	ID string `json:"id"`
+
+	// Fields to return
+	Fields []string `json:"fields,omitempty"`
+}
+
+// NotepadGet retrieves a single notepad with its text as Markdown.
+func (n *NotepadTools) NotepadGet(ctx context.Context, input *NotepadGetInput) (any, error) {
+	if input.ID == "" {
+		return nil, WrapError("notepad_get", ErrInvalidInput)
+	}
+
+	note, _, err := n.client.Notepad(ctx, input.ID, input.Fields)
+	if err != nil {
+		return nil, WrapError("notepad_get", err)
+	}
+
+	return newNotepadView(note), nil
+}
+
+// NotepadCreateInput represents input for eva_notepad_create tool.
+type NotepadCreateInput struct {
+	Text     string `json:"text"`
+	Format   string `json:"format,omitempty"` // markdown (default) or html
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// NotepadCreate creates a new notepad.
+func (n *NotepadTools) NotepadCreate(ctx context.Context, input *NotepadCreateInput) (any, error) {
+	if input.Text == "" {
+		return nil, WrapError("notepad_create", ErrInvalidInput)
+	}
+
+	text, err := notepadHTML(input.Text, input.Format)
+	if err != nil {
+		return nil, WrapError("notepad_create", err)
+	}
+
+	note, err := n.client.NotepadCreate(ctx, &evateamclient.NotepadCreateParams{
+		Text:     text,
+		ParentID: input.ParentID,
+	})
+	if err != nil {
+		return nil, WrapError("notepad_create", err)
+	}
+
+	return newNotepadView(note), nil
+}
+
+// NotepadUpdateInput represents input for eva_notepad_update tool.
+type NotepadUpdateInput struct {
+	ID     string `json:"id"`
+	Text   string `json:"text"`
+	Format string `json:"format,omitempty"` // markdown (default) or html
+}
+
+// NotepadUpdate replaces the text of a notepad.
+func (n *NotepadTools) NotepadUpdate(ctx context.Context, input NotepadUpdateInput) (any, error) {
+	if input.ID == "" {
+		return nil, WrapError("notepad_update", ErrInvalidInput)
+	}
+
+	text, err := notepadHTML(input.Text, input.Format)
+	if err != nil {
+		return nil, WrapError("notepad_update", err)
+	}
+
+	note, err := n.client.NotepadUpdate(ctx, input.ID, map[string]any{
+		evateamclient.NotepadFieldText: text,
+	})
+	if err != nil {
+		return nil, WrapError("notepad_update", err)
+	}
+
+	return newNotepadView(note), nil
+}
+
+// NotepadDeleteInput represents input for eva_notepad_delete tool.
+type NotepadDeleteInput struct {
+	ID string `json:"id"`
+}
+
+// NotepadDelete deletes a notepad.
+func (n *NotepadTools) NotepadDelete(ctx context.Context, input NotepadDeleteInput) (any, error) {
+	if input.ID == "" {
+		return nil, WrapError("notepad_delete", ErrInvalidInput)
+	}
+
+	if err := n.client.NotepadDelete(ctx, input.ID); err != nil {
+		return nil, WrapError("notepad_delete", err)
+	}
+
+	return map[string]bool{"success": true}, nil
+}
+
+// NotepadCountInput represents input for eva_notepad_count tool.
+type NotepadCountInput struct {
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// NotepadCount counts notepads.
+func (n *NotepadTools) NotepadCount(ctx context.Context, input NotepadCountInput) (*CountResult, error) {
+	qb := evateamclient.NewQueryBuilder().From(evateamclient.EntityNotepad)
+
+	if input.ParentID != "" {
+		qb = qb.Where(sq.Eq{evateamclient.NotepadFieldParentID: input.ParentID})
+	}
+
+	count, err := n.client.NotepadCount(ctx, qb)
+	if err != nil {
+		return nil, WrapError("notepad_count", err)
+	}
+
+	return &CountResult{Count: count}, nil
+}
+
+// NotepadAppendTodayInput represents input for eva_notepad_append_today tool.
+type NotepadAppendTodayInput struct {
+	// Person whose note it is, the owner of the API token (CmfPerson:UUID).
+	// Any other person fails once a note is created, see NotepadAppendDay.
+	PersonID string `json:"person_id"`
+
+	// Markdown to append
+	Text string `json:"text"`
+
+	// Day of the note as YYYY-MM-DD, today by default
+	Date string `json:"date,omitempty"`
+
+	// IANA time zone of the day (e.g., "Europe/Moscow"), the server's by default
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// NotepadAppendTodayResult is the result of eva_notepad_append_today tool.
+type NotepadAppendTodayResult struct {
+	*NotepadView
+
+	// False when the note already contained the text
+	Appended bool `json:"appended"`
+}
+
+// NotepadAppendToday appends Markdown to the note of the day, creating it
+// if needed. Appending text the note already contains changes nothing.
+func (n *NotepadTools) NotepadAppendToday(
+	ctx context.Context,
+	input *NotepadAppendTodayInput,
+) (*NotepadAppendTodayResult, error) {
+	if input.PersonID == "" || input.Text == "" {
+		return nil, WrapError("notepad_append_today", ErrInvalidInput)
+	}
+
+	loc := time.Local
+	if input.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(input.Timezone); err != nil {
+			return nil, WrapError("notepad_append_today",
+				fmt.Errorf("%w: unknown timezone %q", ErrInvalidInput, input.Timezone))
+		}
+	}
+
+	day := time.Now().In(loc)
+	if input.Date != "" {
+		var err error
+		if day, err = time.ParseInLocation(time.DateOnly, input.Date, loc); err != nil {
+			return nil, WrapError("notepad_append_today",
+				fmt.Errorf("%w: date %q is not YYYY-MM-DD", ErrInvalidInput, input.Date))
+		}
+	}
+
+	note, appended, err := n.client.NotepadAppendDay(ctx, input.PersonID, day, input.Text)
+	if err != nil {
+		return nil, WrapError("notepad_append_today", err)
+	}
+
+	return &NotepadAppendTodayResult{NotepadView: newNotepadView(note), Appended: appended}, nil
+}
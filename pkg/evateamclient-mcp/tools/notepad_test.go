@@ -0,0 +1,151 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	evateamclient "github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notepadCall struct {
+	Method string         `json:"method"`
+	Kwargs map[string]any `json:"kwargs"`
+}
+
+// newNotepadServer creates an httptest.Server answering the methods with the
+// responses in order and recording the requests it receives.
+func newNotepadServer(t *testing.T, responses ...string) (*tools.NotepadTools, *[]notepadCall) {
+	t.Helper()
+
+	var calls []notepadCall
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var call notepadCall
+		_ = json.Unmarshal(body, &call)
+		calls = append(calls, call)
+
+		if len(calls) > len(responses) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[len(calls)-1]))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := evateamclient.NewClient(&evateamclient.Config{
+		BaseURL:  srv.URL,
+		APIToken: "test-token",
+	})
+	require.NoError(t, err)
+	return tools.NewNotepadTools(client), &calls
+}
+
+func TestNotepadGet_ReturnsMarkdown(t *testing.T) {
+	nt, _ := newNotepadServer(t,
+		`{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1","text":"<h1>Plan</h1><ul><li>review</li></ul>"}}`)
+
+	result, err := nt.NotepadGet(context.Background(), &tools.NotepadGetInput{ID: "CmfNotepad:1"})
+
+	require.NoError(t, err)
+	view, ok := result.(*tools.NotepadView)
+	require.True(t, ok)
+	assert.Equal(t, "# Plan\n\n- review", view.Markdown)
+}
+
+func TestNotepadCreate_ConvertsMarkdown(t *testing.T) {
+	nt, calls := newNotepadServer(t, `{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1"}}`)
+
+	_, err := nt.NotepadCreate(context.Background(), &tools.NotepadCreateInput{Text: "**hi**"})
+
+	require.NoError(t, err)
+	require.Len(t, *calls, 1)
+	assert.Equal(t, "<p><strong>hi</strong></p>", (*calls)[0].Kwargs["text"])
+}
+
+func TestNotepadCreate_InvalidFormat(t *testing.T) {
+	nt, _ := newNotepadServer(t)
+
+	_, err := nt.NotepadCreate(context.Background(), &tools.NotepadCreateInput{Text: "hi", Format: "rtf"})
+
+	assert.ErrorIs(t, err, tools.ErrInvalidInput)
+}
+
+func TestNotepadAppendToday_CreatesNoteForDay(t *testing.T) {
+	nt, calls := newNotepadServer(t,
+		`{"jsonrpc":"2.2","result":[]}`,
+		`{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1","text":"<p>standup</p>","cmf_owner_id":"CmfPerson:1"}}`,
+	)
+
+	result, err := nt.NotepadAppendToday(context.Background(), &tools.NotepadAppendTodayInput{
+		PersonID: "CmfPerson:1",
+		Text:     "standup",
+		Date:     "2026-10-17",
+		Timezone: "Europe/Moscow",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Appended)
+	assert.Equal(t, "standup", result.Markdown)
+	require.Len(t, *calls, 2)
+	assert.Equal(t, []any{
+		[]any{"parent_id", "==", nil},
+		[]any{"cmf_owner_id", "==", "CmfPerson:1"},
+		[]any{"cmf_created_at", ">=", "2026-10-16T21:00:00Z"},
+		[]any{"cmf_created_at", "<", "2026-10-17T21:00:00Z"},
+	}, (*calls)[0].Kwargs["filter"])
+	assert.Equal(t, "CmfNotepad.create", (*calls)[1].Method)
+}
+
+func TestNotepadAppendToday_AlreadyAppended(t *testing.T) {
+	nt, calls := newNotepadServer(t,
+		`{"jsonrpc":"2.2","result":[{"id":"CmfNotepad:1","text":"<p>standup</p><ul><li>review PR</li></ul>"}]}`)
+
+	result, err := nt.NotepadAppendToday(context.Background(), &tools.NotepadAppendTodayInput{PersonID: "CmfPerson:1", Text: "* review PR"})
+
+	require.NoError(t, err)
+	assert.False(t, result.Appended)
+	assert.Len(t, *calls, 1, "no update when the note already contains the text")
+}
+
+func TestNotepadAppendToday_InvalidInput(t *testing.T) {
+	nt, _ := newNotepadServer(t)
+
+	_, err := nt.NotepadAppendToday(context.Background(), &tools.NotepadAppendTodayInput{PersonID: "CmfPerson:1", Text: "x", Date: "17.10.2026"})
+	assert.ErrorIs(t, err, tools.ErrInvalidInput)
+
+	_, err = nt.NotepadAppendToday(context.Background(), &tools.NotepadAppendTodayInput{PersonID: "CmfPerson:1", Text: "x", Timezone: "Mars/Olympus"})
+	assert.ErrorIs(t, err, tools.ErrInvalidInput)
+
+	_, err = nt.NotepadAppendToday(context.Background(), &tools.NotepadAppendTodayInput{Text: "x"})
+	assert.ErrorIs(t, err, tools.ErrInvalidInput)
+}
+
+func TestNotepadAppendToday_PrefixOfExistingLineIsAppended(t *testing.T) {
+	nt, calls := newNotepadServer(t,
+		`{"jsonrpc":"2.2","result":[{"id":"CmfNotepad:1","text":"<ul><li>review PR</li></ul>"}]}`,
+		`{"jsonrpc":"2.2","result":{"id":"CmfNotepad:1","text":"<ul><li>review PR</li></ul><ul><li>review</li></ul>"}}`,
+	)
+
+	result, err := nt.NotepadAppendToday(context.Background(), &tools.NotepadAppendTodayInput{PersonID: "CmfPerson:1", Text: "- review"})
+
+	require.NoError(t, err)
+	assert.True(t, result.Appended)
+	require.Len(t, *calls, 2)
+	assert.Equal(t, "<ul><li>review PR</li></ul><ul><li>review</li></ul>", (*calls)[1].Kwargs["text"])
+}
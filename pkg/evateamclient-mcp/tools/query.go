@@ -0,0 +1,96 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/eql"
+)
+
+// QueryTools provides the MCP tool handler for query language searches.
+type QueryTools struct {
+	client *evateamclient.Client
+}
+
+// NewQueryTools creates a new QueryTools instance.
+func NewQueryTools(client *evateamclient.Client) *QueryTools {
+	return &QueryTools{client: client}
+}
+
+// QueryRunInput represents input for eva_query tool.
+type QueryRunInput struct {
+	// Query, e.g. "project = MOB AND status in (OPEN, IN_PROGRESS) ORDER BY -priority LIMIT 50"
+	Query string `json:"query"`
+
+	// Entity to search, CmfTask by default
+	Entity string `json:"entity,omitempty"`
+
+	// Fields to return (projection)
+	Fields StringList `json:"fields,omitempty"`
+}
+
+// maxQueryLimit caps the LIMIT of a query, so that one tool call cannot
+// pull a whole EVA instance into the model context.
+const maxQueryLimit = 1000
+
+// QueryRun compiles a query language expression and lists the matches.
+// Without LIMIT at most 100 items are returned, and never more than 1000.
+func (t *QueryTools) QueryRun(ctx context.Context, input *QueryRunInput) (*ListResult, error) {
+	entity := input.Entity
+	if entity == "" {
+		entity = evateamclient.EntityTask
+	}
+
+	qb, err := eql.Compile(ctx, t.client, entity, input.Query)
+	if err != nil {
+		var qerr *eql.Error
+		if errors.As(err, &qerr) {
+			err = fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+		return nil, WrapError("query", err)
+	}
+
+	switch {
+	case len(input.Fields) > 0:
+		qb.Select(input.Fields...)
+	case entity == evateamclient.EntityTask:
+		qb.Select(evateamclient.DefaultTaskListFields...)
+	}
+
+	kwargs, err := qb.ToKwargs()
+	if err != nil {
+		return nil, WrapError("query", err)
+	}
+	limit := 0
+	if slice, ok := kwargs["slice"].([]uint64); ok {
+		limit = int(slice[1] - slice[0])
+	}
+	switch {
+	case limit == 0:
+		limit = defaultPaginationLimit
+		qb.Limit(defaultPaginationLimit)
+	case limit > maxQueryLimit:
+		limit = maxQueryLimit
+		qb.Limit(maxQueryLimit)
+	}
+
+	items, _, err := evateamclient.List[map[string]any](ctx, t.client, qb)
+	if err != nil {
+		return nil, WrapError("query", err)
+	}
+
+	return &ListResult{
+		Items:   toAnySlice(items),
+		HasMore: len(items) == limit,
+	}, nil
+}
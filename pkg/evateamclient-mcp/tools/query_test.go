@@ -0,0 +1,96 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	evateamclient "github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueryServer(t *testing.T) (*tools.QueryTools, map[string]map[string]any) {
+	t.Helper()
+
+	requests := make(map[string]map[string]any)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		method := r.URL.Query().Get("m")
+		var req struct {
+			Kwargs map[string]any `json:"kwargs"`
+		}
+		_ = json.Unmarshal(body, &req)
+		requests[method] = req.Kwargs
+
+		w.Header().Set("Content-Type", "application/json")
+		switch method {
+		case "CmfPerson.get":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":{"id":"CmfPerson:1"}}`))
+		case "CmfTask.list":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":[{"id":"CmfTask:1","code":"MOB-1"}]}`))
+		default:
+			http.Error(w, "unexpected call: "+method, http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := evateamclient.NewClient(&evateamclient.Config{BaseURL: srv.URL, APIToken: "test"})
+	require.NoError(t, err)
+
+	return tools.NewQueryTools(client), requests
+}
+
+func TestQueryRun_CompilesAndLists(t *testing.T) {
+	qt, requests := newQueryServer(t)
+
+	result, err := qt.QueryRun(context.Background(), &tools.QueryRunInput{
+		Query: "responsible = @ivanov AND status != CLOSED ORDER BY -priority",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.False(t, result.HasMore)
+
+	list := requests["CmfTask.list"]
+	assert.Equal(t, []any{
+		[]any{"responsible_id", "==", "CmfPerson:1"},
+		[]any{"cache_status_type", "!=", "CLOSED"},
+	}, list["filter"])
+	assert.Equal(t, []any{"-priority"}, list["order_by"])
+	assert.Equal(t, []any{float64(0), float64(100)}, list["slice"], "default limit")
+	assert.Contains(t, list["fields"], "code")
+}
+
+func TestQueryRun_LimitIsCapped(t *testing.T) {
+	qt, requests := newQueryServer(t)
+
+	_, err := qt.QueryRun(context.Background(), &tools.QueryRunInput{
+		Query: "status = OPEN LIMIT 1000000",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []any{float64(0), float64(1000)}, requests["CmfTask.list"]["slice"])
+}
+
+func TestQueryRun_SyntaxErrorIsInvalidInput(t *testing.T) {
+	qt, requests := newQueryServer(t)
+
+	_, err := qt.QueryRun(context.Background(), &tools.QueryRunInput{Query: "status = OPEN AND"})
+
+	require.ErrorIs(t, err, tools.ErrInvalidInput)
+	assert.Contains(t, tools.FormatToolError(err), "column 18")
+	assert.Empty(t, requests)
+}
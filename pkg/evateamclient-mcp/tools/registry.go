@@ -16,6 +16,7 @@ import (
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/raoptimus/evateamclient.go"
+	"go.opentelemetry.io/otel/codes"
 )
 
 func boolPtr(b bool) *bool { return &b }
@@ -46,40 +47,59 @@ type Registry struct {
 	List          *ListTools
 	Document      *DocumentTools
 	Person        *PersonTools
+	Company       *CompanyTools
+	Notepad       *NotepadTools
 	TimeLog       *TimeLogTools
 	Comment       *CommentTools
+	Attachment    *AttachmentTools
 	Epic          *EpicTools
 	TaskLink      *TaskLinkTools
 	StatusHistory *StatusHistoryTools
+	Audit         *AuditTools
 	Stats         *StatsTools
 	LogicType     *LogicTypeTools
 	Tag           *TagTools
+	Query         *QueryTools
 }
 
-// NewRegistry creates a new Registry with all tools initialized.
-func NewRegistry(client *evateamclient.Client) *Registry {
+// NewRegistry creates a new Registry with all tools initialized. fileRoot
+// is the only directory attachment uploads and downloads may name by path;
+// with an empty fileRoot the path-based attachment tools are refused and
+// only base64 content is accepted.
+func NewRegistry(client *evateamclient.Client, fileRoot string) *Registry {
 	return &Registry{
 		Task:          NewTaskTools(client),
 		Project:       NewProjectTools(client),
 		List:          NewListTools(client),
 		Document:      NewDocumentTools(client),
 		Person:        NewPersonTools(client),
+		Company:       NewCompanyTools(client),
+		Notepad:       NewNotepadTools(client),
 		TimeLog:       NewTimeLogTools(client),
 		Comment:       NewCommentTools(client),
+		Attachment:    NewAttachmentTools(client, fileRoot),
 		Epic:          NewEpicTools(client),
 		TaskLink:      NewTaskLinkTools(client),
 		StatusHistory: NewStatusHistoryTools(client),
+		Audit:         NewAuditTools(client),
 		Stats:         NewStatsTools(client),
 		LogicType:     NewLogicTypeTools(client),
 		Tag:           NewTagTools(client),
+		Query:         NewQueryTools(client),
 	}
 }
 
 // wrapHandler wraps a typed handler function to work with MCP's generic interface.
-func wrapHandler[In, Out any](handler func(context.Context, In) (Out, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, Out, error) {
+// Every call runs in its own tool span (see startToolSpan).
+func wrapHandler[In, Out any](name string, handler func(context.Context, In) (Out, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, Out, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, Out, error) {
+		ctx, span := startToolSpan(ctx, req, name)
+		defer span.End()
+
 		result, err := handler(ctx, args)
 		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
 			var zero Out
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
@@ -126,7 +146,7 @@ func addTool[In, Out any](
 	if tool.InputSchema == nil {
 		tool.InputSchema = relaxedInputSchema[In]()
 	}
-	mcp.AddTool(server, tool, wrapHandler(handler))
+	mcp.AddTool(server, tool, wrapHandler(tool.Name, handler))
 }
 
 // relaxedInputSchema builds the JSON schema for the input type In and relaxes
@@ -151,7 +171,7 @@ func (r *Registry) RegisterAll(server *mcp.Server) {
 	// Task tools
 	addTool(server, &mcp.Tool{
 		Name:        "eva_task_list",
-		Description: "List tasks with optional filters (project, status, sprint, responsible)",
+		Description: "List tasks with optional filters (project, status, sprint, responsible). Pass cursor \"\" and then next_cursor to page stably through large results",
 		Annotations: readOnlyAnnotations,
 	}, r.Task.TaskList)
 
@@ -416,10 +436,107 @@ func (r *Registry) RegisterAll(server *mcp.Server) {
 		Annotations: readOnlyAnnotations,
 	}, r.Person.PersonCount)
 
+	// Company tools
+	addTool(server, &mcp.Tool{
+		Name:        "eva_company_list",
+		Description: "List companies (contractors, customers)",
+		Annotations: readOnlyAnnotations,
+	}, r.Company.CompanyList)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_company_get",
+		Description: "Get a single company by ID or code",
+		Annotations: readOnlyAnnotations,
+	}, r.Company.CompanyGet)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_company_create",
+		Description: "Create a new company (name, optional inn, kpp, ogrn)",
+		Annotations: writeAnnotations,
+	}, r.Company.CompanyCreate)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_company_update",
+		Description: "Update an existing company (name, inn, kpp, ogrn, cmf_owner)",
+		Annotations: idempotentWriteAnnotations,
+	}, r.Company.CompanyUpdate)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_company_delete",
+		Description: "Delete a company",
+		Annotations: destructiveAnnotations,
+	}, r.Company.CompanyDelete)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_company_count",
+		Description: "Count companies",
+		Annotations: readOnlyAnnotations,
+	}, r.Company.CompanyCount)
+
+	addTool(server, &mcp.Tool{
+		Name: "eva_company_persons",
+		Description: "List the persons linked to a company. The linking field is taken from the server's " +
+			"CmfPerson schema; fails if the schema has no field referencing CmfCompany",
+		Annotations: readOnlyAnnotations,
+	}, r.Company.CompanyPersons)
+
+	addTool(server, &mcp.Tool{
+		Name: "eva_company_projects",
+		Description: "List the projects linked to a company. The linking field is taken from the server's " +
+			"CmfProject schema; fails if the schema has no field referencing CmfCompany",
+		Annotations: readOnlyAnnotations,
+	}, r.Company.CompanyProjects)
+
+	// Notepad tools
+	addTool(server, &mcp.Tool{
+		Name:        "eva_notepad_list",
+		Description: "List personal notepads (notes), newest first; text is not included unless requested in fields",
+		Annotations: readOnlyAnnotations,
+	}, r.Notepad.NotepadList)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_notepad_get",
+		Description: "Get a single notepad by ID or code, with its HTML text also rendered as markdown",
+		Annotations: readOnlyAnnotations,
+	}, r.Notepad.NotepadGet)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_notepad_create",
+		Description: "Create a notepad; text is markdown unless format is \"html\", parent_id attaches it to an object",
+		Annotations: writeAnnotations,
+	}, r.Notepad.NotepadCreate)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_notepad_update",
+		Description: "Replace the text of a notepad; text is markdown unless format is \"html\"",
+		Annotations: idempotentWriteAnnotations,
+	}, r.Notepad.NotepadUpdate)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_notepad_delete",
+		Description: "Delete a notepad",
+		Annotations: destructiveAnnotations,
+	}, r.Notepad.NotepadDelete)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_notepad_count",
+		Description: "Count notepads",
+		Annotations: readOnlyAnnotations,
+	}, r.Notepad.NotepadCount)
+
+	addTool(server, &mcp.Tool{
+		Name: "eva_notepad_append_today",
+		Description: "Append markdown to today's note of person_id (the first notepad of that person " +
+			"without parent created that day), creating the note if needed. person_id is required and must be " +
+			"the owner of the API token, otherwise the created note is deleted and the call fails. Idempotent: text the note already contains is not appended again. " +
+			"date (YYYY-MM-DD) and timezone (IANA) select another day",
+		Annotations: idempotentWriteAnnotations,
+	}, r.Notepad.NotepadAppendToday)
+
 	// TimeLog tools
 	addTool(server, &mcp.Tool{
 		Name:        "eva_timelog_list",
-		Description: "List time log entries",
+		Description: "List time log entries. Pass cursor \"\" and then next_cursor to page stably through large results",
 		Annotations: readOnlyAnnotations,
 	}, r.TimeLog.TimeLogList)
 
@@ -490,6 +607,35 @@ func (r *Registry) RegisterAll(server *mcp.Server) {
 		Annotations: readOnlyAnnotations,
 	}, r.Comment.CommentCount)
 
+	// Attachment tools
+	addTool(server, &mcp.Tool{
+		Name:        "eva_attachment_list",
+		Description: "List files attached to a task, comment or document (parent_id), oldest first",
+		Annotations: readOnlyAnnotations,
+	}, r.Attachment.AttachmentList)
+
+	addTool(server, &mcp.Tool{
+		Name: "eva_attachment_upload",
+		Description: "Attach a file to a task, comment or document (parent_id). " +
+			"Pass either content_base64 with name, or path of a file in the server's file root, " +
+			"relative to it (name defaults to its base name; unavailable if the server has no file root)",
+		Annotations: writeAnnotations,
+	}, r.Attachment.AttachmentUpload)
+
+	addTool(server, &mcp.Tool{
+		Name: "eva_attachment_download",
+		Description: "Download an attachment by ID. With path the file is saved there, in the server's file root " +
+			"(an existing file is kept unless overwrite is set); without it the content is returned " +
+			"as content_base64, for files up to 10 MiB",
+		Annotations: writeAnnotations,
+	}, r.Attachment.AttachmentDownload)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_attachment_delete",
+		Description: "Delete an attachment",
+		Annotations: destructiveAnnotations,
+	}, r.Attachment.AttachmentDelete)
+
 	// Epic tools
 	addTool(server, &mcp.Tool{
 		Name:        "eva_epic_list",
@@ -563,6 +709,28 @@ func (r *Registry) RegisterAll(server *mcp.Server) {
 		Annotations: readOnlyAnnotations,
 	}, r.StatusHistory.StatusHistoryCount)
 
+	// Audit tools
+	addTool(server, &mcp.Tool{
+		Name: "eva_audit_list",
+		Description: "List audit log records: who changed which field of which object, old/new values and when. " +
+			"object_id gives the change history of an object; person_id with date_from/date_to " +
+			"everything a person changed in a period (YYYY-MM-DD with date_to inclusive, or RFC 3339). " +
+			"Newest first unless order_by is set; pass cursor \"\" to page through large results",
+		Annotations: readOnlyAnnotations,
+	}, r.Audit.AuditList)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_audit_get",
+		Description: "Get a single audit log record by ID",
+		Annotations: readOnlyAnnotations,
+	}, r.Audit.AuditGet)
+
+	addTool(server, &mcp.Tool{
+		Name:        "eva_audit_count",
+		Description: "Count audit log records by object_id, person_id and date_from/date_to",
+		Annotations: readOnlyAnnotations,
+	}, r.Audit.AuditCount)
+
 	// Stats tools
 	addTool(server, &mcp.Tool{
 		Name:        "eva_stats_project",
@@ -607,4 +775,15 @@ func (r *Registry) RegisterAll(server *mcp.Server) {
 		Description: "List tags available for tasks. Returns tag code (e.g. 'TAG-000004') and name/aliases. Use tag code in the tags field of eva_task_create. Filter by project_id or name.",
 		Annotations: readOnlyAnnotations,
 	}, r.Tag.TagList)
+
+	// Query language
+	addTool(server, &mcp.Tool{
+		Name: "eva_query",
+		Description: "Search with a query language instead of filter JSON, e.g. " +
+			"'project = MOB AND status in (OPEN, IN_PROGRESS) AND responsible = @ivanov AND deadline < now()+7d ORDER BY -priority LIMIT 50'. " +
+			"Operators: = != < <= > >= [NOT] IN (...) [NOT] LIKE [NOT] ILIKE [NOT] CONTAINS, IS [NOT] NULL; AND, OR, NOT, parentheses. " +
+			"project/sprint/release take codes, responsible/executor/author take @login; dates: now(), today() +/- 30m, 2h, 7d, 1w. " +
+			"entity defaults to CmfTask; without LIMIT at most 100 items are returned, LIMIT is capped at 1000",
+		Annotations: readOnlyAnnotations,
+	}, r.Query.QueryRun)
 }
@@ -29,6 +29,7 @@ func NewTaskTools(client *evateamclient.Client) *TaskTools {
 // TaskListInput represents input for eva_task_list tool.
 type TaskListInput struct {
 	QueryInput
+	CursorInput
 
 	// Optional project filter
 	ProjectID string `json:"project_id,omitempty"`
@@ -51,52 +52,42 @@ type TaskListInput struct {
 
 // TaskList returns a list of tasks matching filters.
 func (t *TaskTools) TaskList(ctx context.Context, input *TaskListInput) (*ListResult, error) {
-	// Build kwargs for complex filters
-	kwargs := BuildKwargs(&input.QueryInput)
-
-	// Add specific filters
-	var filters [][]any
-	if existingFilter, ok := kwargs["filter"].([][]any); ok {
-		filters = existingFilter
-	} else {
-		if singleFilter, ok := kwargs["filter"].([]any); ok {
-			filters = [][]any{singleFilter}
-		}
+	qb, err := BuildQuery(evateamclient.EntityTask, &input.QueryInput)
+	if err != nil {
+		return nil, WrapError("task_list", err)
 	}
 
 	if input.ProjectID != "" {
-		filters = append(filters, []any{"project_id", "==", input.ProjectID})
+		qb = qb.Where(sq.Eq{evateamclient.TaskFieldProjectID: input.ProjectID})
 	}
 	if input.StatusType != "" {
-		filters = append(filters, []any{"cache_status_type", "==", input.StatusType})
+		qb = qb.Where(sq.Eq{evateamclient.TaskFieldCacheStatusType: input.StatusType})
 	}
 	if input.ResponsibleID != "" {
-		filters = append(filters, []any{"responsible_id", "==", input.ResponsibleID})
+		qb = qb.Where(sq.Eq{evateamclient.TaskFieldResponsibleID: input.ResponsibleID})
 	}
 	if input.LogicTypeID != "" {
-		filters = append(filters, []any{"logic_type_id", "==", input.LogicTypeID})
+		qb = qb.Where(sq.Eq{evateamclient.TaskFieldLogicTypeID: input.LogicTypeID})
 	}
 	if input.Code != "" {
-		filters = append(filters, []any{"code", "==", input.Code})
+		qb = qb.Where(sq.Eq{evateamclient.TaskFieldCode: input.Code})
 	}
-
-	// Sprint uses "contains" operator
 	if input.SprintCode != "" {
-		filters = append(filters, []any{"lists", "contains", input.SprintCode})
-	}
-
-	if len(filters) == 1 {
-		kwargs["filter"] = filters[0]
-	} else if len(filters) > 1 {
-		kwargs["filter"] = filters
+		qb = qb.Where(evateamclient.Contains(evateamclient.TaskFieldLists, input.SprintCode))
 	}
 
-	// Set default fields if not specified
-	if _, ok := kwargs["fields"]; !ok {
-		kwargs["fields"] = evateamclient.DefaultTaskListFields
+	if input.Cursor != nil {
+		kwargs, err := qb.ToKwargs()
+		if err != nil {
+			return nil, WrapError("task_list", err)
+		}
+		if _, ok := kwargs["fields"]; !ok {
+			kwargs["fields"] = evateamclient.DefaultTaskListFields
+		}
+		return listByCursor(ctx, t.client, evateamclient.EntityTask, kwargs, &input.CursorInput, input.Limit, "task_list")
 	}
 
-	tasks, _, err := t.client.Tasks(ctx, kwargs)
+	tasks, _, err := t.client.TasksList(ctx, qb)
 	if err != nil {
 		return nil, WrapError("task_list", err)
 	}
@@ -304,32 +295,25 @@ type TaskCountInput struct {
 
 // TaskCount counts tasks matching filters.
 func (t *TaskTools) TaskCount(ctx context.Context, input TaskCountInput) (*CountResult, error) {
-	kwargs := make(map[string]any)
-	var filters [][]any
+	qb := evateamclient.NewQueryBuilder().From(evateamclient.EntityTask)
 
 	if input.ProjectID != "" {
-		filters = append(filters, []any{"project_id", "==", input.ProjectID})
+		qb = qb.Where(sq.Eq{evateamclient.TaskFieldProjectID: input.ProjectID})
 	}
 	if input.StatusType != "" {
-		filters = append(filters, []any{"cache_status_type", "==", input.StatusType})
+		qb = qb.Where(sq.Eq{evateamclient.TaskFieldCacheStatusType: input.StatusType})
 	}
 	if input.ResponsibleID != "" {
-		filters = append(filters, []any{"responsible_id", "==", input.ResponsibleID})
+		qb = qb.Where(sq.Eq{evateamclient.TaskFieldResponsibleID: input.ResponsibleID})
 	}
 	if input.SprintCode != "" {
-		filters = append(filters, []any{"lists", "contains", input.SprintCode})
-	}
-
-	if len(filters) == 1 {
-		kwargs["filter"] = filters[0]
-	} else if len(filters) > 1 {
-		kwargs["filter"] = filters
+		qb = qb.Where(evateamclient.Contains(evateamclient.TaskFieldLists, input.SprintCode))
 	}
 
-	count, _, err := t.client.TasksCount(ctx, kwargs)
+	count, err := t.client.TaskCount(ctx, qb)
 	if err != nil {
 		return nil, WrapError("task_count", err)
 	}
 
-	return &CountResult{Count: int(count)}, nil
+	return &CountResult{Count: count}, nil
 }
@@ -0,0 +1,116 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	evateamclient "github.com/raoptimus/evateamclient.go"
+	"github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCursorTaskServer serves two task pages: the first request gets two
+// tasks, every later one a single task. It records the kwargs of each request.
+func newCursorTaskServer(t *testing.T) (*tools.TaskTools, *[]map[string]any) {
+	t.Helper()
+
+	var requests []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Kwargs map[string]any `json:"kwargs"`
+		}
+		_ = json.Unmarshal(body, &req)
+		requests = append(requests, req.Kwargs)
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(requests) == 1 {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":[
+				{"id":"CmfTask:1","code":"T-1","cmf_created_at":"2026-01-01T00:00:00Z"},
+				{"id":"CmfTask:2","code":"T-2","cmf_created_at":"2026-01-01T00:00:00Z"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.2","result":[
+			{"id":"CmfTask:3","code":"T-3","cmf_created_at":"2026-01-02T00:00:00Z"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := evateamclient.NewClient(&evateamclient.Config{
+		BaseURL:  srv.URL,
+		APIToken: "test",
+	})
+	require.NoError(t, err)
+
+	return tools.NewTaskTools(client), &requests
+}
+
+func TestTaskList_Cursor_Resume(t *testing.T) {
+	tt, requests := newCursorTaskServer(t)
+	start := ""
+
+	first, err := tt.TaskList(context.Background(), &tools.TaskListInput{
+		QueryInput:  tools.QueryInput{Limit: 2, Offset: 40, OrderBy: tools.StringList{"-priority"}},
+		CursorInput: tools.CursorInput{Cursor: &start},
+		ProjectID:   "CmfProject:1",
+	})
+	require.NoError(t, err)
+	require.Len(t, first.Items, 2)
+	require.True(t, first.HasMore)
+	require.NotEmpty(t, first.NextCursor)
+
+	assert.Equal(t, []any{float64(0), float64(2)}, (*requests)[0]["slice"], "offset is ignored")
+	assert.Equal(t, []any{"cmf_created_at", "id"}, (*requests)[0]["order_by"])
+	assert.Equal(t, []any{"project_id", "==", "CmfProject:1"}, (*requests)[0]["filter"])
+
+	second, err := tt.TaskList(context.Background(), &tools.TaskListInput{
+		QueryInput:  tools.QueryInput{Limit: 2},
+		CursorInput: tools.CursorInput{Cursor: &first.NextCursor},
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, second.Items, 2, "one task from each keyset branch")
+	assert.Equal(t, []any{
+		[]any{"cmf_created_at", "==", "2026-01-01T00:00:00Z"},
+		[]any{"id", ">", "CmfTask:2"},
+	}, (*requests)[1]["filter"])
+}
+
+func TestTaskList_Cursor_Invalid(t *testing.T) {
+	tt, requests := newCursorTaskServer(t)
+	bad := "not-a-cursor"
+
+	_, err := tt.TaskList(context.Background(), &tools.TaskListInput{
+		CursorInput: tools.CursorInput{Cursor: &bad},
+	})
+
+	require.ErrorIs(t, err, tools.ErrInvalidInput)
+	assert.Empty(t, *requests)
+}
+
+func TestTaskList_SprintCodeUsesContains(t *testing.T) {
+	tt, requests := newCursorTaskServer(t)
+
+	_, err := tt.TaskList(context.Background(), &tools.TaskListInput{
+		SprintCode: "SPR-001",
+		ProjectID:  "CmfProject:1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []any{
+		[]any{"project_id", "==", "CmfProject:1"},
+		[]any{"lists", "contains", "SPR-001"},
+	}, (*requests)[0]["filter"])
+}
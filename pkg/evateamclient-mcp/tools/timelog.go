@@ -28,6 +28,7 @@ func NewTimeLogTools(client *evateamclient.Client) *TimeLogTools {
 // TimeLogListInput represents input for eva_timelog_list tool.
 type TimeLogListInput struct {
 	QueryInput
+	CursorInput
 	TaskID    string `json:"task_id,omitempty"`
 	UserID    string `json:"user_id,omitempty"`
 	ProjectID string `json:"project_id,omitempty"`
@@ -40,6 +41,13 @@ func (t *TimeLogTools) TimeLogList(ctx context.Context, input *TimeLogListInput)
 		kwargs := BuildKwargs(&input.QueryInput)
 		kwargs["filter"] = []any{"parent.project_id", "==", input.ProjectID}
 
+		if input.Cursor != nil {
+			if _, ok := kwargs["fields"]; !ok {
+				kwargs["fields"] = evateamclient.DefaultTimeLogListFields
+			}
+			return listByCursor(ctx, t.client, evateamclient.EntityTimeLog, kwargs, &input.CursorInput, input.Limit, "timelog_list")
+		}
+
 		logs, _, err := t.client.TimeLogs(ctx, kwargs)
 		if err != nil {
 			return nil, WrapError("timelog_list", err)
@@ -63,6 +71,17 @@ func (t *TimeLogTools) TimeLogList(ctx context.Context, input *TimeLogListInput)
 		qb = qb.Where(sq.Eq{"cmf_owner_id": input.UserID})
 	}
 
+	if input.Cursor != nil {
+		kwargs, err := qb.ToKwargs()
+		if err != nil {
+			return nil, WrapError("timelog_list", err)
+		}
+		if _, ok := kwargs["fields"]; !ok {
+			kwargs["fields"] = evateamclient.DefaultTimeLogListFields
+		}
+		return listByCursor(ctx, t.client, evateamclient.EntityTimeLog, kwargs, &input.CursorInput, input.Limit, "timelog_list")
+	}
+
 	logs, _, err := t.client.TimeLogsList(ctx, qb)
 	if err != nil {
 		return nil, WrapError("timelog_list", err)
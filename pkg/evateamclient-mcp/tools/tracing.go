@@ -0,0 +1,35 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/raoptimus/evateamclient.go/pkg/evateamclient-mcp/tools"
+
+// startToolSpan opens the parent span of a tool call using the global tracer
+// provider. Over the HTTP transport the caller's trace context is continued
+// from the request headers.
+func startToolSpan(ctx context.Context, req *mcp.CallToolRequest, name string) (context.Context, trace.Span) {
+	if req != nil && req.Extra != nil && len(req.Extra.Header) > 0 {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(req.Extra.Header))
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, "mcp.tool "+name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String("mcp.tool.name", name)),
+	)
+}
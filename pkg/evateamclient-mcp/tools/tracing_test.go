@@ -0,0 +1,81 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func withSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	prevTP, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+	})
+
+	return recorder
+}
+
+func TestWrapHandler_OpensToolSpan(t *testing.T) {
+	recorder := withSpanRecorder(t)
+
+	var handlerSpan trace.SpanContext
+	handler := wrapHandler("eva_test", func(ctx context.Context, _ struct{}) (string, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return "ok", nil
+	})
+
+	_, _, err := handler(context.Background(), &mcp.CallToolRequest{}, struct{}{})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "mcp.tool eva_test", spans[0].Name())
+	assert.Equal(t, handlerSpan.SpanID(), spans[0].SpanContext().SpanID())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestWrapHandler_ToolSpan_RecordsErrorAndContinuesRemoteTrace(t *testing.T) {
+	recorder := withSpanRecorder(t)
+
+	handler := wrapHandler("eva_test", func(context.Context, struct{}) (string, error) {
+		return "", errors.New("boom")
+	})
+	req := &mcp.CallToolRequest{Extra: &mcp.RequestExtra{Header: http.Header{
+		"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	}}}
+
+	result, _, err := handler(context.Background(), req, struct{}{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
@@ -10,9 +10,11 @@ package evateamclient
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/raoptimus/evateamclient.go/bql"
 )
 
 // QueryBuilder wraps Squirrel's SelectBuilder and converts to EVA API kwargs
@@ -30,8 +32,14 @@ import (
 //	projects, meta, err := client.ProjectsList(ctx, qb)
 type QueryBuilder struct {
 	selectBuilder sq.SelectBuilder
+	where         []any
 	includeArch   bool
 	noMeta        bool
+	parallel      int
+	keyset        []string
+	after         *Cursor
+	schema        *Schema
+	groupBy       []string
 }
 
 // NewQueryBuilder creates a new EVA-compatible Squirrel builder
@@ -60,8 +68,9 @@ func (qb *QueryBuilder) From(table string) *QueryBuilder {
 	return qb
 }
 
-// Where adds filter conditions using Squirrel predicates
+// Where adds filter conditions using Squirrel predicates or bql expressions
 // Multiple Where() calls are combined with AND logic
+// Predicates bql.FromSquirrel does not support make ToKwargs fail.
 //
 // Examples:
 //
@@ -69,8 +78,10 @@ func (qb *QueryBuilder) From(table string) *QueryBuilder {
 //	qb.Where(sq.Gt{"priority": 3})
 //	qb.Where(sq.Like{"name": "%Mobile%"})
 //	qb.Where(sq.And{sq.Eq{"system": false}, sq.GtOrEq{"created_at": "2024-01-01"}})
+//	qb.Where(sq.Or{sq.Eq{"responsible_id": me}, sq.And{sq.Eq{"cmf_owner_id": me}, sq.Eq{"system": false}}})
+//	qb.Where(bql.Cond{Field: "priority", Op: bql.GtOrEq, Value: 3})
 func (qb *QueryBuilder) Where(pred any) *QueryBuilder {
-	qb.selectBuilder = qb.selectBuilder.Where(pred)
+	qb.where = append(qb.where, pred)
 	return qb
 }
 
@@ -115,6 +126,101 @@ func (qb *QueryBuilder) NoMeta() *QueryBuilder {
 	return qb
 }
 
+// clone returns a copy of qb that can be changed without affecting qb.
+func (qb *QueryBuilder) clone() *QueryBuilder {
+	cp := *qb
+	cp.where = slices.Clone(qb.where)
+	cp.groupBy = slices.Clone(qb.groupBy)
+	return &cp
+}
+
+// window returns the offset and limit set on qb; limit is 0 when unset.
+func (qb *QueryBuilder) window() (offset, limit uint64, err error) {
+	sqlStr, _, err := qb.safeBuilder().ToSql()
+	if err != nil {
+		return 0, 0, fmt.Errorf("squirrel.ToSql: %w", err)
+	}
+	parts, err := parseSquirrelSQL(sqlStr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return parts.offset, parts.limit, nil
+}
+
+// withoutWindow returns a copy of qb without offset and limit.
+func (qb *QueryBuilder) withoutWindow() *QueryBuilder {
+	cp := qb.clone()
+	cp.selectBuilder = cp.selectBuilder.RemoveOffset().RemoveLimit()
+	return cp
+}
+
+// withStableOrder returns a copy of qb whose order is total, so that slice
+// windows fetched at different times do not overlap: cmf_created_at, id when
+// qb has no order, otherwise its order with id as the tie-breaker.
+func (qb *QueryBuilder) withStableOrder() (*QueryBuilder, error) {
+	sqlStr, _, err := qb.safeBuilder().ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("squirrel.ToSql: %w", err)
+	}
+	parts, err := parseSquirrelSQL(sqlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := qb.clone()
+	switch {
+	case len(parts.orderBy) == 0:
+		cp.selectBuilder = cp.selectBuilder.OrderBy("cmf_created_at", "id")
+	case !slices.Contains(parts.orderBy, "id") && !slices.Contains(parts.orderBy, "-id"):
+		cp.selectBuilder = cp.selectBuilder.OrderBy("id")
+	}
+
+	return cp, nil
+}
+
+// Parallel makes iterators (IterTasks, Iter, ...) fetch the query in up to n
+// concurrent slice windows instead of page by page. It has no effect on the
+// single-page list methods.
+// Example: client.IterTimeLogs(ctx, qb.Parallel(8))
+func (qb *QueryBuilder) Parallel(n int) *QueryBuilder {
+	qb.parallel = n
+	return qb
+}
+
+// Keyset switches iterators and ListPage to keyset (cursor) pagination over
+// the given unique key tuple, DefaultKeysetKeys if none. Each page is
+// filtered to rows after the last row of the previous one instead of using
+// an offset, so rows created while paging are neither skipped nor repeated.
+// Offset and OrderBy are ignored in keyset mode. The keys must be immutable
+// and never null (see DefaultKeysetKeys).
+// Example: client.IterTasks(ctx, qb.Keyset("-cmf_created_at", "id"))
+func (qb *QueryBuilder) Keyset(keys ...string) *QueryBuilder {
+	if len(keys) == 0 {
+		keys = DefaultKeysetKeys
+	}
+	qb.keyset = keys
+	return qb
+}
+
+// After resumes keyset pagination after a cursor returned by ListPage or an
+// iterator (see OnCursor). It implies Keyset with the cursor keys.
+// Example: qb.After(cursor)
+func (qb *QueryBuilder) After(cursor *Cursor) *QueryBuilder {
+	qb.after = cursor
+	if cursor != nil && len(qb.keyset) == 0 {
+		qb.keyset = cursor.keys
+	}
+	return qb
+}
+
+func (qb *QueryBuilder) keysetKeys() []string {
+	if len(qb.keyset) == 0 {
+		return DefaultKeysetKeys
+	}
+	return qb.keyset
+}
+
 // ToKwargs converts Squirrel SelectBuilder to EVA API kwargs
 // This translates SQL-like queries to JSON-RPC BQL format
 //
@@ -123,24 +229,28 @@ func (qb *QueryBuilder) ToKwargs() (map[string]any, error) {
 	kwargs := make(map[string]any)
 
 	// Extract parts from Squirrel builder
-	sqlStr, args, err := qb.safeBuilder().ToSql()
+	sqlStr, _, err := qb.safeBuilder().ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("squirrel.ToSql: %w", err)
 	}
 
 	// Parse SQL to extract EVA components
-	parts, err := parseSquirrelSQL(sqlStr, args)
+	parts, err := parseSquirrelSQL(sqlStr)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert WHERE clause to EVA filter
-	if len(parts.filters) > 0 {
-		if len(parts.filters) == 1 {
-			kwargs["filter"] = parts.filters[0]
-		} else {
-			kwargs["filter"] = parts.filters
-		}
+	// Convert WHERE predicates to EVA filter
+	expr, err := qb.Filter()
+	if err != nil {
+		return nil, err
+	}
+	filter, err := bql.Filter(expr)
+	if err != nil {
+		return nil, err
+	}
+	if filter != nil {
+		kwargs["filter"] = filter
 	}
 
 	// Convert SELECT columns to EVA fields
@@ -170,6 +280,23 @@ func (qb *QueryBuilder) ToKwargs() (map[string]any, error) {
 	return kwargs, nil
 }
 
+// Filter returns the AND of the Where predicates as a bql expression.
+func (qb *QueryBuilder) Filter() (bql.Expr, error) {
+	and := make(bql.And, 0, len(qb.where))
+	for _, pred := range qb.where {
+		expr, err := bql.FromSquirrel(pred)
+		if err != nil {
+			return nil, fmt.Errorf("where: %w", err)
+		}
+		if err := checkRelationRanges(expr); err != nil {
+			return nil, fmt.Errorf("where: %w", err)
+		}
+		and = append(and, expr)
+	}
+
+	return and, nil
+}
+
 // ToMethod returns the appropriate EVA API method based on table
 // Example: "CmfProject" -> "CmfProject.list" or "CmfProject.get" if single is true
 func (qb *QueryBuilder) ToMethod(single bool) (string, error) {
@@ -192,8 +319,6 @@ func (qb *QueryBuilder) ToMethod(single bool) (string, error) {
 	return table + ".list", nil
 }
 
-// Validate checks if query is valid before execution
-// Returns error if query has invalid parameters
 // safeBuilder returns selectBuilder with at least one column.
 // Squirrel requires at least one result column for ToSql();
 // if no columns were specified, "*" is used as a placeholder.
@@ -204,6 +329,27 @@ func (qb *QueryBuilder) safeBuilder() sq.SelectBuilder {
 	return qb.selectBuilder
 }
 
+// entity returns the entity set with From, "" if none.
+func (qb *QueryBuilder) entity() string {
+	sqlStr, _, err := qb.safeBuilder().ToSql()
+	if err != nil {
+		return ""
+	}
+	return extractTableName(sqlStr)
+}
+
+// WithSchema makes Validate check fields, filters and ordering against the
+// entity schema (see SchemaRegistry).
+// Example: qb.WithSchema(schema).Validate()
+func (qb *QueryBuilder) WithSchema(schema *Schema) *QueryBuilder {
+	qb.schema = schema
+	return qb
+}
+
+// Validate checks if query is valid before execution
+// Returns error if query has invalid parameters: a missing From() and, with
+// WithSchema, unknown or non-API fields and ordering by virtual fields
+// (*FieldError, matched by ErrInvalidField).
 func (qb *QueryBuilder) Validate() error {
 	// Check if From() was called
 	sqlStr, _, err := qb.safeBuilder().ToSql()
@@ -215,6 +361,10 @@ func (qb *QueryBuilder) Validate() error {
 		return fmt.Errorf("missing From() clause - specify entity type")
 	}
 
+	if qb.schema != nil {
+		return qb.schema.ValidateQuery(qb)
+	}
+
 	return nil
 }
 
@@ -225,26 +375,24 @@ func (qb *QueryBuilder) String() string {
 		return fmt.Sprintf("QueryBuilder{err=%v}", err)
 	}
 
-	return fmt.Sprintf("QueryBuilder{sql=%q, args=%v, includeArch=%v, noMeta=%v}",
-		sqlStr, args, qb.includeArch, qb.noMeta)
+	return fmt.Sprintf("QueryBuilder{sql=%q, args=%v, where=%v, includeArch=%v, noMeta=%v}",
+		sqlStr, args, qb.where, qb.includeArch, qb.noMeta)
 }
 
 // sqlParts holds parsed SQL components
 type sqlParts struct {
 	fields  []string
 	table   string
-	filters []any
 	orderBy []string
 	limit   uint64
 	offset  uint64
 }
 
-// parseSquirrelSQL converts Squirrel SQL to EVA BQL components
-// This is a simplified parser - production version needs more robust parsing
-func parseSquirrelSQL(sqlStr string, args []any) (*sqlParts, error) {
+// parseSquirrelSQL extracts fields, table, order and window from Squirrel SQL
+// Filters are not part of the SQL, see QueryBuilder.Filter
+func parseSquirrelSQL(sqlStr string) (*sqlParts, error) {
 	parts := &sqlParts{
 		fields:  []string{},
-		filters: []any{},
 		orderBy: []string{},
 	}
 
@@ -262,11 +410,6 @@ func parseSquirrelSQL(sqlStr string, args []any) (*sqlParts, error) {
 	// Extract table name
 	parts.table = extractTableName(sqlStr)
 
-	// Extract WHERE conditions
-	if whereIdx := strings.Index(sqlStr, "WHERE "); whereIdx >= 0 {
-		parts.filters = convertSquirrelFilters(sqlStr[whereIdx:], args)
-	}
-
 	// Extract ORDER BY
 	if orderIdx := strings.Index(sqlStr, "ORDER BY "); orderIdx >= 0 {
 		limitIdx := strings.Index(sqlStr[orderIdx:], " LIMIT ")
@@ -319,146 +462,6 @@ func extractTableName(sqlStr string) string {
 	return strings.TrimSpace(afterFrom[:endIdx])
 }
 
-// convertSquirrelFilters converts SQL WHERE to EVA BQL filters
-// Handles Squirrel's Eq, Gt, Lt, Like, etc.
-func convertSquirrelFilters(whereClause string, args []any) []any {
-	var filters []any
-
-	whereClause = strings.TrimSpace(whereClause)
-	whereClause = strings.TrimPrefix(whereClause, "WHERE ")
-
-	// Simple parser for common patterns, preserving argument order.
-	conditions := splitTopLevelAND(whereClause)
-	argIdx := 0
-
-	for _, cond := range conditions {
-		cond = trimWrappingParens(cond)
-		if cond == "" {
-			continue
-		}
-
-		upperCond := strings.ToUpper(cond)
-
-		// Pattern: field IN (?, ?, ...)
-		if inIdx := strings.Index(upperCond, " IN ("); inIdx >= 0 {
-			fieldName := extractLastWord(cond[:inIdx])
-			openIdx := strings.Index(cond[inIdx:], "(")
-			closeIdx := strings.Index(cond[inIdx:], ")")
-			if openIdx >= 0 && closeIdx > openIdx {
-				inPart := cond[inIdx+openIdx : inIdx+closeIdx+1]
-				placeholders := strings.Count(inPart, "?")
-				values := make([]any, 0, placeholders)
-				for i := 0; i < placeholders && argIdx < len(args); i++ {
-					values = append(values, args[argIdx])
-					argIdx++
-				}
-				if len(values) > 0 {
-					filters = append(filters, []any{fieldName, "IN", values})
-				}
-			}
-
-			continue
-		}
-
-		switch {
-		case strings.Contains(cond, " >= ?"):
-			fieldName := extractLastWord(strings.Split(cond, " >= ?")[0])
-			if argIdx < len(args) {
-				filters = append(filters, []any{fieldName, ">=", args[argIdx]})
-				argIdx++
-			}
-		case strings.Contains(cond, " <= ?"):
-			fieldName := extractLastWord(strings.Split(cond, " <= ?")[0])
-			if argIdx < len(args) {
-				filters = append(filters, []any{fieldName, "<=", args[argIdx]})
-				argIdx++
-			}
-		case strings.Contains(cond, " != ?"):
-			fieldName := extractLastWord(strings.Split(cond, " != ?")[0])
-			if argIdx < len(args) {
-				filters = append(filters, []any{fieldName, "!=", args[argIdx]})
-				argIdx++
-			}
-		case strings.Contains(cond, " = ?"):
-			fieldName := extractLastWord(strings.Split(cond, " = ?")[0])
-			if argIdx < len(args) {
-				filters = append(filters, []any{fieldName, "==", args[argIdx]})
-				argIdx++
-			}
-		case strings.Contains(cond, " > ?"):
-			fieldName := extractLastWord(strings.Split(cond, " > ?")[0])
-			if argIdx < len(args) {
-				filters = append(filters, []any{fieldName, ">", args[argIdx]})
-				argIdx++
-			}
-		case strings.Contains(cond, " < ?"):
-			fieldName := extractLastWord(strings.Split(cond, " < ?")[0])
-			if argIdx < len(args) {
-				filters = append(filters, []any{fieldName, "<", args[argIdx]})
-				argIdx++
-			}
-		case strings.Contains(cond, " LIKE ?"):
-			fieldName := extractLastWord(strings.Split(cond, " LIKE ?")[0])
-			if argIdx < len(args) {
-				filters = append(filters, []any{fieldName, "LIKE", args[argIdx]})
-				argIdx++
-			}
-		}
-	}
-
-	return filters
-}
-
-func splitTopLevelAND(whereClause string) []string {
-	if whereClause == "" {
-		return nil
-	}
-
-	parts := []string{}
-	depth := 0
-	start := 0
-
-	for i := 0; i < len(whereClause); i++ {
-		switch whereClause[i] {
-		case '(':
-			depth++
-		case ')':
-			if depth > 0 {
-				depth--
-			}
-		}
-
-		if depth == 0 && strings.HasPrefix(whereClause[i:], " AND ") {
-			parts = append(parts, strings.TrimSpace(whereClause[start:i]))
-			start = i + len(" AND ")
-			i += len(" AND ") - 1
-		}
-	}
-
-	parts = append(parts, strings.TrimSpace(whereClause[start:]))
-	return parts
-}
-
-func trimWrappingParens(s string) string {
-	s = strings.TrimSpace(s)
-	for strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
-		s = strings.TrimSpace(s[1 : len(s)-1])
-	}
-
-	return s
-}
-
-// extractLastWord extracts the last word from a string (field name)
-func extractLastWord(s string) string {
-	s = strings.TrimSpace(s)
-	words := strings.Fields(s)
-	if len(words) == 0 {
-		return ""
-	}
-
-	return words[len(words)-1]
-}
-
 // parseOrderBy converts SQL ORDER BY to EVA format
 // SQL: "created_at DESC, name ASC" -> EVA: ["-created_at", "name"]
 func parseOrderBy(orderStr string) []string {
@@ -485,7 +488,9 @@ func parseOrderBy(orderStr string) []string {
 
 // Helper functions for common Squirrel patterns with EVA compatibility
 
-// Between creates a range filter for EVA using Squirrel's And combinator
+// Between creates a range filter for EVA using Squirrel's And combinator.
+// m2m relation fields (lists, tags, executors, ...) have no order, so a
+// Between on them fails in ToKwargs; use Contains instead.
 // Example: qb.Where(Between("cmf_created_at", "2024-01-01", "2024-12-31"))
 func Between(col string, from, to any) sq.And {
 	return sq.And{
@@ -493,3 +498,58 @@ func Between(col string, from, to any) sq.And {
 		sq.LtOrEq{col: to},
 	}
 }
+
+// Contains matches rows whose m2m field (executors, tags, lists, ...)
+// includes value
+// Example: qb.Where(Contains(TaskFieldExecutors, "CmfPerson:uuid"))
+func Contains(col string, value any) bql.Cond {
+	return bql.Cond{Field: col, Op: bql.Contains, Value: value}
+}
+
+// NotContains matches rows whose m2m field does not include value
+// Example: qb.Where(NotContains(TaskFieldTags, "TAG-001"))
+func NotContains(col string, value any) bql.Cond {
+	return bql.Cond{Field: col, Op: bql.NotContains, Value: value}
+}
+
+// m2mFields are the m2m relation fields. EVA only tests membership in them
+// (contains, not contains) or their emptiness (== null, != null).
+var m2mFields = map[string]bool{
+	TaskFieldLists:       true,
+	TaskFieldFixVersions: true,
+	TaskFieldTags:        true,
+	TaskFieldExecutors:   true,
+	TaskFieldSpectators:  true,
+	TaskFieldComponents:  true,
+}
+
+// checkRelationRanges rejects range comparisons (>, >=, <, <=, hence
+// Between) on m2m relation fields, which EVA cannot order. Scalar fields
+// reached through a relation ("parent.cmf_created_at") are fine.
+func checkRelationRanges(e bql.Expr) error {
+	switch e := e.(type) {
+	case bql.Cond:
+		switch e.Op {
+		case bql.Gt, bql.GtOrEq, bql.Lt, bql.LtOrEq:
+			if m2mFields[e.Field[strings.LastIndex(e.Field, ".")+1:]] {
+				return fmt.Errorf("%w: %s %s: range on m2m field, use Contains", bql.ErrUnsupported, e.Field, e.Op)
+			}
+		}
+	case bql.And:
+		for _, sub := range e {
+			if err := checkRelationRanges(sub); err != nil {
+				return err
+			}
+		}
+	case bql.Or:
+		for _, sub := range e {
+			if err := checkRelationRanges(sub); err != nil {
+				return err
+			}
+		}
+	case bql.Not:
+		return checkRelationRanges(e.Expr)
+	}
+
+	return nil
+}
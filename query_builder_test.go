@@ -11,6 +11,8 @@ package evateamclient
 import (
 	"testing"
 
+	sq "github.com/Masterminds/squirrel"
+	"github.com/raoptimus/evateamclient.go/bql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -64,106 +66,132 @@ func TestParseOrderBy_SQLFormat_ReturnsEVAFormat(t *testing.T) {
 	}
 }
 
-func TestExtractLastWord_ValidStrings_ReturnsLastWord(t *testing.T) {
+func TestQueryBuilder_Filter_Operators(t *testing.T) {
 	tests := []struct {
-		input    string
-		expected string
+		name     string
+		pred     any
+		expected any
 	}{
-		{"field", "field"},
-		{"WHERE field", "field"},
-		{"AND field2", "field2"},
-		{"  spaced  ", "spaced"},
-		{"", ""},
+		{"equal", sq.Eq{"field": "value"}, []any{"field", "==", "value"}},
+		{"plain map", map[string]any{"field": "value"}, []any{"field", "==", "value"}},
+		{"greater than", sq.Gt{"priority": 3}, []any{"priority", ">", 3}},
+		{"greater or equal", sq.GtOrEq{"priority": 3}, []any{"priority", ">=", 3}},
+		{"less than", sq.Lt{"priority": 5}, []any{"priority", "<", 5}},
+		{"less or equal", sq.LtOrEq{"priority": 5}, []any{"priority", "<=", 5}},
+		{"not equal", sq.NotEq{"status": "CLOSED"}, []any{"status", "!=", "CLOSED"}},
+		{"like", sq.Like{"name": "%test%"}, []any{"name", "LIKE", "%test%"}},
+		{"in", sq.Eq{"lists": []string{"SPR-001", "SPR-002"}}, []any{"lists", "IN", []any{"SPR-001", "SPR-002"}}},
+		{"not in", sq.NotEq{"lists": []string{"SPR-001"}}, []any{"lists", "NOT IN", []any{"SPR-001"}}},
+		{"is null", sq.Eq{"parent_id": nil}, []any{"parent_id", "==", nil}},
+		{"is not null", sq.NotEq{"parent_id": nil}, []any{"parent_id", "!=", nil}},
+		{"not like", sq.NotLike{"name": "%test%"}, []any{"name", "NOT LIKE", "%test%"}},
+		{"ilike", sq.ILike{"name": "%test%"}, []any{"name", "ILIKE", "%test%"}},
+		{"not ilike", sq.NotILike{"name": "%test%"}, []any{"name", "NOT ILIKE", "%test%"}},
+		{"contains", Contains("executors", "CmfPerson:1"), []any{"executors", "contains", "CmfPerson:1"}},
+		{"not contains", NotContains("tags", "TAG-1"), []any{"tags", "not contains", "TAG-1"}},
+		{"between on relation", Between("parent.cmf_created_at", "2026-01-01", "2026-02-01"), []any{
+			[]any{"parent.cmf_created_at", ">=", "2026-01-01"},
+			[]any{"parent.cmf_created_at", "<=", "2026-02-01"},
+		}},
+		{"bql", bql.Cond{Field: "code", Op: bql.Eq, Value: "T-1"}, []any{"code", "==", "T-1"}},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			result := extractLastWord(tt.input)
-			assert.Equal(t, tt.expected, result)
+		t.Run(tt.name, func(t *testing.T) {
+			kwargs, err := NewQueryBuilder().From(EntityTask).Where(tt.pred).ToKwargs()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, kwargs["filter"])
 		})
 	}
 }
 
-func TestConvertSquirrelFilters_EqualityOperator_ReturnsFilter(t *testing.T) {
-	whereClause := "WHERE field = ?"
-	args := []any{"value"}
-
-	filters := convertSquirrelFilters(whereClause, args)
+func TestQueryBuilder_Filter_RangeOnM2MFieldRejected(t *testing.T) {
+	for _, pred := range []any{
+		Between(TaskFieldLists, "SPR-001", "SPR-009"),
+		sq.Gt{TaskFieldTags: "TAG-1"},
+		sq.Or{sq.Eq{"code": "T-1"}, sq.LtOrEq{"parent." + TaskFieldExecutors: "CmfPerson:1"}},
+	} {
+		_, err := NewQueryBuilder().From(EntityTask).Where(pred).ToKwargs()
 
-	assert.Len(t, filters, 1)
-	filter := filters[0].([]any)
-	assert.Equal(t, "field", filter[0])
-	assert.Equal(t, "==", filter[1])
-	assert.Equal(t, "value", filter[2])
+		require.ErrorIs(t, err, bql.ErrUnsupported)
+	}
 }
 
-func TestConvertSquirrelFilters_ComparisonOperators_ReturnsFilters(t *testing.T) {
-	tests := []struct {
-		name        string
-		whereClause string
-		args        []any
-		expectedOp  string
-		expectedVal any
-	}{
-		{"greater than", "WHERE priority > ?", []any{3}, ">", 3},
-		{"greater or equal", "WHERE priority >= ?", []any{3}, ">=", 3},
-		{"less than", "WHERE priority < ?", []any{5}, "<", 5},
-		{"less or equal", "WHERE priority <= ?", []any{5}, "<=", 5},
-		{"not equal", "WHERE status != ?", []any{"CLOSED"}, "!=", "CLOSED"},
-		{"like", "WHERE name LIKE ?", []any{"%test%"}, "LIKE", "%test%"},
-	}
+func TestQueryBuilder_Filter_AndKeepsOrder(t *testing.T) {
+	kwargs, err := NewQueryBuilder().
+		From(EntityTask).
+		Where(sq.Eq{"lists": []string{"SPR-001", "SPR-002"}, "cache_status_type": "OPEN"}).
+		Where(Between("priority", 1, 3)).
+		ToKwargs()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			filters := convertSquirrelFilters(tt.whereClause, tt.args)
-
-			assert.NotEmpty(t, filters, "filter should not be empty for %s", tt.name)
-			if len(filters) > 0 {
-				filter := filters[0].([]any)
-				assert.Equal(t, tt.expectedOp, filter[1])
-				assert.Equal(t, tt.expectedVal, filter[2])
-			}
-		})
-	}
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		[]any{"cache_status_type", "==", "OPEN"},
+		[]any{"lists", "IN", []any{"SPR-001", "SPR-002"}},
+		[]any{"priority", ">=", 1},
+		[]any{"priority", "<=", 3},
+	}, kwargs["filter"])
 }
 
-func TestConvertSquirrelFilters_INOperator_ReturnsFilter(t *testing.T) {
-	whereClause := "WHERE lists IN (?, ?)"
-	args := []any{"SPR-001", "SPR-002"}
+func TestQueryBuilder_Filter_NestedGroups(t *testing.T) {
+	me := "CmfPerson:1"
 
-	filters := convertSquirrelFilters(whereClause, args)
+	kwargs, err := NewQueryBuilder().
+		From(EntityTask).
+		Where(sq.Or{sq.Eq{"cache_status_type": "OPEN"}, sq.Eq{"cache_status_type": "IN_PROGRESS"}}).
+		Where(sq.Or{sq.Eq{"responsible_id": me}, sq.Eq{"cmf_owner_id": me}}).
+		Where(bql.Not{Expr: bql.Or{
+			bql.Cond{Field: "priority", Op: bql.Lt, Value: 2},
+			bql.And{bql.Cond{Field: "system", Op: bql.Eq, Value: true}, bql.Cond{Field: "code", Op: bql.Like, Value: "TMP-%"}},
+		}}).
+		ToKwargs()
 
-	require.Len(t, filters, 1)
-	filter := filters[0].([]any)
-	assert.Equal(t, "lists", filter[0])
-	assert.Equal(t, "IN", filter[1])
-	assert.Equal(t, []any{"SPR-001", "SPR-002"}, filter[2])
+	require.NoError(t, err)
+	assert.Equal(t, []any{
+		[]any{[]any{"cache_status_type", "==", "OPEN"}, "or", []any{"cache_status_type", "==", "IN_PROGRESS"}},
+		[]any{[]any{"responsible_id", "==", me}, "or", []any{"cmf_owner_id", "==", me}},
+		[]any{"not", []any{
+			[]any{"priority", "<", 2},
+			"or",
+			[]any{[]any{"system", "==", true}, []any{"code", "LIKE", "TMP-%"}},
+		}},
+	}, kwargs["filter"])
 }
 
-func TestConvertSquirrelFilters_INAndEquality_UsesArgsInOrder(t *testing.T) {
-	whereClause := "WHERE lists IN (?, ?) AND status = ?"
-	args := []any{"SPR-001", "SPR-002", "OPEN"}
+func TestQueryBuilder_Filter_SingleOr(t *testing.T) {
+	qb := NewQueryBuilder().From(EntityTask).Where(sq.Or{sq.Eq{"code": "T-1"}, sq.Eq{"code": "T-2"}})
+
+	kwargs, err := qb.ToKwargs()
+	require.NoError(t, err)
+	assert.Equal(t, []any{[]any{"code", "==", "T-1"}, "or", []any{"code", "==", "T-2"}}, kwargs["filter"])
+
+	expr, err := qb.Filter()
+	require.NoError(t, err)
+	parsed, err := bql.Parse(kwargs["filter"])
+	require.NoError(t, err)
+	assert.Equal(t, expr, bql.And{parsed})
+}
 
-	filters := convertSquirrelFilters(whereClause, args)
+func TestQueryBuilder_Filter_UnsupportedPredicateFails(t *testing.T) {
+	for _, pred := range []any{"code = 'T-1'", sq.Expr("code = ?", "T-1"), sq.Gt{"parent_id": nil}} {
+		_, err := NewQueryBuilder().From(EntityTask).Where(sq.Eq{"code": "T-1"}).Where(pred).ToKwargs()
 
-	require.Len(t, filters, 2)
+		require.ErrorIs(t, err, bql.ErrUnsupported, "%v", pred)
+	}
+}
 
-	inFilter := filters[0].([]any)
-	assert.Equal(t, "lists", inFilter[0])
-	assert.Equal(t, "IN", inFilter[1])
-	assert.Equal(t, []any{"SPR-001", "SPR-002"}, inFilter[2])
+func TestQueryBuilder_Filter_None(t *testing.T) {
+	kwargs, err := NewQueryBuilder().From(EntityTask).ToKwargs()
 
-	eqFilter := filters[1].([]any)
-	assert.Equal(t, "status", eqFilter[0])
-	assert.Equal(t, "==", eqFilter[1])
-	assert.Equal(t, "OPEN", eqFilter[2])
+	require.NoError(t, err)
+	assert.NotContains(t, kwargs, "filter")
 }
 
 func TestParseSquirrelSQL_ExtractsFields(t *testing.T) {
 	sqlStr := "SELECT id, name, code FROM CmfTask"
-	args := []any{}
 
-	parts, err := parseSquirrelSQL(sqlStr, args)
+	parts, err := parseSquirrelSQL(sqlStr)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"id", "name", "code"}, parts.fields)
@@ -172,9 +200,8 @@ func TestParseSquirrelSQL_ExtractsFields(t *testing.T) {
 
 func TestParseSquirrelSQL_WildcardFields_ReturnsEmpty(t *testing.T) {
 	sqlStr := "SELECT * FROM CmfTask"
-	args := []any{}
 
-	parts, err := parseSquirrelSQL(sqlStr, args)
+	parts, err := parseSquirrelSQL(sqlStr)
 
 	require.NoError(t, err)
 	assert.Empty(t, parts.fields)
@@ -183,9 +210,8 @@ func TestParseSquirrelSQL_WildcardFields_ReturnsEmpty(t *testing.T) {
 
 func TestParseSquirrelSQL_ExtractsLimitOffset(t *testing.T) {
 	sqlStr := "SELECT * FROM CmfTask LIMIT 50 OFFSET 10"
-	args := []any{}
 
-	parts, err := parseSquirrelSQL(sqlStr, args)
+	parts, err := parseSquirrelSQL(sqlStr)
 
 	require.NoError(t, err)
 	assert.Equal(t, uint64(50), parts.limit)
@@ -194,24 +220,21 @@ func TestParseSquirrelSQL_ExtractsLimitOffset(t *testing.T) {
 
 func TestParseSquirrelSQL_ExtractsOrderBy(t *testing.T) {
 	sqlStr := "SELECT * FROM CmfTask ORDER BY priority DESC, name ASC"
-	args := []any{}
 
-	parts, err := parseSquirrelSQL(sqlStr, args)
+	parts, err := parseSquirrelSQL(sqlStr)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"-priority", "name"}, parts.orderBy)
 }
 
 func TestParseSquirrelSQL_CompleteQuery(t *testing.T) {
-	sqlStr := "SELECT id, name FROM CmfTask WHERE status = ? ORDER BY name DESC LIMIT 100 OFFSET 50"
-	args := []any{"OPEN"}
+	sqlStr := "SELECT id, name FROM CmfTask ORDER BY name DESC LIMIT 100 OFFSET 50"
 
-	parts, err := parseSquirrelSQL(sqlStr, args)
+	parts, err := parseSquirrelSQL(sqlStr)
 
 	require.NoError(t, err)
 	assert.Equal(t, []string{"id", "name"}, parts.fields)
 	assert.Equal(t, "CmfTask", parts.table)
-	assert.NotEmpty(t, parts.filters)
 	assert.Equal(t, []string{"-name"}, parts.orderBy)
 	assert.Equal(t, uint64(100), parts.limit)
 	assert.Equal(t, uint64(50), parts.offset)
@@ -0,0 +1,140 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// RateLimit is a token-bucket budget: RPS requests per second on average with
+// bursts of up to Burst requests. A zero RPS means unlimited.
+type RateLimit struct {
+	RPS float64
+	// Burst defaults to RPS rounded up (at least 1).
+	Burst int
+}
+
+// RateLimitConfig configures client-side rate limiting. Every HTTP attempt,
+// including retries, takes a token from the global bucket and from the most
+// specific bucket matching each called method: Methods[method], then
+// Methods[entity], then Reads or Writes. Calls in a batch take one token each.
+//
+// Example:
+//
+//	client, err := evateamclient.NewClient(cfg, evateamclient.WithRateLimit(evateamclient.RateLimitConfig{
+//	  Global:  evateamclient.RateLimit{RPS: 50},
+//	  Reads:   evateamclient.RateLimit{RPS: 20},
+//	  Methods: map[string]evateamclient.RateLimit{"CmfTask.create": {RPS: 5}},
+//	}))
+type RateLimitConfig struct {
+	// Global limits all requests of the client.
+	Global RateLimit
+	// Methods limits by RPC method ("CmfTask.create") or entity ("CmfTask").
+	Methods map[string]RateLimit
+	// Reads limits read methods (*.get, *.list, *.count, ...) not in Methods.
+	Reads RateLimit
+	// Writes limits all other methods not in Methods.
+	Writes RateLimit
+}
+
+// WithRateLimit enables client-side rate limiting. Waiting for a token
+// respects ctx: a call fails early if its deadline would pass first.
+func WithRateLimit(cfg RateLimitConfig) Option {
+	return func(c *Client) {
+		c.rateLimiter = newRateLimiter(cfg)
+	}
+}
+
+type rateLimiter struct {
+	global  *rate.Limiter
+	reads   *rate.Limiter
+	writes  *rate.Limiter
+	methods map[string]*rate.Limiter
+}
+
+func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
+	l := &rateLimiter{
+		global:  newLimiter(cfg.Global),
+		reads:   newLimiter(cfg.Reads),
+		writes:  newLimiter(cfg.Writes),
+		methods: make(map[string]*rate.Limiter, len(cfg.Methods)),
+	}
+	for key, limit := range cfg.Methods {
+		if limiter := newLimiter(limit); limiter != nil {
+			l.methods[key] = limiter
+		}
+	}
+
+	return l
+}
+
+func newLimiter(limit RateLimit) *rate.Limiter {
+	if limit.RPS <= 0 {
+		return nil
+	}
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = max(1, int(math.Ceil(limit.RPS)))
+	}
+
+	return rate.NewLimiter(rate.Limit(limit.RPS), burst)
+}
+
+// limiterFor returns the most specific limiter of a method, or nil.
+func (l *rateLimiter) limiterFor(method string) *rate.Limiter {
+	if limiter, ok := l.methods[method]; ok {
+		return limiter
+	}
+	if entity, _, ok := strings.Cut(method, "."); ok {
+		if limiter, ok := l.methods[entity]; ok {
+			return limiter
+		}
+	}
+	if isIdempotentMethod(method) {
+		return l.reads
+	}
+
+	return l.writes
+}
+
+// wait blocks until a request calling the given methods may be sent. Returns
+// the time spent waiting and whether any limiter applied.
+func (l *rateLimiter) wait(ctx context.Context, methods []string) (time.Duration, bool, error) {
+	if l == nil {
+		return 0, false, nil
+	}
+
+	tokens := make(map[*rate.Limiter]int, len(methods)+1)
+	if l.global != nil {
+		tokens[l.global] = 1
+	}
+	for _, method := range methods {
+		if limiter := l.limiterFor(method); limiter != nil {
+			tokens[limiter]++
+		}
+	}
+	if len(tokens) == 0 {
+		return 0, false, nil
+	}
+
+	startTime := time.Now()
+	for limiter, n := range tokens {
+		if err := limiter.WaitN(ctx, min(n, limiter.Burst())); err != nil {
+			return time.Since(startTime), true, errors.WithMessage(err, "rate limit wait")
+		}
+	}
+
+	return time.Since(startTime), true, nil
+}
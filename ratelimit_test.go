@@ -0,0 +1,144 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitRecorder captures RecordRateLimitWait calls.
+type waitRecorder struct {
+	methods []string
+	waits   []float64
+}
+
+func (r *waitRecorder) RecordRequestDuration(int, string, string, string, float64) {}
+
+func (r *waitRecorder) RecordCircuitState(string, CircuitState) {}
+
+func (r *waitRecorder) RecordRetry(int, string, string, string) {}
+
+func (r *waitRecorder) RecordRateLimitWait(method, _, _ string, wait float64) {
+	r.methods = append(r.methods, method)
+	r.waits = append(r.waits, wait)
+}
+
+func TestRateLimiter_LimiterFor_Precedence(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{
+		Methods: map[string]RateLimit{
+			"CmfTask.create": {RPS: 5},
+			"CmfProject":     {RPS: 2},
+		},
+		Reads:  RateLimit{RPS: 20},
+		Writes: RateLimit{RPS: 10},
+	})
+
+	assert.Same(t, l.methods["CmfTask.create"], l.limiterFor("CmfTask.create"))
+	assert.Same(t, l.methods["CmfProject"], l.limiterFor("CmfProject.list"))
+	assert.Same(t, l.reads, l.limiterFor("CmfTask.list"))
+	assert.Same(t, l.writes, l.limiterFor("CmfTask.update"))
+}
+
+func TestRateLimiter_DefaultBurst(t *testing.T) {
+	assert.Nil(t, newLimiter(RateLimit{}))
+	assert.Equal(t, 1, newLimiter(RateLimit{RPS: 0.5}).Burst())
+	assert.Equal(t, 3, newLimiter(RateLimit{RPS: 2.5}).Burst())
+	assert.Equal(t, 7, newLimiter(RateLimit{RPS: 2, Burst: 7}).Burst())
+}
+
+func TestRateLimiter_Wait_Throttles(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{Global: RateLimit{RPS: 50, Burst: 1}})
+
+	start := time.Now()
+	for range 3 {
+		_, limited, err := l.wait(testCtx, []string{"CmfTask.get"})
+		require.NoError(t, err)
+		assert.True(t, limited)
+	}
+
+	// The first token is free, the other two wait ~20ms each.
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestRateLimiter_Wait_RespectsContextDeadline(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{Global: RateLimit{RPS: 0.1, Burst: 1}})
+	_, _, err := l.wait(testCtx, []string{"CmfTask.get"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(testCtx, 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err = l.wait(ctx, []string{"CmfTask.get"})
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second, "must fail early instead of waiting 10s")
+}
+
+func TestRateLimiter_Nil_NoLimit(t *testing.T) {
+	var l *rateLimiter
+
+	_, limited, err := l.wait(testCtx, []string{"CmfTask.create"})
+
+	require.NoError(t, err)
+	assert.False(t, limited)
+}
+
+func TestClient_RateLimit_RecordsWaitMetric(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, taskGetOKBody)
+	metrics := &waitRecorder{}
+	WithMetrics(metrics)(client)
+	WithRateLimit(RateLimitConfig{Methods: map[string]RateLimit{"CmfTask.create": {RPS: 5}}})(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+	assert.Empty(t, metrics.methods, "reads are not limited")
+
+	WithRateLimit(RateLimitConfig{Reads: RateLimit{RPS: 5}})(client)
+	_, _, err = client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CmfTask.get"}, metrics.methods)
+}
+
+func TestClient_RateLimit_BatchRecordsBatchMethod(t *testing.T) {
+	client := newBatchTestClient(t, &batchServer{})
+	metrics := &waitRecorder{}
+	WithMetrics(metrics)(client)
+	WithRateLimit(RateLimitConfig{Reads: RateLimit{RPS: 1000, Burst: 10}})(client)
+
+	b := client.Batch(testCtx)
+	BatchCount(b, NewQueryBuilder().From(EntityTask))
+	BatchCount(b, NewQueryBuilder().From(EntityProject))
+	require.NoError(t, b.Do())
+
+	assert.Equal(t, []string{BatchMethod}, metrics.methods, "one fixed label, not the joined methods")
+}
+
+func TestClient_RateLimit_ContextCancelled_NoRequest(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, taskGetOKBody)
+	WithRateLimit(RateLimitConfig{Global: RateLimit{RPS: 0.1, Burst: 1}})(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(testCtx, 20*time.Millisecond)
+	defer cancel()
+	_, _, err = client.Task(ctx, "TASK-1", nil)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, mockHTTP.calls)
+}
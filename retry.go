@@ -0,0 +1,189 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+const (
+	defaultRetryMaxAttempts    = 4
+	defaultRetryInitialBackoff = 200 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+	defaultRetryMultiplier     = 2
+	defaultRetryJitter         = 0.2
+)
+
+// idempotentMethodSuffixes are the RPC method names (the part after the
+// entity prefix) that only read data and are therefore safe to repeat.
+var idempotentMethodSuffixes = []string{"get", "list", "count", "macros_page_tree_get"}
+
+// RetryPolicy configures automatic retries of failed RPC calls.
+//
+// Only transport errors and the HTTP statuses listed in RetryableStatuses are
+// retried; RPC errors returned in a 200 OK body never are. By default only
+// read methods (*.get, *.list, *.count, macros_page_tree_get) are retried —
+// non-idempotent methods such as CmfTask.create must be opted in explicitly
+// via ExtraMethods, since repeating them may create duplicates.
+//
+// Example:
+//
+//	policy := evateamclient.DefaultRetryPolicy()
+//	policy.MaxAttempts = 6
+//	client, err := evateamclient.NewClient(cfg, evateamclient.WithRetryPolicy(policy))
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential delay between attempts.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every attempt.
+	Multiplier float64
+	// Jitter randomises every delay by ±Jitter (a fraction in [0, 1]).
+	Jitter float64
+	// RetryableStatuses lists HTTP statuses that trigger a retry.
+	RetryableStatuses []int
+	// ExtraMethods opts non-idempotent RPC methods (e.g. "CmfTask.create")
+	// into retries.
+	ExtraMethods []string
+}
+
+// DefaultRetryPolicy returns a policy retrying read methods up to 4 times on
+// 429/502/503/504 and transport errors, with exponential backoff from 200ms
+// to 5s and 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		Multiplier:     defaultRetryMultiplier,
+		Jitter:         defaultRetryJitter,
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy enables automatic retries of failed RPC calls.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = &p
+	}
+}
+
+// attemptsFor returns how many attempts the policy allows for a request
+// calling the given RPC methods; a batch is retried only if all of its methods
+// may be.
+func (p *RetryPolicy) attemptsFor(methods ...string) int {
+	if p == nil || p.MaxAttempts < 2 {
+		return 1
+	}
+	for _, method := range methods {
+		if !isIdempotentMethod(method) && !slices.Contains(p.ExtraMethods, method) {
+			return 1
+		}
+	}
+
+	return p.MaxAttempts
+}
+
+// shouldRetry reports whether an attempt failed transiently. A cancelled or
+// expired ctx is never retried.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, resp *req.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	return resp != nil && slices.Contains(p.RetryableStatuses, resp.StatusCode)
+}
+
+// backoff returns the delay before the retry following the given attempt
+// (1-based). A server-provided Retry-After takes precedence.
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1) //nolint:gosec // jitter needs no crypto randomness
+	}
+
+	return time.Duration(d)
+}
+
+// isIdempotentMethod reports whether an RPC method (e.g. "CmfTask.list") only
+// reads data.
+func isIdempotentMethod(method string) bool {
+	name := method
+	if idx := strings.LastIndex(method, "."); idx >= 0 {
+		name = method[idx+1:]
+	}
+
+	return slices.Contains(idempotentMethodSuffixes, name)
+}
+
+// retryAfter parses the Retry-After header, either delay-seconds or an
+// HTTP-date. Returns 0 when absent or malformed.
+func retryAfter(resp *req.Response) time.Duration {
+	if resp == nil || resp.Response == nil {
+		return 0
+	}
+
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+
+	return 0
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
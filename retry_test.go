@@ -0,0 +1,253 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taskGetOKBody = `{"jsonrpc":"2.2","result":{"id":"CmfTask:1","code":"TASK-1"}}`
+
+func testRetryPolicy() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = 2 * time.Millisecond
+	return p
+}
+
+// retryRecorder captures RecordRetry calls.
+type retryRecorder struct {
+	retries []int
+}
+
+func (r *retryRecorder) RecordRequestDuration(int, string, string, string, float64) {}
+
+func (r *retryRecorder) RecordRateLimitWait(string, string, string, float64) {}
+
+func (r *retryRecorder) RecordCircuitState(string, CircuitState) {}
+
+func (r *retryRecorder) RecordRetry(status int, _, _, _ string) {
+	r.retries = append(r.retries, status)
+}
+
+func mockResponseWithHeader(statusCode int, body string, header http.Header) *req.Response {
+	resp := mockResponse(statusCode, body)
+	resp.Response.Header = header
+	return resp
+}
+
+func TestClient_Retry_ReadMethodTransientStatus_Retries(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	WithRetryPolicy(testRetryPolicy())(client)
+
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusServiceUnavailable, "unavailable"),
+		mockResponse(http.StatusBadGateway, "bad gateway"),
+		mockResponse(http.StatusOK, taskGetOKBody),
+	}
+
+	task, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "CmfTask:1", task.ID)
+	assert.Equal(t, 3, mockHTTP.callIdx)
+}
+
+func TestClient_Retry_TransportError_Retries(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	WithRetryPolicy(testRetryPolicy())(client)
+
+	mockHTTP.errors = []error{errors.New("connection reset by peer")}
+	mockHTTP.responses = []*req.Response{nil, mockResponse(http.StatusOK, taskGetOKBody)}
+
+	task, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "CmfTask:1", task.ID)
+	assert.Equal(t, 2, mockHTTP.callIdx)
+}
+
+func TestClient_Retry_ExhaustsAttempts_ReturnsLastError(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	policy := testRetryPolicy()
+	policy.MaxAttempts = 2
+	WithRetryPolicy(policy)(client)
+
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusServiceUnavailable, "unavailable"),
+		mockResponse(http.StatusServiceUnavailable, "still unavailable"),
+	}
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "still unavailable")
+	assert.Equal(t, 2, mockHTTP.callIdx)
+}
+
+func TestClient_Retry_NonIdempotentMethod_NotRetried(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	WithRetryPolicy(testRetryPolicy())(client)
+
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusServiceUnavailable, "unavailable"),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":"CmfTask:1"}`),
+	}
+
+	_, err := client.TaskCreate(testCtx, &TaskCreateParams{Name: "n", ProjectID: "p"})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, mockHTTP.callIdx)
+}
+
+func TestClient_Retry_ExtraMethodOptIn_Retried(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	policy := testRetryPolicy()
+	policy.ExtraMethods = []string{"CmfTask.create"}
+	WithRetryPolicy(policy)(client)
+
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusServiceUnavailable, "unavailable"),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":"CmfTask:1"}`),
+		mockResponse(http.StatusOK, taskGetOKBody),
+	}
+
+	task, err := client.TaskCreate(testCtx, &TaskCreateParams{Name: "n", ProjectID: "p"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "CmfTask:1", task.ID)
+	assert.Equal(t, 3, mockHTTP.callIdx)
+}
+
+func TestClient_Retry_NonRetryableStatus_NotRetried(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	WithRetryPolicy(testRetryPolicy())(client)
+
+	mockHTTP.responses = []*req.Response{mockResponse(http.StatusInternalServerError, "boom")}
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, mockHTTP.callIdx)
+}
+
+func TestClient_Retry_NoPolicy_SingleAttempt(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+
+	mockHTTP.responses = []*req.Response{mockResponse(http.StatusServiceUnavailable, "unavailable")}
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, mockHTTP.callIdx)
+}
+
+func TestClient_Retry_RecordsRetryMetric(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	WithRetryPolicy(testRetryPolicy())(client)
+	metrics := &retryRecorder{}
+	client.metrics = metrics
+
+	mockHTTP.responses = []*req.Response{
+		mockResponseWithHeader(http.StatusTooManyRequests, "slow down", http.Header{"Retry-After": {"0"}}),
+		mockResponse(http.StatusOK, taskGetOKBody),
+	}
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{http.StatusTooManyRequests}, metrics.retries)
+}
+
+func TestClient_Retry_ContextCancelledDuringBackoff_ReturnsContextError(t *testing.T) {
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	policy := testRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	WithRetryPolicy(policy)(client)
+
+	mockHTTP.responses = []*req.Response{mockResponse(http.StatusServiceUnavailable, "unavailable")}
+
+	ctx, cancel := context.WithTimeout(testCtx, 10*time.Millisecond)
+	defer cancel()
+
+	_, _, err := client.Task(ctx, "TASK-1", nil)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, mockHTTP.callIdx)
+}
+
+func TestIsIdempotentMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"CmfTask.get", true},
+		{"CmfTask.list", true},
+		{"CmfTimeTrackerHistory.count", true},
+		{"CmfDocument.macros_page_tree_get", true},
+		{"CmfTask.create", false},
+		{"CmfTask.update", false},
+		{"CmfProject.add_executors", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			assert.Equal(t, tt.want, isIdempotentMethod(tt.method))
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff_GrowsExponentiallyAndCaps(t *testing.T) {
+	p := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1, 0))
+	assert.Equal(t, 200*time.Millisecond, p.backoff(2, 0))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3, 0))
+	assert.Equal(t, time.Second, p.backoff(10, 0))
+	assert.Equal(t, 3*time.Second, p.backoff(1, 3*time.Second), "Retry-After takes precedence")
+}
+
+func TestRetryPolicy_Backoff_JitterStaysInRange(t *testing.T) {
+	p := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	for range 100 {
+		d := p.backoff(1, 0)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 150*time.Millisecond)
+	}
+}
+
+func TestRetryAfter_ParsesSecondsAndHTTPDate(t *testing.T) {
+	assert.Equal(t, 7*time.Second, retryAfter(mockResponseWithHeader(http.StatusTooManyRequests, "", http.Header{"Retry-After": {"7"}})))
+	assert.Zero(t, retryAfter(mockResponseWithHeader(http.StatusTooManyRequests, "", http.Header{"Retry-After": {"soon"}})))
+	assert.Zero(t, retryAfter(mockResponse(http.StatusTooManyRequests, "")))
+	assert.Zero(t, retryAfter(nil))
+
+	at := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	d := retryAfter(mockResponseWithHeader(http.StatusServiceUnavailable, "", http.Header{"Retry-After": {at}}))
+	assert.Greater(t, d, 50*time.Second)
+	assert.LessOrEqual(t, d, time.Minute)
+}
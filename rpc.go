@@ -8,7 +8,14 @@
 
 package evateamclient
 
-import "github.com/gofrs/uuid"
+import (
+	"net/http"
+
+	"github.com/gofrs/uuid"
+)
+
+// BatchMethod is the Method of an RPCRequest carrying a JSON-RPC batch.
+const BatchMethod = "batch"
 
 type RPCRequest struct {
 	JSONRPC string      `json:"jsonrpc"`
@@ -16,6 +23,36 @@ type RPCRequest struct {
 	CallID  string      `json:"callid"`
 	Args    interface{} `json:"args,omitempty"`
 	Kwargs  interface{} `json:"kwargs,omitempty"`
+	// Calls holds the queued calls of a batch request (Method == BatchMethod).
+	// A batch is sent as a JSON array of Calls instead of the request itself.
+	Calls []*RPCRequest `json:"-"`
+	// Header holds extra HTTP headers to send with the request; middlewares
+	// may set it.
+	Header http.Header `json:"-"`
+}
+
+// MarshalJSON encodes a batch request as the array of its calls.
+func (r *RPCRequest) MarshalJSON() ([]byte, error) {
+	if len(r.Calls) > 0 {
+		return json.Marshal(r.Calls)
+	}
+
+	type plain RPCRequest
+	return json.Marshal((*plain)(r))
+}
+
+// methods returns the RPC methods invoked by the request.
+func (r *RPCRequest) methods() []string {
+	if len(r.Calls) == 0 {
+		return []string{r.Method}
+	}
+
+	methods := make([]string, 0, len(r.Calls))
+	for _, call := range r.Calls {
+		methods = append(methods, call.Method)
+	}
+
+	return methods
 }
 
 var (
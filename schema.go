@@ -0,0 +1,408 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	encjson "encoding/json"
+	stderrors "errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/raoptimus/evateamclient.go/bql"
+	"github.com/raoptimus/evateamclient.go/models"
+)
+
+// ErrInvalidField is matched via errors.Is by every *FieldError.
+var ErrInvalidField = errors.New("invalid field")
+
+// ErrNoRelation is returned when the schema of an entity has no field
+// referencing the requested one.
+var ErrNoRelation = errors.New("no relation")
+
+// FieldError reports a field a request must not use according to the
+// entity schema.
+type FieldError struct {
+	Entity string
+	Field  string
+	// Reason is e.g. "unknown field" or "read-only field".
+	Reason string
+	// Suggestion is the closest known field of an unknown field, if any.
+	Suggestion string
+}
+
+func (e *FieldError) Error() string {
+	msg := fmt.Sprintf("%s: %s %q", e.Entity, e.Reason, e.Field)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %q?", e.Suggestion)
+	}
+
+	return msg
+}
+
+func (e *FieldError) Unwrap() error {
+	return ErrInvalidField
+}
+
+// Schema is the field metadata of an entity, as sent by the server in the
+// meta of list responses.
+type Schema struct {
+	Entity string
+	// Readonly is set when no field of the entity can be written.
+	Readonly bool
+	Fields   map[string]models.FieldMeta
+}
+
+// NewSchema returns the schema of an entity from the meta of a response.
+func NewSchema(entity string, meta *models.Meta) *Schema {
+	s := &Schema{Entity: entity, Fields: map[string]models.FieldMeta{}}
+	if meta != nil {
+		s.Readonly = meta.Project.Readonly
+		for name, field := range meta.Project.Fields {
+			s.Fields[name] = field
+		}
+	}
+
+	return s
+}
+
+// RelationTo returns the field referencing the target entity (e.g.
+// "CmfCompany"): the one whose meta has target as class_name. With several
+// such fields the first by name is returned.
+func (s *Schema) RelationTo(target string) (string, bool) {
+	var names []string
+	for name, field := range s.Fields {
+		if field.ClassName == target {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	slices.Sort(names)
+
+	return names[0], true
+}
+
+// Field returns the metadata of a field. Nested paths such as
+// "project_id.code" are looked up by their first segment.
+func (s *Schema) Field(name string) (models.FieldMeta, bool) {
+	name, _, _ = strings.Cut(name, ".")
+	field, ok := s.Fields[name]
+
+	return field, ok
+}
+
+// ValidateFields checks that the fields exist and are available in the API.
+func (s *Schema) ValidateFields(fields ...string) error {
+	var errs []error
+	for _, name := range fields {
+		if err := s.checkField(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// ValidateOrderBy checks order_by keys ("name", "-priority"): the fields
+// must be valid and not virtual, since virtual fields are computed and the
+// server cannot sort by them.
+func (s *Schema) ValidateOrderBy(keys ...string) error {
+	var errs []error
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, "-")
+		if err := s.checkField(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if field, _ := s.Field(name); field.Virtual {
+			errs = append(errs, s.fieldError(name, "cannot order by virtual field"))
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// ValidateWrite checks the kwargs of a create or update call: the fields
+// must be valid and writable.
+//
+// Example:
+//
+//	err := schema.ValidateWrite(map[string]any{"name": "New name", "code": "MOB-1"})
+func (s *Schema) ValidateWrite(fields map[string]any) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := s.checkField(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if field, _ := s.Field(name); s.Readonly || (field.Readonly != nil && *field.Readonly) {
+			errs = append(errs, s.fieldError(name, "read-only field"))
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+// ValidateQuery checks the fields, filter and ordering of qb.
+func (s *Schema) ValidateQuery(qb *QueryBuilder) error {
+	sqlStr, _, err := qb.safeBuilder().ToSql()
+	if err != nil {
+		return errors.Wrap(err, "squirrel.ToSql")
+	}
+	parts, err := parseSquirrelSQL(sqlStr)
+	if err != nil {
+		return err
+	}
+	if parts.table != "" && parts.table != s.Entity {
+		return errors.Errorf("schema of %s cannot validate a query on %s", s.Entity, parts.table)
+	}
+	expr, err := qb.Filter()
+	if err != nil {
+		return err
+	}
+
+	return stderrors.Join(
+		s.ValidateFields(parts.fields...),
+		s.ValidateFields(filterFields(expr)...),
+		s.ValidateOrderBy(parts.orderBy...),
+	)
+}
+
+func (s *Schema) checkField(name string) error {
+	field, ok := s.Field(name)
+	if !ok {
+		err := s.fieldError(name, "unknown field")
+		err.Suggestion = s.suggest(name)
+		return err
+	}
+	if !field.APIAllow {
+		return s.fieldError(name, "non-API field")
+	}
+
+	return nil
+}
+
+func (s *Schema) fieldError(name, reason string) *FieldError {
+	return &FieldError{Entity: s.Entity, Field: name, Reason: reason}
+}
+
+// suggest returns the known field closest to name, or "" when none is close
+// enough to be a typo.
+func (s *Schema) suggest(name string) string {
+	name, _, _ = strings.Cut(name, ".")
+	maxDist := max(2, len(name)/3)
+
+	var (
+		best     string
+		bestDist = maxDist + 1
+	)
+	for candidate, field := range s.Fields {
+		if !field.APIAllow {
+			continue
+		}
+		d := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if d < bestDist || (d == bestDist && candidate < best) {
+			best, bestDist = candidate, d
+		}
+	}
+
+	return best
+}
+
+// editDistance is the optimal string alignment distance of a and b: the
+// number of insertions, deletions, substitutions and adjacent
+// transpositions turning a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// rows i-2, i-1 and i of the distance matrix.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return prev[len(rb)]
+}
+
+// filterFields returns the fields used by the conditions of a filter.
+func filterFields(e bql.Expr) []string {
+	var fields []string
+	var walk func(e bql.Expr)
+	walk = func(e bql.Expr) {
+		switch e := e.(type) {
+		case bql.Cond:
+			if !slices.Contains(fields, e.Field) {
+				fields = append(fields, e.Field)
+			}
+		case bql.And:
+			for _, item := range e {
+				walk(item)
+			}
+		case bql.Or:
+			for _, item := range e {
+				walk(item)
+			}
+		case bql.Not:
+			walk(e.Expr)
+		}
+	}
+	walk(e)
+
+	return fields
+}
+
+// WithWriteValidation checks the kwargs of every <Entity>.create and
+// <Entity>.update call, batched ones included, against the schema of the
+// entity before the request is sent: unknown, non-API and read-only fields
+// fail the call with a *FieldError. Schemas are loaded on first use and
+// cached in Client.Schemas.
+//
+// Example:
+//
+//	client, err := evateamclient.NewClient(cfg, evateamclient.WithWriteValidation())
+func WithWriteValidation() Option {
+	return func(c *Client) {
+		c.validateWrites = true
+	}
+}
+
+// Schemas returns the schema registry of the client, used by
+// WithWriteValidation and to resolve relations such as CompanyPersons.
+func (c *Client) Schemas() *SchemaRegistry {
+	return c.schemas
+}
+
+// validateWrite checks a create or update request when WithWriteValidation
+// is set.
+func (c *Client) validateWrite(ctx context.Context, body *RPCRequest) error {
+	if !c.validateWrites {
+		return nil
+	}
+	entity, verb, _ := strings.Cut(body.Method, ".")
+	fields, ok := body.Kwargs.(map[string]any)
+	if !ok || (verb != "create" && verb != "update") {
+		return nil
+	}
+
+	return c.schemas.ValidateWrite(ctx, entity, fields)
+}
+
+// SchemaRegistry loads and caches entity schemas. It is safe for concurrent
+// use.
+//
+// Example:
+//
+//	schemas := evateamclient.NewSchemaRegistry(client)
+//	if err := schemas.Validate(ctx, qb); err != nil {
+//	  return err // e.g. CmfTask: unknown field "nmae", did you mean "name"?
+//	}
+type SchemaRegistry struct {
+	client *Client
+
+	mu      sync.Mutex
+	schemas map[string]*Schema
+}
+
+// NewSchemaRegistry creates a registry loading schemas through the client.
+func NewSchemaRegistry(client *Client) *SchemaRegistry {
+	return &SchemaRegistry{client: client, schemas: map[string]*Schema{}}
+}
+
+// Schema returns the schema of an entity, loading it with a one-row
+// <Entity>.list request on first use.
+func (r *SchemaRegistry) Schema(ctx context.Context, entity string) (*Schema, error) {
+	r.mu.Lock()
+	s, ok := r.schemas[entity]
+	r.mu.Unlock()
+	if ok {
+		return s, nil
+	}
+
+	_, meta, err := Call[encjson.RawMessage](ctx, r.client, entity+".list", nil, map[string]any{
+		"fields": []string{"id"},
+		"slice":  []int{0, 1},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load schema of %s", entity)
+	}
+	if meta == nil || len(meta.Project.Fields) == 0 {
+		return nil, errors.Errorf("failed to load schema of %s: response has no field meta", entity)
+	}
+
+	return r.Store(entity, meta), nil
+}
+
+// Store caches the schema of an entity from the meta of a response, e.g.
+// one returned by a list call made without NoMeta, and returns it.
+func (r *SchemaRegistry) Store(entity string, meta *models.Meta) *Schema {
+	s := NewSchema(entity, meta)
+
+	r.mu.Lock()
+	r.schemas[entity] = s
+	r.mu.Unlock()
+
+	return s
+}
+
+// Invalidate drops the cached schema of an entity, e.g. after custom fields
+// were added.
+func (r *SchemaRegistry) Invalidate(entity string) {
+	r.mu.Lock()
+	delete(r.schemas, entity)
+	r.mu.Unlock()
+}
+
+// Validate checks qb against the schema of its entity.
+func (r *SchemaRegistry) Validate(ctx context.Context, qb *QueryBuilder) error {
+	if err := qb.Validate(); err != nil {
+		return err
+	}
+	s, err := r.Schema(ctx, qb.entity())
+	if err != nil {
+		return err
+	}
+
+	return s.ValidateQuery(qb)
+}
+
+// ValidateWrite checks the kwargs of a create or update call of an entity.
+func (r *SchemaRegistry) ValidateWrite(ctx context.Context, entity string, fields map[string]any) error {
+	s, err := r.Schema(ctx, entity)
+	if err != nil {
+		return err
+	}
+
+	return s.ValidateWrite(fields)
+}
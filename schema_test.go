@@ -0,0 +1,249 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/raoptimus/evateamclient.go/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taskMetaJSON = `{"Project": {"fields": {
+	"id":               {"api_allow": true, "readonly": true},
+	"name":             {"api_allow": true},
+	"code":             {"api_allow": true, "readonly": true},
+	"priority":         {"api_allow": true},
+	"responsible_id":   {"api_allow": true},
+	"cache_status_type":{"api_allow": true, "readonly": true},
+	"cmf_created_at":   {"api_allow": true, "readonly": true},
+	"activity_count":   {"api_allow": true, "virtual": true},
+	"perm_cache":       {"api_allow": false}
+}}}`
+
+func testTaskSchema(t *testing.T) *Schema {
+	t.Helper()
+
+	var meta models.Meta
+	require.NoError(t, json.Unmarshal([]byte(taskMetaJSON), &meta))
+
+	return NewSchema(EntityTask, &meta)
+}
+
+func TestSchema_ValidateQuery_Valid(t *testing.T) {
+	qb := NewQueryBuilder().
+		Select("id", "name", "responsible_id.name").
+		From(EntityTask).
+		Where(sq.Or{sq.Eq{"cache_status_type": "OPEN"}, sq.Gt{"priority": 2}}).
+		OrderBy("-cmf_created_at").
+		WithSchema(testTaskSchema(t))
+
+	assert.NoError(t, qb.Validate())
+}
+
+func TestSchema_ValidateQuery_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		qb       *QueryBuilder
+		expected string
+	}{
+		{
+			name:     "select typo",
+			qb:       NewQueryBuilder().Select("id", "nmae").From(EntityTask),
+			expected: `CmfTask: unknown field "nmae", did you mean "name"?`,
+		},
+		{
+			name:     "select unknown",
+			qb:       NewQueryBuilder().Select("description_html").From(EntityTask),
+			expected: `CmfTask: unknown field "description_html"`,
+		},
+		{
+			name:     "filter non-API field",
+			qb:       NewQueryBuilder().From(EntityTask).Where(sq.Eq{"perm_cache": "x"}),
+			expected: `CmfTask: non-API field "perm_cache"`,
+		},
+		{
+			name:     "order by virtual field",
+			qb:       NewQueryBuilder().From(EntityTask).OrderBy("-activity_count"),
+			expected: `CmfTask: cannot order by virtual field "activity_count"`,
+		},
+		{
+			name:     "order by typo",
+			qb:       NewQueryBuilder().From(EntityTask).OrderBy("priorty"),
+			expected: `CmfTask: unknown field "priorty", did you mean "priority"?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.qb.WithSchema(testTaskSchema(t)).Validate()
+
+			require.ErrorIs(t, err, ErrInvalidField)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestSchema_ValidateQuery_ReportsAllFields(t *testing.T) {
+	qb := NewQueryBuilder().Select("nmae").From(EntityTask).OrderBy("activity_count")
+
+	err := testTaskSchema(t).ValidateQuery(qb)
+
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "nmae", fieldErr.Field)
+	assert.Equal(t, "name", fieldErr.Suggestion)
+	assert.Contains(t, err.Error(), `cannot order by virtual field "activity_count"`)
+}
+
+func TestSchema_ValidateQuery_OtherEntity(t *testing.T) {
+	err := testTaskSchema(t).ValidateQuery(NewQueryBuilder().From(EntityProject))
+
+	assert.EqualError(t, err, "schema of CmfTask cannot validate a query on CmfProject")
+}
+
+func TestSchema_ValidateWrite(t *testing.T) {
+	s := testTaskSchema(t)
+
+	require.NoError(t, s.ValidateWrite(map[string]any{"name": "New", "priority": 3}))
+
+	err := s.ValidateWrite(map[string]any{"name": "New", "code": "MOB-1", "prioity": 3})
+	require.ErrorIs(t, err, ErrInvalidField)
+	assert.EqualError(t, err, `CmfTask: read-only field "code"`+"\n"+
+		`CmfTask: unknown field "prioity", did you mean "priority"?`)
+}
+
+func TestSchema_ValidateWrite_ReadonlyEntity(t *testing.T) {
+	s := testTaskSchema(t)
+	s.Readonly = true
+
+	err := s.ValidateWrite(map[string]any{"name": "New"})
+
+	assert.EqualError(t, err, `CmfTask: read-only field "name"`)
+}
+
+func TestSchema_Suggest_NothingClose(t *testing.T) {
+	assert.Empty(t, testTaskSchema(t).suggest("zzzzzzzz"))
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"name", "name", 0},
+		{"nmae", "name", 1},
+		{"nam", "name", 1},
+		{"names", "name", 1},
+		{"nome", "name", 1},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, editDistance(tt.a, tt.b), "%s -> %s", tt.a, tt.b)
+	}
+}
+
+func TestSchemaRegistry_LoadsOnceAndValidates(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK,
+		`{"jsonrpc": "2.2", "result": [{"id": "CmfTask:1"}], "meta": `+taskMetaJSON+`}`)
+	mockHTTP.urlCheck = func(url string) bool {
+		return assert.Contains(t, url, "m=CmfTask.list")
+	}
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.NotContains(t, string(body), "no_meta")
+	}
+	schemas := NewSchemaRegistry(client)
+
+	err := schemas.Validate(testCtx, NewQueryBuilder().Select("nmae").From(EntityTask))
+	require.ErrorIs(t, err, ErrInvalidField)
+
+	require.NoError(t, schemas.Validate(testCtx, NewQueryBuilder().Select("name").From(EntityTask)))
+	require.NoError(t, schemas.ValidateWrite(testCtx, EntityTask, map[string]any{"name": "x"}))
+	assert.Equal(t, 1, mockHTTP.calls)
+
+	schemas.Invalidate(EntityTask)
+	_, err = schemas.Schema(testCtx, EntityTask)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mockHTTP.calls)
+}
+
+func TestSchemaRegistry_Store(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	schemas := NewSchemaRegistry(client)
+	schemas.Store(EntityTask, &models.Meta{Project: models.ProjectMeta{
+		Fields: map[string]models.FieldMeta{"name": {APIAllow: true}},
+	}})
+
+	s, err := schemas.Schema(testCtx, EntityTask)
+
+	require.NoError(t, err)
+	assert.Contains(t, s.Fields, "name")
+	assert.Zero(t, mockHTTP.calls)
+}
+
+func TestSchemaRegistry_NoFieldMeta(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": [], "meta": {}}`)
+
+	_, err := NewSchemaRegistry(client).Schema(testCtx, EntityTask)
+
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrInvalidField))
+	assert.Contains(t, err.Error(), "no field meta")
+}
+
+func TestSchemaRegistry_Validate_NoFrom(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+
+	err := NewSchemaRegistry(client).Validate(testCtx, NewQueryBuilder().Select("name"))
+
+	assert.ErrorContains(t, err, "missing From()")
+	assert.Zero(t, mockHTTP.calls)
+}
+
+func TestClient_WriteValidation_RejectsBeforeSending(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	WithWriteValidation()(client)
+	var meta models.Meta
+	require.NoError(t, json.Unmarshal([]byte(taskMetaJSON), &meta))
+	client.Schemas().Store(EntityProject, &meta)
+
+	_, err := client.ProjectUpdate(testCtx, "CmfProject:1", map[string]any{"code": "P-2"})
+
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "read-only field", fieldErr.Reason)
+	assert.Zero(t, mockHTTP.calls)
+}
+
+func TestClient_WriteValidation_Batch(t *testing.T) {
+	srv := &batchServer{}
+	client := newBatchTestClient(t, srv)
+	WithWriteValidation()(client)
+	var meta models.Meta
+	require.NoError(t, json.Unmarshal([]byte(taskMetaJSON), &meta))
+	client.Schemas().Store(EntityTask, &meta)
+
+	b := client.Batch(testCtx)
+	bad := BatchUpdate(b, EntityTask, "CmfTask:1", map[string]any{"nmae": "x"})
+	good := BatchUpdate(b, EntityTask, "CmfTask:2", map[string]any{"name": "x"})
+	require.NoError(t, b.Do())
+
+	_, _, err := bad.Result()
+	require.ErrorIs(t, err, ErrInvalidField)
+	_, _, err = good.Result()
+	require.NoError(t, err)
+}
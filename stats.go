@@ -58,29 +58,36 @@ func (c *Client) SprintTasksCount(ctx context.Context, sprintCode string) (int64
 
 // ListTasksCount returns total tasks in list (sprint/release) by list code.
 func (c *Client) ListTasksCount(ctx context.Context, listCode string) (int64, *models.Meta, error) {
-	kwargs := map[string]any{
-		"filter": []any{TaskFieldLists, "contains", listCode},
+	kwargs, err := NewQueryBuilder().
+		From(EntityTask).
+		Where(Contains(TaskFieldLists, listCode)).
+		ToKwargs()
+	if err != nil {
+		return 0, nil, err
 	}
+
 	return c.TasksCount(ctx, kwargs)
 }
 
 // SprintStats retrieves sprint statistics.
 func (c *Client) SprintStats(ctx context.Context, sprintCode string) (*models.SprintStats, error) {
-	tasks, _, err := c.SprintTasks(ctx, sprintCode, []string{TaskFieldCacheStatusType})
+	qb := NewQueryBuilder().
+		From(EntityTask).
+		Where(sq.Eq{TaskFieldLists: []string{sprintCode}})
+	buckets, err := c.GroupCount(ctx, qb, TaskFieldCacheStatusType)
 	if err != nil {
 		return nil, err
 	}
 
 	stats := &models.SprintStats{
-		SprintID:   sprintCode,
-		TotalTasks: len(tasks),
+		SprintID:      sprintCode,
+		TasksByStatus: make(map[string]int, len(buckets)),
 	}
-
-	statusCount := make(map[string]int)
-	for i := range tasks {
-		statusCount[tasks[i].CacheStatusType]++
+	for _, b := range buckets {
+		status, _ := b.Values[TaskFieldCacheStatusType].(string)
+		stats.TasksByStatus[status] += b.Count
+		stats.TotalTasks += b.Count
 	}
-	stats.TasksByStatus = statusCount
 
 	return stats, nil
 }
@@ -174,7 +181,16 @@ func (c *Client) TimeSpentStats(ctx context.Context, params TimeSpentStatsParams
 // - Tasks appeared during sprint are excluded:
 //   - if BaselineTaskIDs provided: only those IDs are counted
 //   - otherwise: task.cmf_created_at must be <= sprint.start_date
-func (c *Client) SprintExecutorsKPI(ctx context.Context, params *SprintExecutorsKPIParams) (*models.SprintExecutorsKPI, error) {
+func (c *Client) SprintExecutorsKPI(
+	ctx context.Context,
+	params *SprintExecutorsKPIParams,
+) (kpi *models.SprintExecutorsKPI, err error) {
+	ctx, span := c.startSpan(ctx, "SprintExecutorsKPI",
+		attrProjectCode.String(params.ProjectCode),
+		attrSprintCode.String(params.SprintCode),
+	)
+	defer func() { endSpan(span, err) }()
+
 	if params.ProjectCode == "" {
 		return nil, errors.New("project_code is required")
 	}
@@ -413,39 +429,32 @@ func (c *Client) resolveTaskTopLoggerDuringSprint(ctx context.Context, taskID st
 	return topPersonID, nil
 }
 
-const timeLogPageSize = 200
+const (
+	timeLogPageSize         = 200
+	timeLogFetchParallelism = 4
+)
 
+// fetchAllProjectTimeLogs fetches all time logs of a project. Projects have
+// tens of thousands of them, so the pages are fetched in parallel.
 func (c *Client) fetchAllProjectTimeLogs(ctx context.Context, params TimeSpentStatsParams) ([]models.TimeLog, error) {
-	var allLogs []models.TimeLog
-
-	for offset := 0; ; offset += timeLogPageSize {
-		filters := [][]any{
-			{"parent.project_id", "==", params.ProjectID},
-		}
-		if params.DateFrom != "" {
-			filters = append(filters, []any{"cmf_created_at", ">=", params.DateFrom})
-		}
-		if params.DateTo != "" {
-			filters = append(filters, []any{"cmf_created_at", "<=", params.DateTo})
-		}
-
-		kwargs := map[string]any{
-			"filter":   filters,
-			"fields":   []string{"id", "time_spent", "cmf_owner_id", "parent", "parent_id", "cmf_created_at"},
-			"order_by": []string{"-cmf_created_at"},
-			"slice":    []int{offset, offset + timeLogPageSize},
-		}
+	qb := NewQueryBuilder().
+		Select("id", "time_spent", "cmf_owner_id", "parent", "parent_id", "cmf_created_at").
+		From(EntityTimeLog).
+		Where(sq.Eq{"parent.project_id": params.ProjectID}).
+		Parallel(timeLogFetchParallelism)
+	if params.DateFrom != "" {
+		qb.Where(sq.GtOrEq{"cmf_created_at": params.DateFrom})
+	}
+	if params.DateTo != "" {
+		qb.Where(sq.LtOrEq{"cmf_created_at": params.DateTo})
+	}
 
-		page, _, err := c.TimeLogs(ctx, kwargs)
+	var allLogs []models.TimeLog
+	for log, err := range c.IterTimeLogs(ctx, qb, PageSize(timeLogPageSize)) {
 		if err != nil {
 			return nil, err
 		}
-
-		allLogs = append(allLogs, page...)
-
-		if len(page) < timeLogPageSize {
-			break
-		}
+		allLogs = append(allLogs, log)
 	}
 
 	return allLogs, nil
@@ -469,16 +478,32 @@ func collectUniquePersonIDs(logs []models.TimeLog) []string {
 	return ids
 }
 
+// fetchPersonNames maps person IDs to names with one list request, chunked
+// by the client when there are many IDs. Unresolved IDs map to themselves.
 func (c *Client) fetchPersonNames(ctx context.Context, ids []string) map[string]string {
 	names := make(map[string]string, len(ids))
-
 	for _, id := range ids {
-		person, _, err := c.Person(ctx, id, []string{"id", "name"})
-		if err != nil {
-			names[id] = id
-			continue
+		names[id] = id
+	}
+	if len(ids) == 0 {
+		return names
+	}
+
+	// The slice covers all IDs: the server's default page could cut off
+	// the names of a large team.
+	qb := NewQueryBuilder().
+		Select(PersonFieldID, PersonFieldName).
+		From(EntityPerson).
+		Where(sq.Eq{PersonFieldID: ids}).
+		Limit(uint64(len(ids)))
+	persons, _, err := c.PersonsList(ctx, qb)
+	if err != nil {
+		return names
+	}
+	for i := range persons {
+		if persons[i].Name != "" {
+			names[persons[i].ID] = persons[i].Name
 		}
-		names[id] = person.Name
 	}
 
 	return names
@@ -181,24 +181,20 @@ func TestClient_TimeSpentStats_Success_ReturnsGroupedReport(t *testing.T) {
 		"meta": {"total": 3}
 	}`
 
-	// Response 2: Person 1
-	person1Resp := `{
+	// Response 2: Persons of both logs in one list request
+	personsResp := `{
 		"jsonrpc": "2.2",
-		"result": {"id": "CmfPerson:person1", "name": "Alice"},
-		"meta": {}
-	}`
-
-	// Response 3: Person 2
-	person2Resp := `{
-		"jsonrpc": "2.2",
-		"result": {"id": "CmfPerson:person2", "name": "Bob"},
+		"result": [
+			{"id": "CmfPerson:person1", "name": "Alice"},
+			{"id": "CmfPerson:person2", "name": "Bob"}
+		],
 		"meta": {}
 	}`
 
 	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": 3}`),
 		mockResponse(http.StatusOK, timeLogsResp),
-		mockResponse(http.StatusOK, person1Resp),
-		mockResponse(http.StatusOK, person2Resp),
+		mockResponse(http.StatusOK, personsResp),
 	}
 
 	stats, err := client.TimeSpentStats(testCtx, TimeSpentStatsParams{
@@ -227,17 +223,29 @@ func TestClient_TimeSpentStats_Success_ReturnsGroupedReport(t *testing.T) {
 	assert.Len(t, stats.Persons[1].Tasks, 1)
 }
 
+func TestClient_FetchPersonNames_SliceCoversAllIDs(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK,
+		`{"jsonrpc": "2.2", "result": [{"id": "CmfPerson:1", "name": "Alice"}]}`)
+	mockHTTP.bodyCheck = func(body []byte) bool {
+		return assert.Contains(t, string(body), `"slice":[0,3]`)
+	}
+
+	names := client.fetchPersonNames(testCtx, []string{"CmfPerson:1", "CmfPerson:2", "CmfPerson:3"})
+
+	assert.Equal(t, map[string]string{
+		"CmfPerson:1": "Alice",
+		"CmfPerson:2": "CmfPerson:2",
+		"CmfPerson:3": "CmfPerson:3",
+	}, names)
+}
+
 func TestClient_TimeSpentStats_EmptyResult_ReturnsEmptyReport(t *testing.T) {
 	client, mockHTTP := newTestClientWithSequentialMock(t)
 
-	emptyResp := `{
-		"jsonrpc": "2.2",
-		"result": [],
-		"meta": {"total": 0}
-	}`
-
+	// Nothing to fetch after a zero count.
 	mockHTTP.responses = []*req.Response{
-		mockResponse(http.StatusOK, emptyResp),
+		mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": 0}`),
 	}
 
 	stats, err := client.TimeSpentStats(testCtx, TimeSpentStatsParams{
@@ -249,6 +257,7 @@ func TestClient_TimeSpentStats_EmptyResult_ReturnsEmptyReport(t *testing.T) {
 	assert.Equal(t, "CmfProject:proj1", stats.ProjectID)
 	assert.Empty(t, stats.Persons)
 	assert.Equal(t, 0, stats.GrandTotalTime)
+	assert.Equal(t, 1, mockHTTP.callIdx)
 }
 
 func TestClient_TimeSpentStats_TimeLogsFetchError_ReturnsError(t *testing.T) {
@@ -269,14 +278,8 @@ func TestClient_TimeSpentStats_TimeLogsFetchError_ReturnsError(t *testing.T) {
 func TestClient_TimeSpentStats_WithDateFilter_PassesDatesToAPI(t *testing.T) {
 	client, mockHTTP := newTestClientWithSequentialMock(t)
 
-	timeLogsResp := `{
-		"jsonrpc": "2.2",
-		"result": [],
-		"meta": {"total": 0}
-	}`
-
 	mockHTTP.responses = []*req.Response{
-		mockResponse(http.StatusOK, timeLogsResp),
+		mockResponse(http.StatusOK, `{"jsonrpc": "2.2", "result": 0}`),
 	}
 	mockHTTP.bodyCheck = func(body []byte) bool {
 		s := string(body)
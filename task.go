@@ -334,17 +334,12 @@ func (c *Client) PersonTasksAsExecutor(
 	userID string,
 	fields []string,
 ) ([]models.TaskBrowse, *models.Meta, error) {
-	if len(fields) == 0 {
-		fields = DefaultTaskListFields
-	}
-
-	// Note: "contains" operator is EVA-specific, not standard SQL
-	kwargs := map[string]any{
-		"filter": []any{TaskFieldExecutors, "contains", userID},
-		"fields": fields,
-	}
+	qb := NewQueryBuilder().
+		Select(fields...).
+		From(EntityTask).
+		Where(Contains(TaskFieldExecutors, userID))
 
-	return c.Tasks(ctx, kwargs)
+	return c.TasksList(ctx, qb)
 }
 
 // PersonProjectTasks retrieves user's tasks as responsible in specific project
@@ -507,6 +502,10 @@ func (c *Client) TaskCreate(
 // read always, and one extra write only when a reset is detected. Tasks without
 // an epic are left untouched.
 //
+// The pre-read, update, re-fetch and epic restore show up as child spans.
+// The body stays in TaskUpdate itself, so that the function label of its
+// metrics and logs is TaskUpdate.
+//
 // Example:
 //
 //	updates := map[string]any{
@@ -518,7 +517,10 @@ func (c *Client) TaskUpdate(
 	ctx context.Context,
 	taskID string,
 	updates map[string]any,
-) (*models.Task, error) {
+) (result *models.Task, err error) {
+	ctx, span := c.startSpan(ctx, "TaskUpdate", attrTaskID.String(taskID))
+	defer func() { endSpan(span, err) }()
+
 	if taskID == "" {
 		return nil, errors.New("taskID is required")
 	}
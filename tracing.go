@@ -0,0 +1,142 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TracerName is the instrumentation scope name of the client spans.
+const TracerName = "github.com/raoptimus/evateamclient.go"
+
+// Span attribute keys.
+const (
+	attrRPCSystem        = attribute.Key("rpc.system")
+	attrRPCService       = attribute.Key("rpc.service")
+	attrRPCMethod        = attribute.Key("rpc.method")
+	attrRPCCallID        = attribute.Key("rpc.jsonrpc.request_id")
+	attrRPCErrorCode     = attribute.Key("rpc.jsonrpc.error_code")
+	attrHTTPStatusCode   = attribute.Key("http.response.status_code")
+	attrRequestBodySize  = attribute.Key("http.request.body.size")
+	attrResponseBodySize = attribute.Key("http.response.body.size")
+	attrEntity           = attribute.Key("eva.entity")
+	attrBatchSize        = attribute.Key("eva.batch.size")
+	attrTaskID           = attribute.Key("eva.task.id")
+	attrProjectCode      = attribute.Key("eva.project.code")
+	attrSprintCode       = attribute.Key("eva.sprint.code")
+)
+
+// WithTracerProvider enables OpenTelemetry tracing: a client span per RPC call
+// and parent spans for composite operations such as TaskUpdate. The W3C trace
+// context is propagated to the server in the traceparent/tracestate headers.
+//
+// Example:
+//
+//	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+//	client, err := evateamclient.NewClient(cfg, evateamclient.WithTracerProvider(tp))
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(TracerName)
+	}
+}
+
+var traceContext = propagation.TraceContext{}
+
+// startSpan starts an internal span for a composite operation. Without a
+// tracer provider it returns a no-op span.
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, noop.Span{}
+	}
+
+	return c.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingMiddleware opens a client span per RPC call and injects the trace
+// context into the request headers.
+func (c *Client) tracingMiddleware() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
+			ctx, span := c.tracer.Start(ctx, req.Method,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(rpcAttributes(req)...),
+			)
+			defer span.End()
+
+			if span.IsRecording() {
+				if reqBody, err := json.Marshal(req); err == nil {
+					span.SetAttributes(attrRequestBodySize.Int(len(reqBody)))
+				}
+			}
+
+			if req.Header == nil {
+				req.Header = make(http.Header)
+			}
+			traceContext.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+
+			span.SetAttributes(
+				attrHTTPStatusCode.Int(resp.StatusCode),
+				attrResponseBodySize.Int(len(resp.Body)),
+			)
+			if resp.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+				return resp, nil
+			}
+			if len(req.Calls) == 0 {
+				var rpcErr rpcErrorResponse
+				if json.Unmarshal(resp.Body, &rpcErr) == nil && rpcErr.Error != nil {
+					span.SetAttributes(attrRPCErrorCode.Int(rpcErr.Error.Code))
+					span.SetStatus(codes.Error, rpcErr.Error.Message)
+				}
+			}
+
+			return resp, nil
+		}
+	}
+}
+
+func rpcAttributes(req *RPCRequest) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attrRPCSystem.String("jsonrpc"),
+		attrRPCMethod.String(req.Method),
+		attrRPCCallID.String(req.CallID),
+	}
+	if len(req.Calls) > 0 {
+		return append(attrs, attrBatchSize.Int(len(req.Calls)))
+	}
+	if entity, _, ok := strings.Cut(req.Method, "."); ok {
+		attrs = append(attrs, attrRPCService.String(entity), attrEntity.String(entity))
+	}
+
+	return attrs
+}
@@ -0,0 +1,146 @@
+/**
+ * This file is part of the raoptimus/evateamclient.go library
+ *
+ * @copyright Copyright (c) Evgeniy Urvantsev
+ * @license https://github.com/raoptimus/evateamclient.go/blob/master/LICENSE.md
+ * @link https://github.com/raoptimus/evateamclient.go
+ */
+
+package evateamclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imroc/req/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedTestClient(t *testing.T) (*Client, *sequentialMockHTTPClient, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	client, mockHTTP := newTestClientWithSequentialMock(t)
+	WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))(client)
+
+	return client, mockHTTP, recorder
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func TestTracing_SpanPerRPCCall(t *testing.T) {
+	client, mockHTTP, recorder := newTracedTestClient(t)
+	mockHTTP.responses = []*req.Response{mockResponse(http.StatusOK, taskGetOKBody)}
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "CmfTask.get", span.Name())
+	assert.Equal(t, "CmfTask.get", spanAttr(span, attrRPCMethod).AsString())
+	assert.Equal(t, EntityTask, spanAttr(span, attrEntity).AsString())
+	assert.NotEmpty(t, spanAttr(span, attrRPCCallID).AsString())
+	assert.Equal(t, int64(http.StatusOK), spanAttr(span, attrHTTPStatusCode).AsInt64())
+	assert.Positive(t, spanAttr(span, attrRequestBodySize).AsInt64())
+	assert.Equal(t, int64(len(taskGetOKBody)), spanAttr(span, attrResponseBodySize).AsInt64())
+	assert.Equal(t, codes.Unset, span.Status().Code)
+}
+
+func TestTracing_RPCErrorCode(t *testing.T) {
+	client, mockHTTP, recorder := newTracedTestClient(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","error":{"code":-32602,"message":"bad kwargs"}}`),
+	}
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, int64(-32602), spanAttr(spans[0], attrRPCErrorCode).AsInt64())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestTracing_TaskUpdate_NestsRPCSpans(t *testing.T) {
+	client, mockHTTP, recorder := newTracedTestClient(t)
+	mockHTTP.responses = []*req.Response{
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfTask:123","epic_id":""}}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":"CmfTask:123"}`),
+		mockResponse(http.StatusOK, `{"jsonrpc":"2.2","result":{"id":"CmfTask:123"}}`),
+	}
+
+	_, err := client.TaskUpdate(testCtx, "CmfTask:123", map[string]any{"name": "x"})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	parent := spans[len(spans)-1]
+	assert.Equal(t, "TaskUpdate", parent.Name())
+	assert.Equal(t, "CmfTask:123", spanAttr(parent, attrTaskID).AsString())
+
+	names := make([]string, 0, 3)
+	for _, span := range spans[:3] {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		names = append(names, span.Name())
+	}
+	assert.Equal(t, []string{"CmfTask.get", "CmfTask.update", "CmfTask.get"}, names)
+}
+
+func TestTracing_PropagatesTraceContext(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_, _ = w.Write([]byte(taskGetOKBody))
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	client, err := NewClient(&Config{BaseURL: srv.URL, APIToken: "test-token"},
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	sc := spans[0].SpanContext()
+	assert.Equal(t, "00-"+sc.TraceID().String()+"-"+sc.SpanID().String()+"-01", traceparent)
+}
+
+func TestTracing_Disabled_NoHeaders(t *testing.T) {
+	client, mockHTTP := newTestClient(t)
+	mockHTTP.response = mockResponse(http.StatusOK, taskGetOKBody)
+
+	var seen http.Header
+	WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *RPCRequest) (*RPCResponse, error) {
+			resp, err := next(ctx, req)
+			seen = req.Header
+			return resp, err
+		}
+	})(client)
+
+	_, _, err := client.Task(testCtx, "TASK-1", nil)
+	require.NoError(t, err)
+	assert.Empty(t, seen.Get("traceparent"))
+}